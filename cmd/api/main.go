@@ -12,9 +12,10 @@
 package main
 
 import (
-	"fmt"
+	"context"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -22,6 +23,7 @@ import (
 	"gorm.io/gorm"
 
 	_ "github.com/steven230500/hypeatlas-api/docs"
+	"github.com/steven230500/hypeatlas-api/domain/entities"
 
 	relaysvc "github.com/steven230500/hypeatlas-api/modules/relay/domain/service"
 	relayhttp "github.com/steven230500/hypeatlas-api/modules/relay/infra/http"
@@ -30,9 +32,14 @@ import (
 	signalhttp "github.com/steven230500/hypeatlas-api/modules/signal/infra/http"
 	signalrepo "github.com/steven230500/hypeatlas-api/modules/signal/infra/repository"
 
+	"github.com/steven230500/hypeatlas-api/providers/riot"
 	sharedgorm "github.com/steven230500/hypeatlas-api/shared/db"
 	sharedhttp "github.com/steven230500/hypeatlas-api/shared/http"
+	"github.com/steven230500/hypeatlas-api/shared/ingestion"
+	"github.com/steven230500/hypeatlas-api/shared/lifecycle"
 	"github.com/steven230500/hypeatlas-api/shared/logger"
+	"github.com/steven230500/hypeatlas-api/shared/metrics"
+	"github.com/steven230500/hypeatlas-api/shared/scheduler"
 )
 
 func main() {
@@ -70,11 +77,18 @@ func main() {
 	}
 	signalRepo := signalrepo.New(gdb)
 	log.Info().Msg("signal repository: postgres")
-	signalRouter := signalhttp.NewRouter(signalRepo)
+	// Un solo servicio de Riot (y rate limiter) para rutas, ingesta y health
+	var riotSvc *riot.Service
+	if key := os.Getenv("RIOT_API_KEY"); key != "" {
+		riotSvc = riot.NewService(key, signalRepo)
+	}
+	signalRouter := signalhttp.NewRouter(signalRepo, riotSvc)
 
 	// Router raíz
 	r := sharedhttp.NewRouter()
-	r.Use(middleware.Logger) // log de cada request
+	r.Use(middleware.RequestID) // X-Request-Id entrante o uno nuevo
+	r.Use(logger.Middleware)    // logger con request_id en el contexto + log de cada request
+	r.Use(metrics.Middleware)   // latencia y status por patrón de ruta
 
 	// 404 en JSON (para que jq no muera con "404" texto)
 	r.NotFound(func(w http.ResponseWriter, _ *http.Request) {
@@ -83,13 +97,44 @@ func main() {
 		_, _ = w.Write([]byte(`{"error":"not_found","where":"root"}`))
 	})
 
-	// Health raíz
-	// @Summary Healthcheck
-	// @Success 200 {string} string "ok"
+	// Health: liveness (proceso vivo) y readiness (DB y proveedores)
+	health := lifecycle.NewHealth(2 * time.Second)
+	health.Add(lifecycle.Check{Name: "db", Critical: true, Run: func(ctx context.Context) error {
+		return sharedgorm.Ping(ctx, gdb)
+	}})
+	if riotSvc != nil {
+		riotClient := riotSvc.Client()
+		health.Add(lifecycle.Check{Name: "riot", TTL: 30 * time.Second, Run: func(ctx context.Context) error {
+			_, err := riotClient.GetLatestVersion(ctx)
+			return err
+		}})
+	}
+
+	// @Summary Liveness
+	// @Description 200 while the process is up; does not check dependencies
+	// @Success 200 {object} map[string]interface{} "status ok"
+	// @Router /livez [get]
+	r.Get("/livez", health.Live)
+	// @Summary Healthcheck (alias of /livez)
+	// @Success 200 {object} map[string]interface{} "status ok"
 	// @Router /healthz [get]
-	r.Get("/healthz", func(w http.ResponseWriter, _ *http.Request) {
-		_, _ = w.Write([]byte("ok"))
-	})
+	r.Get("/healthz", health.Live)
+	// @Summary Readiness
+	// @Description Checks the database (critical) and the Riot API (degraded only). 503 when a critical check fails or the server is shutting down.
+	// @Success 200 {object} map[string]interface{} "ok or degraded, with per-check status and latency"
+	// @Failure 503 {object} map[string]interface{} "unavailable or draining"
+	// @Router /readyz [get]
+	r.Get("/readyz", health.Ready)
+
+	// Métricas Prometheus (HTTP, DB, proveedores y co-streams en vivo por juego)
+	if err := metrics.RegisterLive(relayRepo.LiveStatsByGame); err != nil {
+		log.Error().Err(err).Msg("live co-stream metrics disabled")
+	}
+	// @Summary Prometheus metrics
+	// @Produce plain
+	// @Success 200 {string} string "metrics in Prometheus text format"
+	// @Router /metrics [get]
+	r.Handle("/metrics", metrics.Handler())
 
 	// -- verificación Riot Games --
 	// @Summary Riot Games domain verification
@@ -118,6 +163,23 @@ func main() {
 	// ⬇️ Prefijo final: /v1/signal/...
 	v1.Mount("/signal", signalRouter)
 
+	// Ingesta protegida por X-API-Key: /v1/ingest/signal/... y /v1/ingest/relay/...
+	// Cada petición de ingesta deja una fila en app.ingestion_logs (source api)
+	ingestAudit := ingestion.NewStore(gdb)
+	v1.Route("/ingest", func(r chi.Router) {
+		r.Use(sharedhttp.ApiKeyMiddleware)
+		r.Use(ingestion.Middleware(ingestAudit, entities.IngestionSourceAPI))
+		r.Mount("/signal", signalhttp.NewIngestRouter(gdb, riotSvc))
+		r.Route("/relay", relayhttp.NewIngest(relayRepo).Register)
+	})
+
+	// Administración: jobs del worker (/v1/admin/jobs) y auditoría de ingestas (/v1/admin/ingestion)
+	v1.Route("/admin", func(r chi.Router) {
+		r.Use(sharedhttp.ApiKeyMiddleware)
+		scheduler.NewHandler(scheduler.NewGormStore(gdb)).Register(r)
+		ingestion.NewHandler(ingestAudit).Register(r)
+	})
+
 	// Health duplicado en el v1 para validar prefijo
 	v1.Get("/signal/riot/_health", func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -127,12 +189,26 @@ func main() {
 	// Montar /v1 en el router raíz
 	r.Mount("/v1", v1)
 
-	// DUMP del router raíz (paths COMPLETOS)
+	// Rutas registradas (paths completos), visibles con LOG_LEVEL=debug
 	_ = chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
-		fmt.Println("[ROOT]", method, route)
+		log.Debug().Str("method", method).Str("route", route).Msg("route registered")
 		return nil
 	})
 
+	// SIGINT/SIGTERM: /readyz pasa a 503, se espera SHUTDOWN_DRAIN_DELAY para que el balanceador
+	// lo note, se drenan las peticiones en curso y luego se cierra la DB
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
+	srv := lifecycle.NewServer(":"+port, r)
 	log.Info().Str("port", port).Msg("api up")
-	_ = http.ListenAndServe(":"+port, r)
+	if err := lifecycle.Serve(ctx, srv,
+		lifecycle.EnvDuration("SHUTDOWN_DRAIN_DELAY", 5*time.Second),
+		lifecycle.EnvDuration("SHUTDOWN_TIMEOUT", 25*time.Second),
+		health.SetDraining); err != nil {
+		log.Error().Err(err).Msg("http server stopped with error")
+	}
+	if err := sharedgorm.Close(gdb); err != nil {
+		log.Error().Err(err).Msg("db close failed")
+	}
+	log.Info().Msg("api stopped")
 }
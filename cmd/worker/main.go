@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -10,73 +12,169 @@ import (
 
 	"github.com/rs/zerolog/log"
 
+	"github.com/steven230500/hypeatlas-api/domain/entities"
 	relayout "github.com/steven230500/hypeatlas-api/modules/relay/domain/ports/out"
 	relayrepo "github.com/steven230500/hypeatlas-api/modules/relay/infra/repository"
-	signalout "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	signalsvc "github.com/steven230500/hypeatlas-api/modules/signal/domain/service"
 	signalrepo "github.com/steven230500/hypeatlas-api/modules/signal/infra/repository"
+	riotprov "github.com/steven230500/hypeatlas-api/providers/riot"
 	twitchprov "github.com/steven230500/hypeatlas-api/providers/twitch"
 	sharedgorm "github.com/steven230500/hypeatlas-api/shared/db"
+	"github.com/steven230500/hypeatlas-api/shared/ingestion"
+	"github.com/steven230500/hypeatlas-api/shared/lifecycle"
+	"github.com/steven230500/hypeatlas-api/shared/logger"
+	"github.com/steven230500/hypeatlas-api/shared/metrics"
+	"github.com/steven230500/hypeatlas-api/shared/scheduler"
 )
 
-type CompSample struct {
-	Game, Region, League, Patch, Map, Side string
-	Slots                                  map[string]any
-	Pick, Win, Delta                       *float64
-}
-
 func main() {
+	logger.New()
 	if os.Getenv("POSTGRES_URL") == "" {
 		log.Fatal().Msg("POSTGRES_URL missing")
 	}
 	db := sharedgorm.Connect()
-	defer func() {
-		sqlDB, _ := db.DB()
-		sqlDB.Close()
-	}()
 
 	relayRepo := relayrepo.New(db)
 	signalRepo := signalrepo.New(db)
+	audit := ingestion.NewStore(db)
 
 	// Twitch config
 	twID := os.Getenv("TWITCH_CLIENT_ID")
 	twSec := os.Getenv("TWITCH_SECRET")
-	twHandles := strings.Split(strings.ToLower(os.Getenv("TWITCH_HANDLES")), ",")
 	var tw *twitchprov.Client
 	if twID != "" && twSec != "" {
 		tw = twitchprov.New(twID, twSec)
 	}
 
-	interval := 30 * time.Second
-	if v := os.Getenv("WORKER_INTERVAL_SEC"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			interval = time.Duration(n) * time.Second
+	// SIGINT/SIGTERM cancela ctx: el scheduler deja de lanzar jobs y espera a los que corren
+	ctx, stop := lifecycle.SignalContext(context.Background())
+	defer stop()
+	sched := scheduler.New(scheduler.NewGormStore(db), time.Duration(envInt("SCHEDULER_POLL_SEC", 5))*time.Second)
+	sched.SetGracePeriod(lifecycle.EnvDuration("WORKER_SHUTDOWN_GRACE", time.Minute))
+	// Con varias réplicas cada job corre en una sola (advisory lock de Postgres por job)
+	if locks, err := strconv.ParseBool(os.Getenv("WORKER_JOB_LOCKS")); err != nil || locks {
+		sqlDB, err := db.DB()
+		if err != nil {
+			log.Fatal().Err(err).Msg("db pool unavailable")
+		}
+		sched.SetLocker(scheduler.NewPgLocker(sqlDB))
+	}
+
+	// Relay: co-streams de Twitch y limpieza de los que dejaron de emitir
+	if tw != nil {
+		addJob(sched, "twitch_poll", time.Duration(envInt("WORKER_INTERVAL_SEC", 30))*time.Second, 2*time.Minute,
+			func(ctx context.Context) error { return pollTwitch(ctx, relayRepo, tw, audit) })
+	}
+	addJob(sched, "stale_cleanup", time.Minute, 30*time.Second,
+		func(ctx context.Context) error { return cleanupStale(ctx, relayRepo) })
+	if days := retentionDays(); days > 0 {
+		addJob(sched, "ingestion_retention", 24*time.Hour, 30*time.Minute,
+			func(ctx context.Context) error { return purgeIngestionLogs(ctx, audit, days) })
+	} else {
+		log.Info().Msg("INGESTION_RETENTION_DAYS=0: ingestion logs are kept forever")
+	}
+
+	// Rollups: comps de LoL e impacto de parches de los últimos parches
+	impact := signalsvc.NewImpactService(signalRepo)
+	lolComps := signalsvc.NewLolCompService(signalRepo)
+	addJob(sched, "metric_rollups", time.Duration(envInt("IMPACT_INTERVAL_MIN", 60))*time.Minute, 15*time.Minute,
+		func(ctx context.Context) error { return rollupMetrics(ctx, lolComps, impact) })
+
+	// Riot: cada job en su propio schedule porque el rate limit los hace lentos
+	if key := os.Getenv("RIOT_API_KEY"); key != "" {
+		riotSvc := riotprov.NewService(key, signalRepo)
+		ingest := signalsvc.NewMatchIngestService(signalRepo, riotSvc)
+		metaGame := signalsvc.NewMetaGameService(signalRepo, riotSvc)
+		mastery := signalsvc.NewMasteryService(signalRepo, riotSvc)
+		valComps := signalsvc.NewValCompService(signalRepo, riotSvc)
+		platforms := riotPlatforms()
+
+		addJob(sched, "riot_sync", 6*time.Hour, 10*time.Minute, func(ctx context.Context) error {
+			err := riotSvc.SyncPatches(ctx)
+			audit.Record(ctx, entities.IngestionSourceRiot, entities.IngestionEntityPatch, "lol:ddragon", err)
+			return err
+		})
+		addJob(sched, "riot_match_ingest", time.Duration(envInt("RIOT_MATCH_INTERVAL_MIN", 30))*time.Minute, 25*time.Minute,
+			func(ctx context.Context) error {
+				return ingestRiotMatches(ctx, ingest, lolComps, impact, audit, platforms)
+			})
+		addJob(sched, "rotation_snapshot", time.Duration(envInt("RIOT_SNAPSHOT_INTERVAL_MIN", 60))*time.Minute, 20*time.Minute,
+			func(ctx context.Context) error { return snapshotMetaGame(ctx, metaGame, platforms) })
+		if len(platforms) > 1 {
+			addJob(sched, "cross_region_report", time.Duration(envInt("RIOT_SNAPSHOT_INTERVAL_MIN", 60))*time.Minute, 20*time.Minute,
+				func(ctx context.Context) error { return crossRegionReport(ctx, metaGame, platforms) })
 		}
+		addJob(sched, "mastery_aggregation", time.Duration(envInt("RIOT_MASTERY_INTERVAL_MIN", 360))*time.Minute, time.Hour,
+			func(ctx context.Context) error { return aggregateMastery(ctx, mastery, platforms) })
+		addJob(sched, "val_comps", time.Duration(envInt("RIOT_VAL_INTERVAL_MIN", 60))*time.Minute, 30*time.Minute,
+			func(ctx context.Context) error { return ingestValComps(ctx, valComps, impact, audit) })
 	}
 
-	ctx := context.Background()
-	ticker := time.NewTicker(interval)
-	defer ticker.Stop()
+	// Métricas Prometheus del worker (jobs, DB y proveedores); WORKER_METRICS_ADDR=off lo desactiva
+	addr := os.Getenv("WORKER_METRICS_ADDR")
+	if addr == "" {
+		addr = ":9091"
+	}
+	if addr != "off" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", metrics.Handler())
+		go func() {
+			if err := lifecycle.Serve(ctx, lifecycle.NewServer(addr, mux), 0, 5*time.Second, nil); err != nil {
+				log.Error().Err(err).Str("addr", addr).Msg("metrics listener failed")
+			}
+		}()
+	}
 
-	log.Info().Dur("interval", interval).Msg("worker started")
+	log.Info().Strs("jobs", sched.Jobs()).Str("instance", sched.Instance()).Msg("worker started")
+	runErr := sched.Run(ctx)
 
-	runOnce(ctx, relayRepo, signalRepo, tw, twHandles)
-	for range ticker.C {
-		runOnce(ctx, relayRepo, signalRepo, tw, twHandles)
+	// La DB se cierra después de que terminaron los jobs
+	if err := sharedgorm.Close(db); err != nil {
+		log.Error().Err(err).Msg("db close failed")
+	}
+	if runErr != nil {
+		log.Fatal().Err(runErr).Msg("scheduler failed")
 	}
+	log.Info().Msg("worker stopped")
 }
 
-func runOnce(
-	ctx context.Context,
-	relayRepo relayout.Repository,
-	signalRepo signalout.Repository,
-	tw *twitchprov.Client,
-	_ []string, // ya no usamos TWITCH_HANDLES del .env
-) {
-	// ====== Resolver básico de evento (si ya tienes LoadStreamRules/ActiveWindows) ======
+// addJob registra un job con su intervalo y timeout por defecto. JOB_<NAME>_SCHEDULE
+// (intervalo o cron de 5 campos), JOB_<NAME>_TIMEOUT y JOB_<NAME>_JITTER los reemplazan;
+// el jitter por defecto es un 5% del intervalo (máx. 1 minuto).
+func addJob(sched *scheduler.Scheduler, name string, every, timeout time.Duration, run func(ctx context.Context) error) {
+	prefix := "JOB_" + strings.ToUpper(name) + "_"
+	var schedule scheduler.Schedule = scheduler.Every(every)
+	if spec := os.Getenv(prefix + "SCHEDULE"); spec != "" {
+		s, err := scheduler.ParseSchedule(spec)
+		if err != nil {
+			log.Fatal().Err(err).Str("job", name).Msg("invalid job schedule")
+		}
+		schedule = s
+	}
+	jitter := min(every/20, time.Minute)
+	if _, ok := schedule.(*scheduler.Cron); ok {
+		jitter = 0
+	}
+	job := scheduler.Job{
+		Name:     name,
+		Schedule: schedule,
+		Jitter:   lifecycle.EnvDuration(prefix+"JITTER", jitter),
+		Timeout:  lifecycle.EnvDuration(prefix+"TIMEOUT", timeout),
+		Run:      run,
+	}
+	if err := sched.Add(job); err != nil {
+		log.Fatal().Err(err).Str("job", name).Msg("invalid job")
+	}
+}
+
+// eventResolver asigna un co-stream a un evento por regla o ventana activa
+type eventResolver func(platform, login, lang string) (slug, title, game, league string)
+
+func newEventResolver(ctx context.Context, relayRepo relayout.Repository) eventResolver {
 	rules, _ := relayRepo.LoadStreamRules(ctx) // map["twitch:<login>"]=event_slug
 	wins, _ := relayRepo.ActiveWindows(ctx, time.Now())
 
-	resolveEvent := func(platform, login, lang string) (slug, title, game, league string) {
+	return func(platform, login, lang string) (slug, title, game, league string) {
 		key := platform + ":" + strings.ToLower(login)
 		if e, ok := rules[key]; ok {
 			return e, "Mapped by rule", "val", "Community"
@@ -88,76 +186,298 @@ func runOnce(
 		}
 		return "misc-live", "Community Live", "val", "Community"
 	}
+}
 
-	// ====== TWITCH: lee handles desde DB y upserts ======
-	if tw != nil {
-		creators, err := relayRepo.ListCreatorHandles(ctx, "twitch", true)
+// pollTwitch lee los creadores de Twitch de la DB y sube sus streams en vivo como co-streams.
+// Cada upsert deja su fila en ingestion_logs; si falla la consulta a Twitch se registra el
+// error para cada login del lote.
+func pollTwitch(ctx context.Context, relayRepo relayout.Repository, tw *twitchprov.Client, audit *ingestion.Store) error {
+	creators, err := relayRepo.ListCreatorHandles(ctx, "twitch", true)
+	if err != nil {
+		return fmt.Errorf("list twitch creators failed: %w", err)
+	}
+	if len(creators) == 0 {
+		return nil
+	}
+	resolveEvent := newEventResolver(ctx, relayRepo)
+
+	var logins []string
+	for _, c := range creators {
+		logins = append(logins, c.Handle)
+	}
+	verified := true // solo se consultan creadores verificados
+	var errs []error
+	for _, chunk := range twitchprov.Chunk(logins, 100) {
+		streams, err := tw.GetStreamsByLogin(ctx, chunk)
 		if err != nil {
-			log.Error().Err(err).Msg("list twitch creators failed")
-		} else if len(creators) > 0 {
-			var logins []string
-			for _, c := range creators {
-				logins = append(logins, c.Handle)
+			log.Ctx(ctx).Error().Err(err).Msg("twitch GetStreams failed")
+			for _, login := range chunk {
+				audit.Record(ctx, entities.IngestionSourceTwitch, entities.IngestionEntityCoStream, "twitch:"+login, fmt.Errorf("get streams: %w", err))
 			}
-			for _, chunk := range twitchprov.Chunk(logins, 100) {
-				streams, err := tw.GetStreamsByLogin(ctx, chunk)
-				if err != nil {
-					log.Error().Err(err).Msg("twitch GetStreams failed")
-					continue
-				}
-				for login, s := range streams {
-					url := "https://twitch.tv/" + login
-					eventSlug, eventTitle, game, league := resolveEvent("twitch", login, s.Language)
-
-					if err := relayRepo.UpsertCoStream(ctx,
-						eventSlug, eventTitle, game, league, nil,
-						"twitch", login, url, s.Language, "", true,
-						s.ViewerCount, s.Type == "live",
-					); err != nil {
-						log.Error().Err(err).Str("login", login).Msg("upsert co-stream failed")
-					}
-				}
+			errs = append(errs, err)
+			continue
+		}
+		for login, s := range streams {
+			url := "https://twitch.tv/" + login
+			eventSlug, eventTitle, game, league := resolveEvent("twitch", login, s.Language)
+
+			if err := relayRepo.UpsertCoStream(ctx, relayout.CoStreamUpsert{
+				Source:     entities.IngestionSourceTwitch,
+				EventSlug:  eventSlug,
+				EventTitle: eventTitle,
+				Game:       game,
+				League:     league,
+				Platform:   "twitch",
+				Handle:     login,
+				URL:        url,
+				Lang:       s.Language,
+				Verified:   &verified,
+				Viewers:    s.ViewerCount,
+				IsLive:     s.Type == "live",
+			}); err != nil {
+				log.Ctx(ctx).Error().Err(err).Str("login", login).Msg("upsert co-stream failed")
+				errs = append(errs, err)
+			}
+		}
+		// Creadores verificados sin stream: Twitch no los devuelve, pero sí se consultaron
+		for _, login := range chunk {
+			login = strings.ToLower(strings.TrimSpace(login))
+			if _, live := streams[login]; !live {
+				audit.RecordOffline(ctx, entities.IngestionSourceTwitch, entities.IngestionEntityCoStream, "twitch:"+login)
 			}
 		}
 	}
+	return errors.Join(errs...)
+}
+
+// cleanupStale marca offline los co-streams con más de WORKER_STALE_MINUTES sin señal
+func cleanupStale(ctx context.Context, relayRepo relayout.Repository) error {
+	staleMinutes := envInt("WORKER_STALE_MINUTES", 10)
+	affected, err := relayRepo.MarkStaleCoStreamsOffline(ctx, time.Duration(staleMinutes)*time.Minute)
+	if err != nil {
+		return fmt.Errorf("cleanup stale co_streams failed: %w", err)
+	}
+	if affected > 0 {
+		log.Ctx(ctx).Info().Int64("rows", affected).Int("stale_minutes", staleMinutes).Msg("cleanup co_streams marked offline")
+	}
+	return nil
+}
+
+// rollupMetrics recalcula las comps de LoL de los COMPS_RECENT_PATCHES últimos parches y el
+// impacto de los IMPACT_RECENT_PATCHES últimos
+func rollupMetrics(ctx context.Context, comps *signalsvc.LolCompService, impact *signalsvc.ImpactService) error {
+	var errs []error
+	written, err := comps.RebuildRecent(ctx, envInt("COMPS_RECENT_PATCHES", 3))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("lol comp aggregation failed")
+		errs = append(errs, err)
+	}
+	for patch, n := range written {
+		log.Ctx(ctx).Info().Str("patch", patch).Int("comps", n).Msg("lol comp aggregation OK")
+	}
+	for _, game := range []string{"lol", "val"} {
+		errs = append(errs, scoreRecentPatches(ctx, impact, game))
+	}
+	return errors.Join(errs...)
+}
+
+// ingestRiotMatches ingiere partidas de Match-V5 por plataforma y recalcula las comps de LoL
+// (y el impacto de parches) de los parches con partidas nuevas
+func ingestRiotMatches(ctx context.Context, ingest *signalsvc.MatchIngestService, comps *signalsvc.LolCompService, impact *signalsvc.ImpactService, audit *ingestion.Store, platforms []string) error {
+	opts := signalsvc.MatchIngestOptions{
+		Queue:            "RANKED_SOLO_5x5",
+		Players:          envInt("RIOT_MATCH_PLAYERS", 20),
+		MatchesPerPlayer: envInt("RIOT_MATCHES_PER_PLAYER", 10),
+	}
+
+	var errs []error
+	touched := make(map[string]bool)
+	for _, platform := range platforms {
+		res, err := ingest.IngestPlatform(ctx, platform, opts)
+		audit.Record(ctx, entities.IngestionSourceRiot, entities.IngestionEntityMatch, "lol:"+platform, err)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("platform", platform).Msg("riot match ingest failed")
+			errs = append(errs, fmt.Errorf("%s: %w", platform, err))
+			continue
+		}
+		log.Ctx(ctx).Info().
+			Str("platform", platform).
+			Int("players", res.Players).
+			Int("new_matches", res.NewMatches).
+			Int("skipped", res.SkippedMatches).
+			Int("failed", res.FailedMatches).
+			Strs("patches", res.Patches).
+			Msg("riot match ingest OK")
+		for _, patch := range res.Patches {
+			touched[patch] = true
+		}
+	}
+	for patch := range touched {
+		n, err := comps.RebuildPatch(ctx, patch)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("patch", patch).Msg("lol comp aggregation failed")
+			errs = append(errs, err)
+			continue
+		}
+		log.Ctx(ctx).Info().Str("patch", patch).Int("comps", n).Msg("lol comp aggregation OK")
+	}
+	if len(touched) > 0 {
+		errs = append(errs, scoreRecentPatches(ctx, impact, "lol"))
+	}
+	return errors.Join(errs...)
+}
 
-	// ====== META/COMPS (mock para demo) ======
-	for _, c := range mockPullComps() {
-		raw, _ := json.Marshal(c.Slots)
-		if err := signalRepo.UpsertComp(ctx,
-			c.Game, c.Region, c.League, c.Patch, c.Map, c.Side, string(raw), c.Pick, c.Win, c.Delta,
-		); err != nil {
-			log.Error().Err(err).Str("patch", c.Patch).Msg("upsert comp failed")
+// snapshotMetaGame persiste rotación, ligas y reporte de meta-game de cada plataforma
+func snapshotMetaGame(ctx context.Context, metaGame *signalsvc.MetaGameService, platforms []string) error {
+	queues := []string{"RANKED_SOLO_5x5", "RANKED_FLEX_SR"}
+	var errs []error
+	for _, platform := range platforms {
+		res, err := metaGame.SnapshotPlatform(ctx, platform, queues)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("platform", platform).Msg("meta-game snapshot failed")
+			errs = append(errs, fmt.Errorf("%s: %w", platform, err))
+			continue
 		}
+		log.Ctx(ctx).Info().
+			Str("platform", platform).
+			Str("patch", res.Patch).
+			Bool("rotation_changed", res.RotationChanged).
+			Strs("queues", res.Queues).
+			Msg("meta-game snapshot OK")
 	}
+	return errors.Join(errs...)
+}
 
-	// ====== CLEANUP: marca offline streams con más de N minutos sin señal ======
-	staleMinutes := 10
-	if v := os.Getenv("WORKER_STALE_MINUTES"); v != "" {
-		if n, err := strconv.Atoi(v); err == nil && n > 0 {
-			staleMinutes = n
+// crossRegionReport guarda el reporte comparativo entre las plataformas de RIOT_PLATFORMS
+func crossRegionReport(ctx context.Context, metaGame *signalsvc.MetaGameService, platforms []string) error {
+	opts := signalsvc.CrossRegionOptions{Concurrency: envInt("RIOT_CROSS_REGION_CONCURRENCY", 3)}
+	report, err := metaGame.SnapshotCrossRegion(ctx, platforms, opts)
+	if err != nil {
+		return err
+	}
+	log.Ctx(ctx).Info().Strs("platforms", report.Platforms).Int("failed", len(report.Errors)).Msg("cross-region report OK")
+	return nil
+}
+
+// aggregateMastery agrega maestrías de una muestra del ladder de cada plataforma
+func aggregateMastery(ctx context.Context, mastery *signalsvc.MasteryService, platforms []string) error {
+	sample := envInt("RIOT_MASTERY_SAMPLE", 50)
+	var errs []error
+	for _, platform := range platforms {
+		res, err := mastery.AggregatePlatform(ctx, platform, "RANKED_SOLO_5x5", sample)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("platform", platform).Msg("mastery aggregation failed")
+			errs = append(errs, fmt.Errorf("%s: %w", platform, err))
+			continue
 		}
+		log.Ctx(ctx).Info().
+			Str("platform", platform).
+			Int("sample_size", res.SampleSize).
+			Int("failed", res.Failed).
+			Int("champions", res.Champions).
+			Msg("mastery aggregation OK")
 	}
-	affected, err := relayRepo.MarkStaleCoStreamsOffline(ctx, time.Duration(staleMinutes)*time.Minute)
+	return errors.Join(errs...)
+}
+
+// ingestValComps ingiere partidas recientes de Valorant y recalcula app.comps
+func ingestValComps(ctx context.Context, comps *signalsvc.ValCompService, impact *signalsvc.ImpactService, audit *ingestion.Store) error {
+	opts := signalsvc.ValIngestOptions{
+		Queue:      "competitive",
+		MaxMatches: envInt("RIOT_VAL_MATCHES", 50),
+	}
+
+	var errs []error
+	touched := false
+	for _, shard := range valShards() {
+		res, err := comps.IngestShard(ctx, shard, opts)
+		audit.Record(ctx, entities.IngestionSourceRiot, entities.IngestionEntityMatch, "val:"+shard, err)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("shard", shard).Msg("valorant comp aggregation failed")
+			errs = append(errs, fmt.Errorf("%s: %w", shard, err))
+			continue
+		}
+		log.Ctx(ctx).Info().
+			Str("shard", shard).
+			Int("new_matches", res.NewMatches).
+			Int("skipped", res.SkippedMatches).
+			Int("failed", res.FailedMatches).
+			Strs("patches", res.Patches).
+			Int("comps", res.Comps).
+			Msg("valorant comp aggregation OK")
+		touched = touched || len(res.Patches) > 0
+	}
+	// Un solo recálculo aunque varios shards hayan traído partidas nuevas
+	if touched {
+		errs = append(errs, scoreRecentPatches(ctx, impact, "val"))
+	}
+	return errors.Join(errs...)
+}
+
+// retentionDays INGESTION_RETENTION_DAYS (default 30); 0 desactiva la purga, a diferencia
+// de envInt que descarta los valores no positivos
+func retentionDays() int {
+	if n, err := strconv.Atoi(os.Getenv("INGESTION_RETENTION_DAYS")); err == nil && n >= 0 {
+		return n
+	}
+	return 30
+}
+
+// purgeIngestionLogs borra las filas de ingestion_logs con más de days días
+func purgeIngestionLogs(ctx context.Context, audit *ingestion.Store, days int) error {
+	deleted, err := audit.Purge(ctx, time.Now().AddDate(0, 0, -days))
 	if err != nil {
-		log.Error().Err(err).Int("stale_minutes", staleMinutes).Msg("cleanup stale co_streams failed")
-	} else if affected > 0 {
-		log.Info().Int64("rows", affected).Int("stale_minutes", staleMinutes).Msg("cleanup co_streams marked offline")
-	}
-
-	log.Info().Msg("ingest cycle OK")
-}
-
-func mockPullComps() []CompSample {
-	pick := 25.1
-	win := 53.2
-	delta := 1.8
-	return []CompSample{{
-		Game: "val", Region: "EMEA", League: "VCT EMEA", Patch: "9.15", Map: "Ascent", Side: "attack",
-		Slots: map[string]any{
-			"roles":   []string{"smokes", "initiator", "duelist", "sentinel", "flex"},
-			"members": []map[string]string{{"agent": "omen"}, {"agent": "sova"}, {"agent": "jett"}, {"agent": "killjoy"}, {"agent": "skye"}},
-		},
-		Pick: &pick, Win: &win, Delta: &delta,
-	}}
+		return fmt.Errorf("purge ingestion logs failed: %w", err)
+	}
+	log.Ctx(ctx).Info().Int64("rows", deleted).Int("retention_days", days).Msg("ingestion logs purged")
+	return nil
+}
+
+// scoreRecentPatches recalcula el impacto de los IMPACT_RECENT_PATCHES últimos parches de un juego
+func scoreRecentPatches(ctx context.Context, impact *signalsvc.ImpactService, game string) error {
+	patches, err := impact.ScoreRecent(ctx, game, envInt("IMPACT_RECENT_PATCHES", 3))
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("game", game).Msg("patch impact scoring failed")
+		return fmt.Errorf("patch impact %s: %w", game, err)
+	}
+	log.Ctx(ctx).Info().Str("game", game).Strs("patches", patches).Msg("patch impact scoring OK")
+	return nil
+}
+
+// riotPlatforms lee RIOT_PLATFORMS (coma-separado, default euw1)
+func riotPlatforms() []string {
+	var platforms []string
+	for _, p := range strings.Split(strings.ToLower(os.Getenv("RIOT_PLATFORMS")), ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			platforms = append(platforms, p)
+		}
+	}
+	if len(platforms) == 0 {
+		platforms = []string{"euw1"}
+	}
+	return platforms
+}
+
+// valShards lee RIOT_VAL_SHARDS (coma-separado, default eu)
+func valShards() []string {
+	var shards []string
+	for _, s := range strings.Split(strings.ToLower(os.Getenv("RIOT_VAL_SHARDS")), ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			shards = append(shards, s)
+		}
+	}
+	if len(shards) == 0 {
+		shards = []string{"eu"}
+	}
+	return shards
+}
+
+// envInt lee un entero positivo del entorno con valor por defecto
+func envInt(key string, def int) int {
+	if v := os.Getenv(key); v != "" {
+		if n, err := strconv.Atoi(v); err == nil && n > 0 {
+			return n
+		}
+	}
+	return def
 }
@@ -3,6 +3,7 @@ package entities
 import (
 	"crypto/md5"
 	"encoding/hex"
+	"encoding/json"
 	"time"
 
 	"gorm.io/datatypes"
@@ -19,8 +20,9 @@ type Comp struct {
 	Patch  string    `gorm:"type:varchar(32);not null;index:idx_comps_full_filter,priority:3;index:idx_comps_filter,priority:4" json:"patch"`
 	Map    string    `gorm:"type:varchar(64);not null;default:'';index:idx_comps_full_filter,priority:5" json:"map"`
 	Side   string    `gorm:"type:varchar(16);not null;default:'';index:idx_comps_full_filter,priority:6" json:"side"`
+	Shape  string    `gorm:"type:varchar(16);not null;default:'full';index" json:"shape"` // full|bot_duo|jungle_mid
 
-	Slots   datatypes.JSON `gorm:"type:jsonb;not null" json:"slots"`
+	Slots   datatypes.JSON `gorm:"type:jsonb;not null;index:idx_comps_slots,type:gin" json:"slots"`
 	SlotsFP string         `gorm:"type:text;not null;uniqueIndex:uq_comp_fingerprint" json:"-"`
 
 	// UNIQUE lógico: (game,region,league,patch,map,side,slots_fp)
@@ -32,9 +34,12 @@ type Comp struct {
 	_ struct{} `gorm:"uniqueIndex:uq_comp_fingerprint,priority:6"` // side
 	// slots_fp es priority:7 por tag en el propio campo
 
+	Games     int       `gorm:"not null;default:0" json:"games"` // partidas de la comp en esta fila
 	PickRate  *float64  `gorm:"type:numeric(6,3)" json:"pick_rate,omitempty"`
 	WinRate   *float64  `gorm:"type:numeric(6,3)" json:"win_rate,omitempty"`
 	DeltaWin  *float64  `gorm:"type:numeric(6,3)" json:"delta_win,omitempty"`
+	WinCILow  *float64  `gorm:"type:numeric(6,3)" json:"win_ci_low,omitempty"`  // Wilson 95%
+	WinCIHigh *float64  `gorm:"type:numeric(6,3)" json:"win_ci_high,omitempty"` // Wilson 95%
 	CreatedAt time.Time `gorm:"type:timestamptz;not null;index:idx_comps_created,sort:desc" json:"created_at"`
 	UpdatedAt time.Time `gorm:"type:timestamptz;not null"                                        json:"updated_at"`
 }
@@ -44,12 +49,70 @@ func (Comp) TableName() string { return "app.comps" }
 // Hook: simula columna generada slots_fp = md5(slots::text)
 func (c *Comp) BeforeSave(tx *gorm.DB) error {
 	if len(c.Slots) > 0 {
-		sum := md5.Sum([]byte(c.Slots.String()))
-		c.SlotsFP = hex.EncodeToString(sum[:])
+		c.SlotsFP = SlotsFingerprint(c.Slots)
 	}
 	return nil
 }
 
+// Formas de composición: la completa y las parciales de LoL
+const (
+	CompShapeFull      = "full"
+	CompShapeBotDuo    = "bot_duo"    // bot + support
+	CompShapeJungleMid = "jungle_mid" // jungle + mid
+)
+
+// CompSnapshot foto de las métricas de una comp; UpsertComp guarda una cada vez que cambian.
+// La identidad (game, region, league, map, side, slots_fp) permite seguir la comp entre parches.
+type CompSnapshot struct {
+	ID         int64     `gorm:"primaryKey" json:"id"`
+	CompUUID   uuid.UUID `gorm:"type:uuid;not null;index" json:"comp_uuid"`
+	Game       string    `gorm:"type:varchar(10);not null;index:idx_comp_snapshots_identity,priority:1" json:"game"`
+	Region     string    `gorm:"type:varchar(16);not null;index:idx_comp_snapshots_identity,priority:2" json:"region"`
+	League     string    `gorm:"type:varchar(120);not null;default:'';index:idx_comp_snapshots_identity,priority:3" json:"league"`
+	Patch      string    `gorm:"type:varchar(32);not null" json:"patch"`
+	Map        string    `gorm:"type:varchar(64);not null;default:'';index:idx_comp_snapshots_identity,priority:4" json:"map"`
+	Side       string    `gorm:"type:varchar(16);not null;default:'';index:idx_comp_snapshots_identity,priority:5" json:"side"`
+	Shape      string    `gorm:"type:varchar(16);not null;default:'full'" json:"shape"`
+	SlotsFP    string    `gorm:"type:text;not null;index:idx_comp_snapshots_identity,priority:6" json:"-"`
+	Games      int       `gorm:"not null;default:0" json:"games"`
+	PickRate   *float64  `gorm:"type:numeric(6,3)" json:"pick_rate,omitempty"`
+	WinRate    *float64  `gorm:"type:numeric(6,3)" json:"win_rate,omitempty"`
+	DeltaWin   *float64  `gorm:"type:numeric(6,3)" json:"delta_win,omitempty"`
+	WinCILow   *float64  `gorm:"type:numeric(6,3)" json:"win_ci_low,omitempty"`
+	WinCIHigh  *float64  `gorm:"type:numeric(6,3)" json:"win_ci_high,omitempty"`
+	CapturedAt time.Time `gorm:"type:timestamptz;not null;index" json:"captured_at"`
+}
+
+func (CompSnapshot) TableName() string { return "app.comp_snapshots" }
+
+// CompSlots forma canónica de los slots de una composición:
+// roles[i] es el rol del miembro members[i]
+type CompSlots struct {
+	Shape   string           `json:"shape,omitempty"`
+	Roles   []string         `json:"roles"`
+	Members []CompSlotMember `json:"members"`
+}
+
+// CompSlotMember miembro de una composición (agent en VAL, champion en LoL)
+type CompSlotMember struct {
+	Agent    string `json:"agent,omitempty"`
+	Champion string `json:"champion,omitempty"`
+}
+
+// SlotsFingerprint calcula slots_fp = md5 del JSON normalizado (sin espacios y con
+// las claves ordenadas), así el mismo slots da el mismo fp venga de donde venga
+func SlotsFingerprint(slots []byte) string {
+	normalized := slots
+	var v any
+	if err := json.Unmarshal(slots, &v); err == nil {
+		if raw, err := json.Marshal(v); err == nil {
+			normalized = raw
+		}
+	}
+	sum := md5.Sum(normalized)
+	return hex.EncodeToString(sum[:])
+}
+
 // ChampionRotation representa la rotación semanal de campeones gratuitos
 type ChampionRotation struct {
 	UUID                  uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"uuid"`
@@ -97,6 +160,7 @@ type ChampionMasteryStats struct {
 	AvgMasteryLevel  float64   `gorm:"type:numeric(4,2);not null" json:"avg_mastery_level"`
 	TotalPlayers     int       `gorm:"not null" json:"total_players"`
 	TopMasteryPoints int64     `gorm:"not null" json:"top_mastery_points"`
+	TopMasteryLevel  int       `gorm:"not null;default:0" json:"top_mastery_level"`
 	SampleSize       int       `gorm:"not null" json:"sample_size"`
 	CreatedAt        time.Time `gorm:"type:timestamptz;not null" json:"created_at"`
 	UpdatedAt        time.Time `gorm:"type:timestamptz;not null" json:"updated_at"`
@@ -8,8 +8,8 @@ import (
 
 type CoStream struct {
 	UUID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"uuid"`
-	EventUUID   uuid.UUID `gorm:"type:uuid;not null;index"                       json:"event_uuid"`
-	CreatorUUID uuid.UUID `gorm:"type:uuid;not null;index"                       json:"creator_uuid"`
+	EventUUID   uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:uq_costream_event_creator,priority:1" json:"event_uuid"`
+	CreatorUUID uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:uq_costream_event_creator,priority:2" json:"creator_uuid"`
 
 	Platform string `gorm:"type:varchar(16);not null;index"                           json:"platform"` // twitch|youtube
 	URL      string `gorm:"type:text;not null"                                         json:"url"`
@@ -25,3 +25,10 @@ type HypeMapSummaryItem struct {
 	TotalViewers int       `json:"total_viewers"  gorm:"column:total_viewers"`
 	LastSeenAt   time.Time `json:"last_seen_at"   gorm:"column:last_seen_at"`
 }
+
+// LiveGameStats co-streams en vivo y espectadores totales de un juego
+type LiveGameStats struct {
+	Game    string `json:"game"    gorm:"column:game"`
+	Streams int64  `json:"streams" gorm:"column:streams"`
+	Viewers int64  `json:"viewers" gorm:"column:viewers"`
+}
@@ -6,14 +6,32 @@ import (
 	"github.com/google/uuid"
 )
 
+// Valores de IngestionLog
+const (
+	IngestionSourceTwitch = "twitch"
+	IngestionSourceRiot   = "riot"
+	IngestionSourceAPI    = "api"
+
+	IngestionEntityCoStream = "costream"  // platform:handle
+	IngestionEntityMatch    = "match"     // game:platform (lote de partidas de Riot o archivo VAL)
+	IngestionEntityPatch    = "patch"     // game:version
+	IngestionEntityComp     = "comp"      // game:patch:shape
+	IngestionEntityProMatch = "pro_match" // game
+	IngestionEntityRequest  = "request"   // petición que falló antes de identificar la entidad (ruta)
+
+	IngestionStatusSuccess = "success"
+	IngestionStatusError   = "error"
+	IngestionStatusOffline = "offline" // consulta correcta sin nada que ingerir (creador sin emitir)
+)
+
 type IngestionLog struct {
 	UUID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"uuid"`
-	Source      string    `gorm:"type:varchar(16);not null;index"                json:"source"`      // twitch|api
-	EntityType  string    `gorm:"type:varchar(24);not null;index"                json:"entity_type"` // costream|comp|event
-	EntityID    string    `gorm:"type:varchar(80);not null;index"                json:"entity_id"`
-	Status      string    `gorm:"type:varchar(16);not null;index"                json:"status"` // success|error
+	Source      string    `gorm:"type:varchar(16);not null;index"                json:"source"`      // twitch|riot|api
+	EntityType  string    `gorm:"type:varchar(24);not null;index"                json:"entity_type"` // costream|match|patch|comp|pro_match|request
+	EntityID    string    `gorm:"type:varchar(160);not null;index"               json:"entity_id"`
+	Status      string    `gorm:"type:varchar(16);not null;index"                json:"status"` // success|error|offline
 	ErrorMsg    string    `gorm:"type:text"                                      json:"error_msg,omitempty"`
-	ProcessedAt time.Time `gorm:"type:timestamptz;not null"                      json:"processed_at"`
+	ProcessedAt time.Time `gorm:"type:timestamptz;not null;index"                json:"processed_at"`
 
 	CreatedAt time.Time `gorm:"type:timestamptz;not null" json:"created_at"`
 	UpdatedAt time.Time `gorm:"type:timestamptz;not null" json:"updated_at"`
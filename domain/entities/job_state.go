@@ -0,0 +1,34 @@
+package entities
+
+import "time"
+
+// Estados de la última ejecución de un job
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusTimedOut  = "timed_out"
+)
+
+// JobState estado persistido de un job programado del worker (uno por nombre)
+type JobState struct {
+	Name               string     `gorm:"type:varchar(64);primaryKey"       json:"name"`
+	Schedule           string     `gorm:"type:varchar(64);not null"         json:"schedule"` // "@every 30m" o cron de 5 campos
+	Timeout            string     `gorm:"type:varchar(32)"                  json:"timeout,omitempty"`
+	Running            bool       `gorm:"not null;default:false"            json:"running"`
+	Owner              string     `gorm:"type:varchar(128)"                 json:"owner,omitempty"`       // réplica (host-pid) de la última ejecución
+	LastStatus         string     `gorm:"type:varchar(16)"                  json:"last_status,omitempty"` // running|succeeded|failed|timed_out
+	LastStartedAt      *time.Time `gorm:"type:timestamptz"                  json:"last_started_at,omitempty"`
+	LastFinishedAt     *time.Time `gorm:"type:timestamptz"                  json:"last_finished_at,omitempty"`
+	LastDurationMs     int64      `gorm:"not null;default:0"                json:"last_duration_ms"`
+	LastError          string     `gorm:"type:text"                         json:"last_error,omitempty"`
+	NextRunAt          *time.Time `gorm:"type:timestamptz"                  json:"next_run_at,omitempty"`
+	TriggerRequestedAt *time.Time `gorm:"type:timestamptz"                  json:"trigger_requested_at,omitempty"` // pedido manual pendiente
+	Runs               int64      `gorm:"not null;default:0"                json:"runs"`
+	Failures           int64      `gorm:"not null;default:0"                json:"failures"`
+
+	CreatedAt time.Time `gorm:"type:timestamptz;not null" json:"created_at"`
+	UpdatedAt time.Time `gorm:"type:timestamptz;not null" json:"updated_at"`
+}
+
+func (JobState) TableName() string { return "app.job_states" }
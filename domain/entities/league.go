@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 )
 
 type League struct {
@@ -19,9 +20,10 @@ type League struct {
 
 func (League) TableName() string { return "app.leagues" }
 
-// ProfessionalLeague representa una liga profesional de League of Legends
+// ProfessionalLeague representa una liga profesional (LoL o Valorant)
 type ProfessionalLeague struct {
 	Code        string    `gorm:"type:varchar(10);primaryKey" json:"code"`
+	Game        string    `gorm:"type:varchar(10);not null;default:'lol';index" json:"game"`
 	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
 	Region      string    `gorm:"type:varchar(50);not null" json:"region"`
 	Platform    string    `gorm:"type:varchar(20);not null" json:"platform"`
@@ -35,7 +37,9 @@ type ProfessionalLeague struct {
 
 func (ProfessionalLeague) TableName() string { return "app.professional_leagues" }
 
-// LeagueChampionStats representa estadísticas de campeones en una liga profesional
+// LeagueChampionStats representa estadísticas de campeones en una liga profesional.
+// Las filas se recalculan a partir de ProMatch por liga y temporada (en Valorant ChampionName
+// es el agente).
 type LeagueChampionStats struct {
 	ID            uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
 	LeagueCode    string    `gorm:"type:varchar(10);not null;index" json:"league_code"`
@@ -55,3 +59,20 @@ type LeagueChampionStats struct {
 }
 
 func (LeagueChampionStats) TableName() string { return "app.league_champion_stats" }
+
+// ProMatch partida profesional importada desde archivo (CSV/JSON)
+type ProMatch struct {
+	UUID       uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey"                    json:"uuid"`
+	Game       string         `gorm:"type:varchar(10);not null;default:'lol'"                           json:"game"`
+	LeagueCode string         `gorm:"type:varchar(10);not null;uniqueIndex:uq_pro_matches,priority:1;index:idx_pro_matches_season,priority:1" json:"league_code"`
+	MatchID    string         `gorm:"type:varchar(64);not null;uniqueIndex:uq_pro_matches,priority:2"   json:"match_id"`
+	Season     string         `gorm:"type:varchar(20);not null;index:idx_pro_matches_season,priority:2" json:"season"`
+	Patch      string         `gorm:"type:varchar(32);not null;default:''"                             json:"patch"`
+	Map        string         `gorm:"type:varchar(32);not null;default:''"                              json:"map"` // solo Valorant
+	PlayedAt   *time.Time     `gorm:"type:timestamptz"                                                  json:"played_at,omitempty"`
+	Summary    datatypes.JSON `gorm:"type:jsonb;not null"                                               json:"summary"` // MatchSummary
+	CreatedAt  time.Time      `gorm:"type:timestamptz;not null"                                         json:"created_at"`
+	UpdatedAt  time.Time      `gorm:"type:timestamptz;not null"                                         json:"updated_at"`
+}
+
+func (ProMatch) TableName() string { return "app.pro_matches" }
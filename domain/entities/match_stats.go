@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// RiotMatch registra una partida de Match-V5 ya procesada (sirve para deduplicar)
+type RiotMatch struct {
+	MatchID      string         `gorm:"type:varchar(40);primaryKey"                   json:"match_id"`
+	Game         string         `gorm:"type:varchar(10);not null;default:'lol'"       json:"game"`
+	Platform     string         `gorm:"type:varchar(16);not null;index:idx_riot_matches_platform_patch,priority:1" json:"platform"`
+	Patch        string         `gorm:"type:varchar(32);not null;index:idx_riot_matches_platform_patch,priority:2" json:"patch"`
+	QueueID      int            `gorm:"not null;index:idx_riot_matches_platform_patch,priority:3"                  json:"queue_id"`
+	GameCreation time.Time      `gorm:"type:timestamptz;not null;index"               json:"game_creation"`
+	Summary      datatypes.JSON `gorm:"type:jsonb;not null"                           json:"summary"` // MatchSummary
+	CreatedAt    time.Time      `gorm:"type:timestamptz;not null"                     json:"created_at"`
+	UpdatedAt    time.Time      `gorm:"type:timestamptz;not null"                     json:"updated_at"`
+}
+
+func (RiotMatch) TableName() string { return "app.riot_matches" }
+
+// MatchSummary resumen compacto de una partida (picks por rol y bans por equipo)
+type MatchSummary struct {
+	Teams []MatchTeamSummary `json:"teams"`
+}
+
+// MatchTeamSummary resumen de un equipo dentro de una partida
+type MatchTeamSummary struct {
+	TeamID   int                `json:"team_id"`
+	Name     string             `json:"name,omitempty"` // equipo profesional (ProMatch)
+	Side     string             `json:"side"`           // blue|red
+	Win      bool               `json:"win"`
+	Picks    []MatchPickSummary `json:"picks"`
+	Bans     []int              `json:"bans"`
+	BanNames []string           `json:"ban_names,omitempty"` // ProMatch: los archivos traen nombres, no IDs
+}
+
+// MatchPickSummary campeón elegido por un jugador
+type MatchPickSummary struct {
+	ChampionID   int    `json:"champion_id"`
+	ChampionName string `json:"champion_name"`
+	Role         string `json:"role"` // TOP|JUNGLE|MIDDLE|BOTTOM|UTILITY
+}
+
+// VALMatchSummary resumen compacto de una partida de Valorant (agentes y rondas por lado).
+// Se guarda en RiotMatch.Summary con Game = "val" y Platform = shard.
+type VALMatchSummary struct {
+	Map    string           `json:"map"`
+	Queue  string           `json:"queue"`  // competitive, premier, ... ("" en partidas subidas)
+	League string           `json:"league"` // Ranked, VCT EMEA, ...
+	Teams  []VALTeamSummary `json:"teams"`
+}
+
+// VALTeamSummary resumen de un equipo dentro de una partida de Valorant
+type VALTeamSummary struct {
+	TeamID        string   `json:"team_id"` // Red|Blue
+	Win           bool     `json:"win"`
+	Agents        []string `json:"agents"` // nombres normalizados (omen, kayo, ...)
+	AttackRounds  int      `json:"attack_rounds"`
+	AttackWins    int      `json:"attack_wins"`
+	DefenseRounds int      `json:"defense_rounds"`
+	DefenseWins   int      `json:"defense_wins"`
+}
+
+// ChampionMatchStats estadísticas medidas de un campeón a partir de partidas de Match-V5.
+// Role = "" es la fila agregada del campeón (incluye bans); el resto son filas por rol.
+type ChampionMatchStats struct {
+	UUID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"uuid"`
+	Game         string    `gorm:"type:varchar(10);not null;default:'lol'"        json:"game"`
+	Platform     string    `gorm:"type:varchar(16);not null;uniqueIndex:uq_champion_match_stats,priority:1" json:"platform"`
+	Patch        string    `gorm:"type:varchar(32);not null;uniqueIndex:uq_champion_match_stats,priority:2" json:"patch"`
+	QueueID      int       `gorm:"not null;uniqueIndex:uq_champion_match_stats,priority:3"                  json:"queue_id"`
+	ChampionID   int       `gorm:"not null;uniqueIndex:uq_champion_match_stats,priority:4"                  json:"champion_id"`
+	Role         string    `gorm:"type:varchar(16);not null;default:'';uniqueIndex:uq_champion_match_stats,priority:5" json:"role"`
+	ChampionName string    `gorm:"type:varchar(64);not null"                      json:"champion_name"`
+	Games        int       `gorm:"not null;default:0"                             json:"games"`
+	Wins         int       `gorm:"not null;default:0"                             json:"wins"`
+	Bans         int       `gorm:"not null;default:0"                             json:"bans"`
+	PickRate     float64   `gorm:"type:numeric(6,3);not null;default:0"           json:"pick_rate"`
+	WinRate      float64   `gorm:"type:numeric(6,3);not null;default:0"           json:"win_rate"`
+	BanRate      float64   `gorm:"type:numeric(6,3);not null;default:0"           json:"ban_rate"`
+	CreatedAt    time.Time `gorm:"type:timestamptz;not null"                      json:"created_at"`
+	UpdatedAt    time.Time `gorm:"type:timestamptz;not null"                      json:"updated_at"`
+}
+
+func (ChampionMatchStats) TableName() string { return "app.champion_match_stats" }
@@ -14,14 +14,12 @@ type Repository interface {
 	// HypeMap
 	HypeMapLive(ctx context.Context, game, lang string, limit, offset int) ([]entities.HypeMapItem, error)
 	HypeMapSummary(ctx context.Context, game, lang string, limit, offset int) ([]entities.HypeMapSummaryItem, error)
+	LiveStatsByGame(ctx context.Context) ([]entities.LiveGameStats, error)
 
 	// Ingest / mantenimiento
-	UpsertCoStream(ctx context.Context,
-		eventSlug, eventTitle, game, league string,
-		startsAtNullable *string,
-		platform, handle, url, lang, country string,
-		verified bool, viewers int, isLive bool,
-	) error
+	// UpsertCoStream sube evento, creador y co-stream en una transacción y deja una fila en
+	// ingestion_logs con el resultado
+	UpsertCoStream(ctx context.Context, in CoStreamUpsert) error
 
 	MarkStaleCoStreamsOffline(ctx context.Context, olderThan time.Duration) (int64, error)
 
@@ -30,3 +28,28 @@ type Repository interface {
 	ActiveWindows(ctx context.Context, now time.Time) ([]entities.EventWindow, error)
 	ListCreatorHandles(ctx context.Context, platform string, verified bool) ([]entities.Creator, error)
 }
+
+// CoStreamUpsert datos de una ingesta de co-stream. Política de actualización sobre filas existentes:
+//   - evento (slug): title, league y game solo al crear (el worker manda placeholders que no
+//     deben pisar eventos curados); starts_at se reemplaza si viene
+//   - creador (platform, handle): url, lang, country y verified se reemplazan si vienen
+//   - co-stream (evento, creador): url, lang y country se reemplazan si vienen; verified como
+//     en el creador; viewers, is_live y last_seen_at reflejan la última señal
+type CoStreamUpsert struct {
+	Source string // twitch|api (ingestion_logs.source)
+
+	EventSlug  string
+	EventTitle string
+	Game       string
+	League     string
+	StartsAt   *time.Time // nil: now() al crear el evento
+
+	Platform string
+	Handle   string
+	URL      string
+	Lang     string
+	Country  string
+	Verified *bool // nil: conserva el valor actual (false al crear)
+	Viewers  int
+	IsLive   bool
+}
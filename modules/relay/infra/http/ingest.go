@@ -3,10 +3,13 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
+	"github.com/steven230500/hypeatlas-api/domain/entities"
 	out "github.com/steven230500/hypeatlas-api/modules/relay/domain/ports/out"
+	"github.com/steven230500/hypeatlas-api/shared/ingestion"
 )
 
 type IngestHandler struct{ repo out.Repository }
@@ -29,7 +32,7 @@ type upsertCoStreamReq struct {
 	URL      string `json:"url"`
 	Lang     string `json:"lang"`
 	Country  string `json:"country"`
-	Verified bool   `json:"verified"`
+	Verified *bool  `json:"verified"` // ausente: no cambia el estado del creador
 	Viewers  int    `json:"viewers"`
 	IsLive   bool   `json:"is_live"`
 }
@@ -40,7 +43,7 @@ type upsertCoStreamReq struct {
 // @Accept      json
 // @Param       body body   upsertCoStreamReq true "payload"
 // @Success     204 "no content"
-// @Failure     400 {string} string "bad json"
+// @Failure     400 {string} string "bad json / missing fields / bad starts_at"
 // @Failure     500 {string} string "db error"
 // @Router      /v1/ingest/relay/costreams:upsert [post]
 func (h *IngestHandler) upsertCoStream(w http.ResponseWriter, r *http.Request) {
@@ -50,11 +53,39 @@ func (h *IngestHandler) upsertCoStream(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.repo.UpsertCoStream(
-		r.Context(),
-		req.EventSlug, req.EventTitle, req.Game, req.League, req.StartsAt,
-		req.Platform, req.Handle, req.URL, req.Lang, req.Country, req.Verified, req.Viewers, req.IsLive,
-	); err != nil {
+	if req.EventSlug == "" || req.Platform == "" || req.Handle == "" {
+		http.Error(w, "event_slug, platform and handle are required", http.StatusBadRequest)
+		return
+	}
+	ingestion.Describe(r.Context(), entities.IngestionEntityCoStream, req.Platform+":"+req.Handle)
+	var startsAt *time.Time
+	if req.StartsAt != nil && *req.StartsAt != "" {
+		t, err := time.Parse(time.RFC3339, *req.StartsAt)
+		if err != nil {
+			http.Error(w, "starts_at must be RFC3339", http.StatusBadRequest)
+			return
+		}
+		startsAt = &t
+	}
+
+	// el repositorio deja su propia fila en ingestion_logs (con el error de la base de datos)
+	ingestion.Recorded(r.Context())
+	if err := h.repo.UpsertCoStream(r.Context(), out.CoStreamUpsert{
+		Source:     entities.IngestionSourceAPI,
+		EventSlug:  req.EventSlug,
+		EventTitle: req.EventTitle,
+		Game:       req.Game,
+		League:     req.League,
+		StartsAt:   startsAt,
+		Platform:   req.Platform,
+		Handle:     req.Handle,
+		URL:        req.URL,
+		Lang:       req.Lang,
+		Country:    req.Country,
+		Verified:   req.Verified,
+		Viewers:    req.Viewers,
+		IsLive:     req.IsLive,
+	}); err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
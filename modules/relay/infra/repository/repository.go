@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
-	"log"
+	"fmt"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"github.com/steven230500/hypeatlas-api/domain/entities"
 	out "github.com/steven230500/hypeatlas-api/modules/relay/domain/ports/out"
 	"github.com/steven230500/hypeatlas-api/shared/db"
@@ -76,19 +78,9 @@ WHERE c.is_live = true
 	query += " LIMIT ? OFFSET ?"
 	params = append(params, limit, offset)
 
-	// Debug: imprimir la consulta
-	log.Printf("DEBUG HypeMapLive query: %s", query)
-	log.Printf("DEBUG HypeMapLive params: %v", params)
-
+	// El SQL queda en el log de GORM (nivel trace, o warn si es lenta)
 	result := db.Call(r.db.WithContext(ctx).Raw(query, params...).Scan(&items))
-
-	// Debug: imprimir resultados
-	log.Printf("DEBUG HypeMapLive result error: %v", result.Error)
-	log.Printf("DEBUG HypeMapLive items count: %d", len(items))
-	if len(items) > 0 {
-		log.Printf("DEBUG HypeMapLive first item: %+v", items[0])
-	}
-
+	log.Ctx(ctx).Debug().Str("game", game).Str("lang", lang).Int("items", len(items)).Msg("hypemap live query")
 	return items, result.Error
 }
 
@@ -135,66 +127,119 @@ WHERE c.is_live = true
 	return items, result.Error
 }
 
-func (r *Repo) UpsertCoStream(ctx context.Context, eventSlug, eventTitle, game, league string, startsAtNullable *string, platform, handle, url, lang, country string, verified bool, viewers int, isLive bool) error {
-	// Primero, encontrar o crear el evento
-	var event entities.Event
-	result := db.Call(r.db.WithContext(ctx).Where("slug = ?", eventSlug).First(&event))
-	if result.Error == gorm.ErrRecordNotFound {
-		event = entities.Event{
-			Slug:   eventSlug,
-			Title:  eventTitle,
-			Game:   game,
-			League: &league,
-		}
-		// Parse startsAt if provided
-		if startsAtNullable != nil {
-			if parsed, err := time.Parse(time.RFC3339, *startsAtNullable); err == nil {
-				event.StartsAt = &parsed
-			}
+// language=SQL
+const liveStatsByGameSQL = `
+SELECT e.game, COUNT(*) AS streams, COALESCE(SUM(c.viewers), 0) AS viewers
+FROM app.co_streams c
+JOIN app.events e ON e.uuid = c.event_uuid
+WHERE c.is_live = true
+GROUP BY e.game`
+
+// LiveStatsByGame cuenta co-streams en vivo y suma sus espectadores por juego
+func (r *Repo) LiveStatsByGame(ctx context.Context) ([]entities.LiveGameStats, error) {
+	var rows []entities.LiveGameStats
+	err := db.Call(r.db.WithContext(ctx).Raw(liveStatsByGameSQL).Scan(&rows)).Error
+	return rows, err
+}
+
+// language=SQL
+const (
+	upsertEventSQL = `
+INSERT INTO app.events (slug, title, game, league, starts_at, created_at, updated_at)
+VALUES (@slug, @title, @game, NULLIF(@league, ''), COALESCE(CAST(@starts_at AS timestamptz), now()), now(), now())
+ON CONFLICT (slug) DO UPDATE SET
+  starts_at  = COALESCE(CAST(@starts_at AS timestamptz), app.events.starts_at),
+  updated_at = now()
+RETURNING uuid`
+
+	upsertCreatorSQL = `
+INSERT INTO app.creators (platform, handle, url, lang, country, verified, created_at, updated_at)
+VALUES (@platform, @handle, @url, @lang, @country, COALESCE(CAST(@verified AS boolean), false), now(), now())
+ON CONFLICT (platform, handle) DO UPDATE SET
+  url        = COALESCE(NULLIF(EXCLUDED.url, ''), app.creators.url),
+  lang       = COALESCE(NULLIF(EXCLUDED.lang, ''), app.creators.lang),
+  country    = COALESCE(NULLIF(EXCLUDED.country, ''), app.creators.country),
+  verified   = COALESCE(CAST(@verified AS boolean), app.creators.verified),
+  updated_at = now()
+RETURNING uuid`
+
+	upsertCoStreamSQL = `
+INSERT INTO app.co_streams (event_uuid, creator_uuid, platform, url, lang, country, viewers, verified, is_live, last_seen_at, created_at, updated_at)
+VALUES (@event_uuid, @creator_uuid, @platform, @url, @lang, @country, @viewers, COALESCE(CAST(@verified AS boolean), false), @is_live, now(), now(), now())
+ON CONFLICT (event_uuid, creator_uuid) DO UPDATE SET
+  platform     = EXCLUDED.platform,
+  url          = COALESCE(NULLIF(EXCLUDED.url, ''), app.co_streams.url),
+  lang         = COALESCE(NULLIF(EXCLUDED.lang, ''), app.co_streams.lang),
+  country      = COALESCE(NULLIF(EXCLUDED.country, ''), app.co_streams.country),
+  viewers      = EXCLUDED.viewers,
+  verified     = COALESCE(CAST(@verified AS boolean), app.co_streams.verified),
+  is_live      = EXCLUDED.is_live,
+  last_seen_at = EXCLUDED.last_seen_at,
+  updated_at   = now()`
+)
+
+// UpsertCoStream sube evento, creador y co-stream con INSERT ... ON CONFLICT en una sola
+// transacción (la política por campo está en out.CoStreamUpsert). Cada llamada deja una
+// fila en ingestion_logs; si falla, la fila de error se escribe fuera de la transacción.
+func (r *Repo) UpsertCoStream(ctx context.Context, in out.CoStreamUpsert) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var eventUUID, creatorUUID uuid.UUID
+		if err := tx.Raw(upsertEventSQL, map[string]any{
+			"slug":      in.EventSlug,
+			"title":     in.EventTitle,
+			"game":      in.Game,
+			"league":    in.League,
+			"starts_at": in.StartsAt,
+		}).Row().Scan(&eventUUID); err != nil {
+			return fmt.Errorf("upsert event %q: %w", in.EventSlug, err)
 		}
-		if err := db.Call(r.db.WithContext(ctx).Create(&event)).Error; err != nil {
-			return err
+		if err := tx.Raw(upsertCreatorSQL, map[string]any{
+			"platform": in.Platform,
+			"handle":   in.Handle,
+			"url":      in.URL,
+			"lang":     in.Lang,
+			"country":  in.Country,
+			"verified": in.Verified,
+		}).Row().Scan(&creatorUUID); err != nil {
+			return fmt.Errorf("upsert creator %s:%s: %w", in.Platform, in.Handle, err)
 		}
-	} else if result.Error != nil {
-		return result.Error
-	}
-
-	// Encontrar o crear el creator
-	var creator entities.Creator
-	result = db.Call(r.db.WithContext(ctx).Where("platform = ? AND handle = ?", platform, handle).First(&creator))
-	if result.Error == gorm.ErrRecordNotFound {
-		creator = entities.Creator{
-			Platform: platform,
-			Handle:   handle,
-			URL:      url,
-			Lang:     lang,
-			Country:  country,
-			Verified: verified,
+		if err := db.Call(tx.Exec(upsertCoStreamSQL, map[string]any{
+			"event_uuid":   eventUUID,
+			"creator_uuid": creatorUUID,
+			"platform":     in.Platform,
+			"url":          in.URL,
+			"lang":         in.Lang,
+			"country":      in.Country,
+			"viewers":      in.Viewers,
+			"verified":     in.Verified,
+			"is_live":      in.IsLive,
+		})).Error; err != nil {
+			return fmt.Errorf("upsert co-stream: %w", err)
 		}
-		if err := db.Call(r.db.WithContext(ctx).Create(&creator)).Error; err != nil {
-			return err
+		return db.Call(tx.Create(ingestionLog(in, nil))).Error
+	})
+	if err != nil {
+		if logErr := db.Call(r.db.WithContext(ctx).Create(ingestionLog(in, err))).Error; logErr != nil {
+			log.Ctx(ctx).Error().Err(logErr).Str("entity_id", in.Platform+":"+in.Handle).Msg("write ingestion log failed")
 		}
-	} else if result.Error != nil {
-		return result.Error
-	}
-
-	// Luego, upsert el co-stream
-	coStream := entities.CoStream{
-		EventUUID:   event.UUID,
-		CreatorUUID: creator.UUID,
-		Platform:    platform,
-		URL:         url,
-		Lang:        lang,
-		Country:     country,
-		Viewers:     viewers,
-		Verified:    verified,
-		IsLive:      isLive,
-		LastSeenAt:  time.Now(),
-	}
-
-	return db.Call(r.db.WithContext(ctx).Where("event_uuid = ? AND creator_uuid = ?", event.UUID, creator.UUID).
-		Assign(coStream).
-		FirstOrCreate(&coStream)).Error
+	}
+	return err
+}
+
+// ingestionLog fila de auditoría de una ingesta de co-stream
+func ingestionLog(in out.CoStreamUpsert, err error) *entities.IngestionLog {
+	row := &entities.IngestionLog{
+		Source:      in.Source,
+		EntityType:  entities.IngestionEntityCoStream,
+		EntityID:    in.Platform + ":" + in.Handle,
+		Status:      entities.IngestionStatusSuccess,
+		ProcessedAt: time.Now(),
+	}
+	if err != nil {
+		row.Status = entities.IngestionStatusError
+		row.ErrorMsg = err.Error()
+	}
+	return row
 }
 
 func (r *Repo) MarkStaleCoStreamsOffline(ctx context.Context, olderThan time.Duration) (int64, error) {
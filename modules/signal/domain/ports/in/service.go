@@ -3,15 +3,185 @@ package in
 import (
 	"context"
 
+	"github.com/google/uuid"
 	"github.com/steven230500/hypeatlas-api/domain/entities"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
 )
 
 type Service interface {
 	// Patches & Changes
 	ListPatches(ctx context.Context, game string) ([]entities.Patch, error)
 	ListChanges(ctx context.Context, game, version, entityType string) ([]entities.PatchChange, error)
+	ImpactSummary(ctx context.Context, game, version string, top int) (*ImpactSummary, error)
+	ChangesRange(ctx context.Context, game, from, to, entityType, entity string) (*ChangeRange, error)
 
 	// Leagues & Comps
 	ListLeagues(ctx context.Context, game, region string) ([]entities.League, error)
-	ListComps(ctx context.Context, game, region, league, patch, mapp, side string, limit int) ([]entities.Comp, error)
+	ListComps(ctx context.Context, q out.CompQuery) ([]entities.Comp, error)
+	Synergy(ctx context.Context, q out.CompQuery, with string, minGames int) (*SynergyReport, error)
+	CompHistory(ctx context.Context, id uuid.UUID, limit int) (*CompHistory, error)
+	CompMovers(ctx context.Context, q out.CompQuery, from string) (*CompMovers, error)
+
+	// Tier list
+	TierList(ctx context.Context, q TierListQuery) (*TierList, error)
+}
+
+// TierListQuery filtros del tier list (Game obligatorio). Con League se usan las estadísticas
+// de la liga profesional (Season, por defecto la más reciente); si no, las partidas ranked
+// ingeridas de Patch (por defecto el último) y Platform ("" = todas).
+type TierListQuery struct {
+	Game     string
+	Patch    string
+	Role     string
+	Platform string
+	League   string
+	Season   string
+}
+
+// TierList tiers por rol calculados con las tasas medidas
+type TierList struct {
+	Game        string          `json:"game"`
+	Source      string          `json:"source"` // ranked|pro
+	Patch       string          `json:"patch,omitempty"`
+	Platform    string          `json:"platform,omitempty"`
+	League      string          `json:"league,omitempty"`
+	Season      string          `json:"season,omitempty"`
+	Matches     int             `json:"matches"`
+	Roles       []TierRole      `json:"roles"`
+	Methodology TierMethodology `json:"methodology"`
+}
+
+// TierRole campeones (o agentes) de un rol ordenados por score
+type TierRole struct {
+	Role            string      `json:"role"`
+	BaselineWinRate float64     `json:"baseline_win_rate"` // win rate de todo el rol (%)
+	Entries         []TierEntry `json:"entries"`
+}
+
+// TierEntry campeón (o agente) en un rol con su tier
+type TierEntry struct {
+	ChampionID      int     `json:"champion_id,omitempty"`
+	Name            string  `json:"name"`
+	Role            string  `json:"role"`
+	Tier            string  `json:"tier"`
+	Score           float64 `json:"score"`
+	Games           int     `json:"games"`
+	RoleShare       float64 `json:"role_share"`        // % de sus partidas jugadas en este rol
+	PickRate        float64 `json:"pick_rate"`         // % de partidas en las que se eligió en este rol
+	WinRate         float64 `json:"win_rate"`          // %
+	AdjustedWinRate float64 `json:"adjusted_win_rate"` // win rate suavizado hacia el del rol
+	BanRate         float64 `json:"ban_rate"`          // % de partidas en las que se baneó
+}
+
+// TierMethodology cómo se calcularon los tiers
+type TierMethodology struct {
+	Summary      string             `json:"summary"`
+	Weights      map[string]float64 `json:"weights"`
+	Cuts         []TierCut          `json:"cuts"`
+	MinGames     int                `json:"min_games"`
+	MinRoleShare float64            `json:"min_role_share"`
+	PriorGames   int                `json:"prior_games"`
+}
+
+// TierCut score mínimo de un tier
+type TierCut struct {
+	Tier     string  `json:"tier"`
+	MinScore float64 `json:"min_score"`
+}
+
+// CompHistory evolución de una comp entre snapshots y parches
+type CompHistory struct {
+	Comp  entities.Comp           `json:"comp"`
+	Items []entities.CompSnapshot `json:"items"`
+}
+
+// CompMovers comps que más subieron y bajaron de win rate entre dos parches
+type CompMovers struct {
+	Game    string      `json:"game"`
+	From    string      `json:"from"`
+	Patch   string      `json:"patch"`
+	Risers  []CompMover `json:"risers"`
+	Fallers []CompMover `json:"fallers"`
+}
+
+// CompMover cambio de una comp entre From y Patch, con los cambios de balance de Patch
+// que tocan a sus miembros (causas probables)
+type CompMover struct {
+	Comp           entities.Comp          `json:"comp"`
+	PrevGames      int                    `json:"prev_games"`
+	PrevWinRate    float64                `json:"prev_win_rate"`
+	PrevPickRate   float64                `json:"prev_pick_rate"`
+	WinRateChange  float64                `json:"win_rate_change"`
+	PickRateChange float64                `json:"pick_rate_change"`
+	Changes        []entities.PatchChange `json:"changes"`
+}
+
+// SynergyReport compañeros de un agente/campeón según las comps guardadas
+type SynergyReport struct {
+	Game    string           `json:"game"`
+	With    string           `json:"with"`
+	Games   int              `json:"games"`    // partidas de comps que lo incluyen
+	WinRate float64          `json:"win_rate"` // win rate de esas comps (%)
+	Comps   int              `json:"comps"`
+	Pairs   []SynergyPartner `json:"pairs"`
+}
+
+// SynergyPartner co-ocurrencia y lift de un compañero
+type SynergyPartner struct {
+	Member       string  `json:"member"`
+	Games        int     `json:"games"`
+	CoOccurrence float64 `json:"co_occurrence"` // % de las partidas de With en que aparece
+	WinRate      float64 `json:"win_rate"`
+	Lift         float64 `json:"lift"` // WinRate - win rate de With (puntos)
+	WinCILow     float64 `json:"win_ci_low"`
+	WinCIHigh    float64 `json:"win_ci_high"`
+}
+
+// ImpactSummary cambios más impactantes de un parche por tipo de entidad
+type ImpactSummary struct {
+	Game  string              `json:"game"`
+	Patch string              `json:"patch"`
+	Types []ImpactTypeSummary `json:"types"`
+}
+
+// ImpactTypeSummary ranking de un tipo de entidad (champion, agent, item, ...)
+type ImpactTypeSummary struct {
+	EntityType string                 `json:"entity_type"`
+	Entities   []ImpactEntity         `json:"entities"`
+	TopChanges []entities.PatchChange `json:"top_changes"`
+}
+
+// ImpactEntity impacto combinado de los cambios de una entidad (suma, tope 10)
+type ImpactEntity struct {
+	EntityID string  `json:"entity_id"`
+	Score    float64 `json:"score"`
+	Changes  int     `json:"changes"`
+}
+
+// ChangeRange efecto neto de los cambios entre dos versiones
+type ChangeRange struct {
+	Game    string      `json:"game"`
+	From    string      `json:"from"`
+	To      string      `json:"to"`
+	Patches []string    `json:"patches"` // parches del rango (From, To], en orden de versión
+	Items   []NetChange `json:"items"`
+}
+
+// NetChange valor de un campo antes del primer cambio del rango y después del último
+type NetChange struct {
+	EntityType string       `json:"entity_type"`
+	EntityID   string       `json:"entity_id"`
+	Field      string       `json:"field"`
+	Old        string       `json:"old"`
+	New        string       `json:"new"`
+	Changed    bool         `json:"changed"` // false si los cambios intermedios se anulan
+	Steps      []ChangeStep `json:"steps"`
+}
+
+// ChangeStep cambio de un parche que contribuye a un NetChange
+type ChangeStep struct {
+	Patch       string  `json:"patch"`
+	Old         string  `json:"old"`
+	New         string  `json:"new"`
+	ImpactScore float64 `json:"impact_score"`
 }
@@ -2,7 +2,9 @@ package out
 
 import (
 	"context"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/steven230500/hypeatlas-api/domain/entities"
 )
 
@@ -10,11 +12,96 @@ type Repository interface {
 	// Patches & Changes
 	PatchesByGame(ctx context.Context, game string) ([]entities.Patch, error)
 	PatchChanges(ctx context.Context, game, version, entityType string) ([]entities.PatchChange, error)
+	PatchChangesByPatches(ctx context.Context, patchUUIDs []uuid.UUID, entityType, entityID string) ([]entities.PatchChange, error)
+	UpsertPatchChanges(ctx context.Context, game, version string, releasedAt *time.Time, changes []entities.PatchChange) (*entities.Patch, error)
+	UpdatePatchChangeScores(ctx context.Context, scores map[int64]float64) error
+	EntityPickRates(ctx context.Context, game, patch string) (map[string]float64, error)
 
 	// Leagues & Comps
 	Leagues(ctx context.Context, game, region string) ([]entities.League, error)
-	Comps(ctx context.Context, game, region, league, patch, mapp, side string, limit int) ([]entities.Comp, error)
+	Comps(ctx context.Context, q CompQuery) ([]entities.Comp, error)
+	SynergyComps(ctx context.Context, q CompQuery) ([]entities.Comp, error)
+	PatchComps(ctx context.Context, q CompQuery) ([]entities.Comp, error)
+	CompPatches(ctx context.Context, game string) ([]string, error)
+	CompByUUID(ctx context.Context, id uuid.UUID) (*entities.Comp, error)
+	CompHistory(ctx context.Context, comp *entities.Comp, limit int) ([]entities.CompSnapshot, error)
+
+	// Pro leagues
+	ProfessionalLeagues(ctx context.Context, game string) ([]entities.ProfessionalLeague, error)
+	ProfessionalLeague(ctx context.Context, code string) (*entities.ProfessionalLeague, error)
+	EnsureProfessionalLeague(ctx context.Context, league *entities.ProfessionalLeague) error
+	LeagueSeasons(ctx context.Context, code string) ([]LeagueSeason, error)
+	LeagueChampionStats(ctx context.Context, code, season string) ([]entities.LeagueChampionStats, error)
+	ReplaceLeagueChampionStats(ctx context.Context, code, season string, rows []entities.LeagueChampionStats) error
+	UpsertProMatches(ctx context.Context, matches []entities.ProMatch) error
+	ProMatches(ctx context.Context, code, season string) ([]entities.ProMatch, error)
 
 	// Ingest
-	UpsertComp(ctx context.Context, game, region, league, patch, mapp, side string, slotsJSON string, pickRate, winRate, deltaWin *float64) error
+	UpsertComp(ctx context.Context, comp *entities.Comp) error
+
+	// Match-V5
+	KnownRiotMatches(ctx context.Context, matchIDs []string) (map[string]bool, error)
+	RegisterRiotMatch(ctx context.Context, match *entities.RiotMatch) (bool, error)
+	RecordRiotMatch(ctx context.Context, match *entities.RiotMatch, rows []entities.ChampionMatchStats) (bool, error)
+	RefreshChampionMatchRates(ctx context.Context, platform, patch string, queueID int) error
+	ChampionMatchStats(ctx context.Context, platform, patch string, queueID int) ([]entities.ChampionMatchStats, error)
+	LatestMatchPatch(ctx context.Context, platform string, queueID int) (string, error)
+	RiotMatchesByPatch(ctx context.Context, game, patch string) ([]entities.RiotMatch, error)
+	RiotMatchPatches(ctx context.Context, game, platform string) ([]string, error)
+	RiotMatchesSince(ctx context.Context, game, platform string, queueID int, since time.Time) ([]entities.RiotMatch, error)
+
+	// Meta-game (snapshots)
+	SaveChampionRotation(ctx context.Context, rotation *entities.ChampionRotation) (bool, error)
+	ChampionRotationHistory(ctx context.Context, game, platform string, limit int) ([]entities.ChampionRotation, error)
+	SaveLeagueRanking(ctx context.Context, ranking *entities.LeagueRanking) error
+	LeagueRankingHistory(ctx context.Context, game, platform, queue, tier string, limit int) ([]entities.LeagueRanking, error)
+	SaveMetaAnalysis(ctx context.Context, analysis *entities.MetaGameAnalysis) error
+	LatestMetaAnalysis(ctx context.Context, game, platform, analysisType string) (*entities.MetaGameAnalysis, error)
+	MetaAnalysisHistory(ctx context.Context, game, platform, analysisType string, limit int) ([]entities.MetaGameAnalysis, error)
+
+	// Mastery
+	ReplaceChampionMasteryStats(ctx context.Context, game, platform string, rows []entities.ChampionMasteryStats) error
+	ChampionMasteryStats(ctx context.Context, game, platform string, q MasteryQuery) ([]entities.ChampionMasteryStats, error)
+}
+
+// MasteryQuery filtros para listar estadísticas de maestría
+type MasteryQuery struct {
+	ChampionID   int    // 0 = todos
+	ChampionName string // búsqueda parcial, case-insensitive
+	Sort         string // avg_points|top_points|avg_level|players
+	Limit        int
+}
+
+// LeagueSeason temporada con estadísticas de una liga profesional
+type LeagueSeason struct {
+	LeagueCode  string    `json:"league_code"`
+	Season      string    `json:"season"`
+	Games       int       `json:"games"`
+	LastUpdated time.Time `json:"last_updated"`
 }
+
+// CompQuery filtros para listar composiciones (Game, Region y Patch obligatorios)
+type CompQuery struct {
+	Game   string
+	Region string
+	League string
+	Patch  string
+	Map    string
+	Side   string // LoL: blue|red, VAL: attack|defense
+	Shape  string // full|bot_duo|jungle_mid ("" = todas)
+
+	Includes []string // agentes/campeones que la comp debe contener (todos)
+	Excludes []string // agentes/campeones que la comp no debe contener
+
+	MinGames int    // descarta comps con menos partidas
+	Sort     string // wilson (default)|win_rate|pick_rate|delta
+	Limit    int
+}
+
+// Ordenaciones de CompQuery.Sort
+const (
+	CompSortWilson   = "wilson"
+	CompSortWinRate  = "win_rate"
+	CompSortPickRate = "pick_rate"
+	CompSortDelta    = "delta"
+)
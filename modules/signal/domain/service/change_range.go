@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/google/uuid"
+
+	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	"github.com/steven230500/hypeatlas-api/shared/version"
+)
+
+// ChangesRange pliega los cambios de los parches (from, to] en un old→new neto por campo.
+// Las versiones se ordenan por segmentos numéricos (14.9 < 14.10), no como texto.
+func (s *svc) ChangesRange(ctx context.Context, game, from, to, entityType, entity string) (*in.ChangeRange, error) {
+	if game == "" || from == "" || to == "" {
+		return nil, errors.New("game, from and to required")
+	}
+	if version.Compare(from, to) >= 0 {
+		return nil, errors.New("from must be lower than to")
+	}
+
+	patches, err := s.repo.PatchesByGame(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(patches, func(i, j int) bool { return version.Compare(patches[i].Version, patches[j].Version) < 0 })
+
+	result := &in.ChangeRange{Game: game, From: from, To: to, Patches: []string{}, Items: []in.NetChange{}}
+	versions := make(map[uuid.UUID]string)
+	order := make(map[uuid.UUID]int)
+	var ids []uuid.UUID
+	for _, p := range patches {
+		if version.Compare(p.Version, from) > 0 && version.Compare(p.Version, to) <= 0 {
+			versions[p.UUID] = p.Version
+			order[p.UUID] = len(ids)
+			ids = append(ids, p.UUID)
+			result.Patches = append(result.Patches, p.Version)
+		}
+	}
+
+	changes, err := s.repo.PatchChangesByPatches(ctx, ids, entityType, entity)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(changes, func(i, j int) bool { return order[changes[i].PatchUUID] < order[changes[j].PatchUUID] })
+
+	type fieldKey struct{ entityType, entityID, field string }
+	index := make(map[fieldKey]int)
+	for _, c := range changes {
+		key := fieldKey{c.EntityType, c.EntityID, c.Field}
+		step := in.ChangeStep{Patch: versions[c.PatchUUID], Old: c.Old, New: c.New, ImpactScore: c.ImpactScore}
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(result.Items)
+			result.Items = append(result.Items, in.NetChange{
+				EntityType: c.EntityType, EntityID: c.EntityID, Field: c.Field,
+				Old: c.Old, New: c.New, Steps: []in.ChangeStep{step},
+			})
+			continue
+		}
+		item := &result.Items[i]
+		item.New = c.New
+		item.Steps = append(item.Steps, step)
+	}
+
+	for i := range result.Items {
+		result.Items[i].Changed = result.Items[i].Old != result.Items[i].New
+	}
+	sort.SliceStable(result.Items, func(i, j int) bool {
+		a, b := result.Items[i], result.Items[j]
+		if a.EntityType != b.EntityType {
+			return a.EntityType < b.EntityType
+		}
+		if a.EntityID != b.EntityID {
+			return a.EntityID < b.EntityID
+		}
+		return a.Field < b.Field
+	})
+	return result, nil
+}
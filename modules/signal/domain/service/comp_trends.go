@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+	"github.com/steven230500/hypeatlas-api/shared/version"
+)
+
+// ErrCompNotFound la comp pedida no existe
+var ErrCompNotFound = errors.New("comp not found")
+
+func (s *svc) CompHistory(ctx context.Context, id uuid.UUID, limit int) (*in.CompHistory, error) {
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+	comp, err := s.repo.CompByUUID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if comp == nil {
+		return nil, ErrCompNotFound
+	}
+	items, err := s.repo.CompHistory(ctx, comp, limit)
+	if err != nil {
+		return nil, err
+	}
+	return &in.CompHistory{Comp: *comp, Items: items}, nil
+}
+
+// CompMovers compara las comps de q.Patch con las de from (por defecto el parche anterior)
+// y devuelve las q.Limit que más subieron y más bajaron de win rate. Solo entran comps con
+// q.MinGames partidas en ambos parches.
+func (s *svc) CompMovers(ctx context.Context, q out.CompQuery, from string) (*in.CompMovers, error) {
+	if q.Game == "" || q.Patch == "" {
+		return nil, errors.New("game and patch required")
+	}
+	if q.Limit <= 0 || q.Limit > 100 {
+		q.Limit = 10
+	}
+	q.Includes = normalizeMembers(q.Game, q.Includes)
+	q.Excludes = normalizeMembers(q.Game, q.Excludes)
+
+	if from == "" {
+		prev, err := s.previousCompPatch(ctx, q.Game, q.Patch)
+		if err != nil {
+			return nil, err
+		}
+		if prev == "" {
+			return nil, errors.New("no previous patch with comps; pass from")
+		}
+		from = prev
+	}
+
+	current, err := s.repo.PatchComps(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+	prevQuery := q
+	prevQuery.Patch = from
+	previous, err := s.repo.PatchComps(ctx, prevQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	type identity struct{ region, league, mapp, side, fp string }
+	before := make(map[identity]entities.Comp, len(previous))
+	for _, c := range previous {
+		before[identity{c.Region, c.League, c.Map, c.Side, c.SlotsFP}] = c
+	}
+
+	var moves []in.CompMover
+	for _, c := range current {
+		prev, ok := before[identity{c.Region, c.League, c.Map, c.Side, c.SlotsFP}]
+		if !ok || c.WinRate == nil || prev.WinRate == nil {
+			continue
+		}
+		move := in.CompMover{
+			Comp:          c,
+			PrevGames:     prev.Games,
+			PrevWinRate:   *prev.WinRate,
+			WinRateChange: stats.Round(*c.WinRate-*prev.WinRate, 3),
+		}
+		if prev.PickRate != nil {
+			move.PrevPickRate = *prev.PickRate
+		}
+		if c.PickRate != nil {
+			move.PickRateChange = stats.Round(*c.PickRate-move.PrevPickRate, 3)
+		}
+		moves = append(moves, move)
+	}
+
+	sort.Slice(moves, func(i, j int) bool {
+		if moves[i].WinRateChange != moves[j].WinRateChange {
+			return moves[i].WinRateChange > moves[j].WinRateChange
+		}
+		return moves[i].Comp.Games > moves[j].Comp.Games
+	})
+
+	result := &in.CompMovers{Game: q.Game, From: from, Patch: q.Patch, Risers: []in.CompMover{}, Fallers: []in.CompMover{}}
+	for i := 0; i < len(moves) && len(result.Risers) < q.Limit && moves[i].WinRateChange > 0; i++ {
+		result.Risers = append(result.Risers, moves[i])
+	}
+	for i := len(moves) - 1; i >= 0 && len(result.Fallers) < q.Limit && moves[i].WinRateChange < 0; i-- {
+		result.Fallers = append(result.Fallers, moves[i])
+	}
+
+	if err := s.attachPatchChanges(ctx, q.Game, q.Patch, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// previousCompPatch busca el parche anterior en app.patches y, si no está, entre los parches con comps
+func (s *svc) previousCompPatch(ctx context.Context, game, patch string) (string, error) {
+	patches, err := s.repo.PatchesByGame(ctx, game)
+	if err != nil {
+		return "", err
+	}
+	versions := make([]string, 0, len(patches))
+	for _, p := range patches {
+		versions = append(versions, p.Version)
+	}
+	if prev := version.Previous(versions, patch); prev != "" {
+		return prev, nil
+	}
+	compPatches, err := s.repo.CompPatches(ctx, game)
+	if err != nil {
+		return "", err
+	}
+	return version.Previous(compPatches, patch), nil
+}
+
+// attachPatchChanges asocia a cada mover los cambios de balance del parche que tocan a sus miembros
+func (s *svc) attachPatchChanges(ctx context.Context, game, patch string, movers *in.CompMovers) error {
+	entityType := "champion"
+	if game == "val" {
+		entityType = "agent"
+	}
+	changes, err := s.repo.PatchChanges(ctx, game, patch, entityType)
+	if err != nil {
+		return err
+	}
+	byMember := make(map[string][]entities.PatchChange)
+	for _, c := range changes {
+		key := strings.ToLower(normalizeMember(game, c.EntityID))
+		byMember[key] = append(byMember[key], c)
+	}
+
+	attach := func(list []in.CompMover) {
+		for i := range list {
+			list[i].Changes = []entities.PatchChange{}
+			var slots entities.CompSlots
+			if err := json.Unmarshal(list[i].Comp.Slots, &slots); err != nil {
+				continue
+			}
+			for _, m := range slots.Members {
+				name := m.Agent
+				if name == "" {
+					name = m.Champion
+				}
+				list[i].Changes = append(list[i].Changes, byMember[strings.ToLower(name)]...)
+			}
+		}
+	}
+	attach(movers.Risers)
+	attach(movers.Fallers)
+	return nil
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+)
+
+// compKey agrupación de app.comps dentro de un juego y parche
+type compKey struct {
+	Region, League, Map, Side, Shape string
+}
+
+// group devuelve la clave sin lado (denominador del pick rate)
+func (k compKey) group() compKey {
+	k.Side = ""
+	return k
+}
+
+// compTally contador de partidas, muestras y victorias; las muestras son partidas o
+// rondas según el juego y lado
+type compTally struct {
+	games, samples, wins int
+}
+
+// compAggregator acumula picks y resultados por comp y genera las filas de app.comps:
+//
+//   - games: partidas de la comp en esa fila
+//   - pick_rate: % de equipos del grupo (region, league, map, shape) que jugaron la comp
+//   - win_rate: % de muestras ganadas en ese lado, con su intervalo de Wilson al 95%
+//   - delta_win: win_rate menos el win rate de todo el grupo en el mismo lado
+type compAggregator struct {
+	results  map[compKey]map[string]*compTally // clave -> slots JSON -> resultado
+	baseline map[compKey]*compTally
+	teams    map[compKey]int
+	picks    map[compKey]map[string]int
+}
+
+func newCompAggregator() *compAggregator {
+	return &compAggregator{
+		results:  make(map[compKey]map[string]*compTally),
+		baseline: make(map[compKey]*compTally),
+		teams:    make(map[compKey]int),
+		picks:    make(map[compKey]map[string]int),
+	}
+}
+
+// slotsJSON serializa los slots canónicos (el mismo texto que se guarda y se hashea)
+func slotsJSON(slots entities.CompSlots) (string, error) {
+	raw, err := json.Marshal(slots)
+	return string(raw), err
+}
+
+// pick cuenta un equipo del grupo que jugó la comp
+func (a *compAggregator) pick(key compKey, slots string) {
+	group := key.group()
+	a.teams[group]++
+	if a.picks[group] == nil {
+		a.picks[group] = make(map[string]int)
+	}
+	a.picks[group][slots]++
+}
+
+// result suma una partida de la comp en key (incluido el lado) con sus muestras/victorias
+func (a *compAggregator) result(key compKey, slots string, samples, wins int) {
+	if samples == 0 {
+		return
+	}
+	if a.results[key] == nil {
+		a.results[key] = make(map[string]*compTally)
+	}
+	t := a.results[key][slots]
+	if t == nil {
+		t = &compTally{}
+		a.results[key][slots] = t
+	}
+	t.games++
+	t.samples += samples
+	t.wins += wins
+
+	b := a.baseline[key]
+	if b == nil {
+		b = &compTally{}
+		a.baseline[key] = b
+	}
+	b.games++
+	b.samples += samples
+	b.wins += wins
+}
+
+// rows genera las comps agregadas de un juego y parche
+func (a *compAggregator) rows(game, patch string) []entities.Comp {
+	var rows []entities.Comp
+	for key, bySlots := range a.results {
+		group := key.group()
+		base := a.baseline[key]
+		baseWin := 100 * float64(base.wins) / float64(base.samples)
+		for slots, t := range bySlots {
+			pick := stats.Round(100*float64(a.picks[group][slots])/float64(a.teams[group]), 3)
+			win := stats.Round(100*float64(t.wins)/float64(t.samples), 3)
+			delta := stats.Round(win-baseWin, 3)
+			low, high := stats.WilsonInterval(t.wins, t.samples, stats.Z95)
+			low, high = stats.Round(100*low, 3), stats.Round(100*high, 3)
+			rows = append(rows, entities.Comp{
+				Game: game, Region: key.Region, League: key.League, Patch: patch,
+				Map: key.Map, Side: key.Side, Shape: key.Shape, Slots: []byte(slots),
+				Games: t.games, PickRate: &pick, WinRate: &win, DeltaWin: &delta,
+				WinCILow: &low, WinCIHigh: &high,
+			})
+		}
+	}
+	return rows
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+	"github.com/steven230500/hypeatlas-api/shared/version"
+)
+
+// CrossRegionPlatform valor de platform con el que se guardan los reportes multi-plataforma
+const CrossRegionPlatform = "multi"
+
+// Umbrales de sobre/infra-representación regional de un campeón
+const (
+	crossRegionOverRatio   = 1.5 // pick rate ≥ 1.5× la media entre regiones
+	crossRegionUnderRatio  = 0.67
+	crossRegionMinPickRate = 2.0 // % medio mínimo para considerar al campeón
+	crossRegionOutliers    = 5   // campeones por región y lista
+)
+
+// ErrCrossRegionPlatforms se piden menos de dos plataformas
+var ErrCrossRegionPlatforms = errors.New("cross-region report needs at least two platforms")
+
+// CrossRegionOptions configura el reporte multi-plataforma
+type CrossRegionOptions struct {
+	Queue       string // default RANKED_SOLO_5x5
+	Concurrency int    // plataformas analizadas en paralelo (default 3)
+}
+
+// CrossRegionReport comparación del meta entre plataformas
+type CrossRegionReport struct {
+	Platforms   []string                 `json:"platforms"`
+	Queue       string                   `json:"queue"`
+	GeneratedAt time.Time                `json:"generated_at"`
+	Regions     []RegionSummary          `json:"regions"`
+	Tiers       []CrossRegionTier        `json:"tiers"`
+	Champions   []ChampionRegionPresence `json:"champions"`
+	Errors      map[string]string        `json:"errors,omitempty"` // plataformas que fallaron
+	Insights    []string                 `json:"insights"`
+}
+
+// RegionSummary resumen de una plataforma dentro del reporte
+type RegionSummary struct {
+	Platform             string            `json:"platform"`
+	Patch                string            `json:"patch"`
+	ApexPlayers          int               `json:"apex_players"`
+	Cutoffs              map[string]int    `json:"cutoffs"`
+	RotationImpact       float64           `json:"rotation_impact"`
+	MetaShiftProbability float64           `json:"meta_shift_probability"`
+	FreeChampions        []string          `json:"free_champions"`
+	Matches              int               `json:"matches"` // partidas ranked detrás de los pick rates
+	Overrepresented      []RegionalOutlier `json:"overrepresented"`
+	Underrepresented     []RegionalOutlier `json:"underrepresented"`
+	AvgWinRate           float64           `json:"avg_win_rate"` // media de todos los jugadores apex
+}
+
+// CrossRegionTier distribuciones de un tier por plataforma y su dispersión
+type CrossRegionTier struct {
+	Tier          string                        `json:"tier"`
+	Players       map[string]int                `json:"players"`
+	LP            map[string]stats.Distribution `json:"lp"`
+	WinRate       map[string]stats.Distribution `json:"win_rate"`
+	Cutoffs       map[string]int                `json:"cutoffs"`
+	MedianLPGap   float64                       `json:"median_lp_gap"`   // máx - mín de la mediana de LP
+	CutoffGap     int                           `json:"cutoff_gap"`      // máx - mín del cutoff
+	WinRateSpread float64                       `json:"win_rate_spread"` // máx - mín del win rate medio
+}
+
+// ChampionRegionPresence pick rate de un campeón en cada plataforma
+type ChampionRegionPresence struct {
+	ChampionID   int                `json:"champion_id"`
+	Name         string             `json:"name"`
+	PickRates    map[string]float64 `json:"pick_rates"`
+	MeanPickRate float64            `json:"mean_pick_rate"`
+	Spread       float64            `json:"spread"` // máx - mín entre plataformas
+}
+
+// RegionalOutlier campeón sobre o infra-representado en una plataforma
+type RegionalOutlier struct {
+	ChampionID   int     `json:"champion_id"`
+	Name         string  `json:"name"`
+	PickRate     float64 `json:"pick_rate"`
+	MeanPickRate float64 `json:"mean_pick_rate"`
+	Ratio        float64 `json:"ratio"`
+}
+
+// regionResult análisis crudo de una plataforma
+type regionResult struct {
+	platform string
+	rotation *ChampionRotationAnalysis
+	version  string
+	ladder   *LadderAnalysis
+	tiers    *in.TierList
+	err      error
+}
+
+// CrossRegionReport analiza rotación, ladder apex y pick rates de varias plataformas en paralelo
+// (como mucho opts.Concurrency a la vez; cada petición sigue pasando por el rate limiter de Riot)
+// y las compara. Las plataformas que fallan quedan en Errors; falla si quedan menos de dos.
+func (s *MetaGameService) CrossRegionReport(ctx context.Context, platforms []string, opts CrossRegionOptions) (*CrossRegionReport, error) {
+	platforms = uniquePlatforms(platforms)
+	if len(platforms) < 2 {
+		return nil, ErrCrossRegionPlatforms
+	}
+	if opts.Queue == "" {
+		opts.Queue = "RANKED_SOLO_5x5"
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 3
+	}
+
+	results := make([]regionResult, len(platforms))
+	sem := make(chan struct{}, opts.Concurrency)
+	var wg sync.WaitGroup
+	for i, platform := range platforms {
+		wg.Add(1)
+		go func(i int, platform string) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				results[i] = regionResult{platform: platform, err: ctx.Err()}
+				return
+			}
+			results[i] = s.analyzeRegion(ctx, platform, opts.Queue)
+		}(i, platform)
+	}
+	wg.Wait()
+
+	var ok []regionResult
+	errs := make(map[string]string)
+	for _, r := range results {
+		if r.err != nil {
+			errs[r.platform] = r.err.Error()
+			continue
+		}
+		ok = append(ok, r)
+	}
+	if len(ok) < 2 {
+		return nil, fmt.Errorf("cross-region report: only %d of %d platforms analyzed: %v", len(ok), len(platforms), errs)
+	}
+
+	report := buildCrossRegionReport(opts.Queue, ok)
+	if len(errs) > 0 {
+		report.Errors = errs
+	}
+	return report, nil
+}
+
+// SnapshotCrossRegion calcula el reporte multi-plataforma y lo guarda con analysis_type=cross_region
+func (s *MetaGameService) SnapshotCrossRegion(ctx context.Context, platforms []string, opts CrossRegionOptions) (*CrossRegionReport, error) {
+	report, err := s.CrossRegionReport(ctx, platforms, opts)
+	if err != nil {
+		return nil, err
+	}
+	patch := ""
+	for _, r := range report.Regions {
+		if r.Patch != "" && (patch == "" || version.Compare(r.Patch, patch) > 0) {
+			patch = r.Patch
+		}
+	}
+	if err := s.saveAnalysis(ctx, CrossRegionPlatform, patch, AnalysisTypeCrossRegion, "snapshot", report, report.Insights); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+// analyzeRegion corre los análisis de una plataforma
+func (s *MetaGameService) analyzeRegion(ctx context.Context, platform, queue string) regionResult {
+	res := regionResult{platform: platform}
+	rotation, _, ddVersion, err := s.analyzeRotation(ctx, platform)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	ladder, err := s.AnalyzeLadder(ctx, platform, queue, LadderOptions{})
+	if err != nil {
+		res.err = fmt.Errorf("error analyzing ladder: %w", err)
+		return res
+	}
+	res.rotation, res.version, res.ladder = rotation, ddVersion, ladder
+
+	// Sin partidas ingeridas la plataforma entra en LP/win rate pero no en representación
+	if tiers, err := s.tiers.Build(ctx, in.TierListQuery{Game: "lol", Platform: platform}); err == nil {
+		res.tiers = tiers
+	}
+	return res
+}
+
+// buildCrossRegionReport compara los análisis por plataforma
+func buildCrossRegionReport(queue string, results []regionResult) *CrossRegionReport {
+	report := &CrossRegionReport{Queue: queue, GeneratedAt: time.Now()}
+	for _, r := range results {
+		report.Platforms = append(report.Platforms, r.platform)
+		summary := RegionSummary{
+			Platform:             r.platform,
+			Patch:                riot.PatchFromGameVersion(r.version),
+			Cutoffs:              r.ladder.Cutoffs,
+			AvgWinRate:           avgWinRate(r.ladder),
+			RotationImpact:       r.rotation.ImpactScore,
+			MetaShiftProbability: r.rotation.MetaShiftProbability,
+			FreeChampions:        []string{},
+			Overrepresented:      []RegionalOutlier{},
+			Underrepresented:     []RegionalOutlier{},
+		}
+		for _, c := range r.rotation.FreeChampions {
+			summary.FreeChampions = append(summary.FreeChampions, c.Name)
+		}
+		for _, t := range r.ladder.Tiers {
+			summary.ApexPlayers += t.Players
+		}
+		if r.tiers != nil {
+			summary.Matches = r.tiers.Matches
+		}
+		report.Regions = append(report.Regions, summary)
+	}
+	regions := make(map[string]*RegionSummary, len(report.Regions))
+	for i := range report.Regions {
+		regions[report.Regions[i].Platform] = &report.Regions[i]
+	}
+
+	report.Tiers = compareTiers(results)
+	report.Champions = compareChampions(results, regions)
+	report.Insights = crossRegionInsights(report)
+	return report
+}
+
+// compareTiers LP, cutoffs y win rate de cada tier entre plataformas
+func compareTiers(results []regionResult) []CrossRegionTier {
+	var tiers []CrossRegionTier
+	for _, tier := range ApexTiers {
+		ct := CrossRegionTier{
+			Tier:    tier,
+			Players: map[string]int{},
+			LP:      map[string]stats.Distribution{},
+			WinRate: map[string]stats.Distribution{},
+			Cutoffs: map[string]int{},
+		}
+		var medians, winRates []float64
+		for _, r := range results {
+			for _, t := range r.ladder.Tiers {
+				if t.Tier != tier || t.Players == 0 {
+					continue
+				}
+				ct.Players[r.platform] = t.Players
+				ct.LP[r.platform] = t.LP
+				ct.WinRate[r.platform] = t.WinRate
+				ct.Cutoffs[r.platform] = t.CutoffLP
+				medians = append(medians, t.LP.P50)
+				winRates = append(winRates, t.WinRate.Mean)
+			}
+		}
+		if len(medians) == 0 {
+			continue
+		}
+		ct.MedianLPGap = stats.Round(spread(medians), 2)
+		ct.WinRateSpread = stats.Round(spread(winRates), 2)
+		high, low := extremes(ct.Cutoffs)
+		ct.CutoffGap = ct.Cutoffs[high] - ct.Cutoffs[low]
+		tiers = append(tiers, ct)
+	}
+	return tiers
+}
+
+// avgWinRate win rate medio de todos los jugadores apex (ponderado por jugadores de cada tier)
+func avgWinRate(ladder *LadderAnalysis) float64 {
+	players, total := 0, 0.0
+	for _, t := range ladder.Tiers {
+		players += t.WinRate.Count
+		total += t.WinRate.Mean * float64(t.WinRate.Count)
+	}
+	if players == 0 {
+		return 0
+	}
+	return stats.Round(total/float64(players), 2)
+}
+
+// compareChampions pick rate de cada campeón por plataforma (suma de sus roles en el tier list)
+// frente a la media entre plataformas con partidas; marca los outliers de cada región
+func compareChampions(results []regionResult, regions map[string]*RegionSummary) []ChampionRegionPresence {
+	var measured []string
+	byChampion := make(map[int]*ChampionRegionPresence)
+	for _, r := range results {
+		if r.tiers == nil || r.tiers.Matches == 0 {
+			continue
+		}
+		measured = append(measured, r.platform)
+		for _, role := range r.tiers.Roles {
+			for _, e := range role.Entries {
+				c := byChampion[e.ChampionID]
+				if c == nil {
+					c = &ChampionRegionPresence{ChampionID: e.ChampionID, Name: e.Name, PickRates: map[string]float64{}}
+					byChampion[e.ChampionID] = c
+				}
+				c.PickRates[r.platform] += e.PickRate
+			}
+		}
+	}
+	if len(measured) < 2 {
+		return []ChampionRegionPresence{}
+	}
+
+	champions := make([]ChampionRegionPresence, 0, len(byChampion))
+	for _, c := range byChampion {
+		rates := make([]float64, 0, len(measured))
+		for _, p := range measured {
+			// Un campeón ausente en una región cuenta con pick rate 0
+			c.PickRates[p] = stats.Round(c.PickRates[p], 2)
+			rates = append(rates, c.PickRates[p])
+		}
+		c.MeanPickRate = stats.Round(mean(rates), 2)
+		c.Spread = stats.Round(spread(rates), 2)
+		champions = append(champions, *c)
+
+		if c.MeanPickRate < crossRegionMinPickRate {
+			continue
+		}
+		for _, p := range measured {
+			outlier := RegionalOutlier{
+				ChampionID:   c.ChampionID,
+				Name:         c.Name,
+				PickRate:     c.PickRates[p],
+				MeanPickRate: c.MeanPickRate,
+				Ratio:        stats.Round(c.PickRates[p]/c.MeanPickRate, 2),
+			}
+			switch {
+			case outlier.Ratio >= crossRegionOverRatio:
+				regions[p].Overrepresented = append(regions[p].Overrepresented, outlier)
+			case outlier.Ratio <= crossRegionUnderRatio:
+				regions[p].Underrepresented = append(regions[p].Underrepresented, outlier)
+			}
+		}
+	}
+
+	sort.Slice(champions, func(i, j int) bool {
+		if champions[i].Spread != champions[j].Spread {
+			return champions[i].Spread > champions[j].Spread
+		}
+		return champions[i].Name < champions[j].Name
+	})
+	for _, p := range measured {
+		r := regions[p]
+		sort.Slice(r.Overrepresented, func(i, j int) bool { return r.Overrepresented[i].Ratio > r.Overrepresented[j].Ratio })
+		sort.Slice(r.Underrepresented, func(i, j int) bool { return r.Underrepresented[i].Ratio < r.Underrepresented[j].Ratio })
+		if len(r.Overrepresented) > crossRegionOutliers {
+			r.Overrepresented = r.Overrepresented[:crossRegionOutliers]
+		}
+		if len(r.Underrepresented) > crossRegionOutliers {
+			r.Underrepresented = r.Underrepresented[:crossRegionOutliers]
+		}
+	}
+	return champions
+}
+
+// crossRegionInsights frases resumen del reporte
+func crossRegionInsights(report *CrossRegionReport) []string {
+	var insights []string
+	for _, t := range report.Tiers {
+		if len(t.Cutoffs) < 2 {
+			continue
+		}
+		high, low := extremes(t.Cutoffs)
+		if t.CutoffGap > 0 {
+			insights = append(insights, fmt.Sprintf("%s cutoff is %d LP higher on %s than on %s", titleRole(t.Tier), t.CutoffGap, high, low))
+		}
+		if t.WinRateSpread >= 1 {
+			insights = append(insights, fmt.Sprintf("%s average win rate differs by %.1f points across regions", titleRole(t.Tier), t.WinRateSpread))
+		}
+	}
+	for _, r := range report.Regions {
+		if len(r.Overrepresented) > 0 {
+			o := r.Overrepresented[0]
+			insights = append(insights, fmt.Sprintf("%s is %.1fx more picked on %s than the cross-region average", o.Name, o.Ratio, r.Platform))
+		}
+	}
+	return insights
+}
+
+// uniquePlatforms normaliza la lista de plataformas (minúsculas, sin vacíos ni duplicados)
+func uniquePlatforms(platforms []string) []string {
+	seen := make(map[string]bool, len(platforms))
+	var result []string
+	for _, p := range platforms {
+		p = strings.ToLower(strings.TrimSpace(p))
+		if p == "" || seen[p] {
+			continue
+		}
+		seen[p] = true
+		result = append(result, p)
+	}
+	return result
+}
+
+// extremes plataformas con el valor más alto y más bajo
+func extremes(values map[string]int) (string, string) {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	high, low := keys[0], keys[0]
+	for _, k := range keys {
+		if values[k] > values[high] {
+			high = k
+		}
+		if values[k] < values[low] {
+			low = k
+		}
+	}
+	return high, low
+}
+
+func spread(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		lo, hi = math.Min(lo, v), math.Max(hi, v)
+	}
+	return hi - lo
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+	"github.com/steven230500/hypeatlas-api/shared/version"
+)
+
+// MaxImpactScore escala de PatchChange.ImpactScore (0-10)
+const MaxImpactScore = 10.0
+
+// impactFieldWeights peso por tipo de campo; se usa el primero cuyo keyword aparece en Field
+var impactFieldWeights = []struct {
+	Keywords []string
+	Weight   float64
+}{
+	{[]string{"vfx", "sfx", "visual", "skin", "icon", "tooltip", "animation", "audio", "cosmetic"}, 0.05},
+	{[]string{"damage", "dmg", "ratio", "scaling", "ad", "ap"}, 1.0},
+	{[]string{"health", "hp", "armor", "resist", "mr", "heal", "shield"}, 0.9},
+	{[]string{"cooldown", "cd", "recharge", "charges"}, 0.8},
+	{[]string{"range", "speed", "duration", "radius", "width", "windup"}, 0.7},
+	{[]string{"cost", "mana", "energy", "price", "credits", "ult points"}, 0.6},
+}
+
+// defaultFieldWeight peso de campos sin keyword conocido
+const defaultFieldWeight = 0.5
+
+// numberPattern números de un valor de cambio ("40s", "40/45/50", "-10%")
+var numberPattern = regexp.MustCompile(`-?\d+(?:\.\d+)?`)
+
+// ImpactService calcula PatchChange.ImpactScore a partir del cambio y de la popularidad de la entidad
+type ImpactService struct {
+	repo out.Repository
+}
+
+// NewImpactService crea un nuevo servicio de impacto de parches
+func NewImpactService(repo out.Repository) *ImpactService {
+	return &ImpactService{repo: repo}
+}
+
+// ScorePatch recalcula el impacto de todos los cambios de un parche, lo guarda y devuelve el resumen.
+// La popularidad sale del parche anterior (el meta que el cambio va a alterar) y, si no hay
+// datos, del propio parche.
+func (s *ImpactService) ScorePatch(ctx context.Context, game, patch string, top int) (*in.ImpactSummary, error) {
+	changes, err := s.repo.PatchChanges(ctx, game, patch, "")
+	if err != nil {
+		return nil, fmt.Errorf("error loading patch changes: %w", err)
+	}
+	if len(changes) == 0 {
+		return SummarizeImpact(game, patch, changes, top), nil
+	}
+
+	rates, err := s.pickRates(ctx, game, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[int64]float64, len(changes))
+	for i := range changes {
+		rate, known := rates[strings.ToLower(normalizeMember(game, changes[i].EntityID))]
+		score := ImpactScore(changes[i].Field, changes[i].Old, changes[i].New, rate, known)
+		changes[i].ImpactScore = score
+		scores[changes[i].ID] = score
+	}
+	if err := s.repo.UpdatePatchChangeScores(ctx, scores); err != nil {
+		return nil, fmt.Errorf("error saving impact scores: %w", err)
+	}
+	return SummarizeImpact(game, patch, changes, top), nil
+}
+
+// ScoreRecent recalcula los últimos n parches de un juego (los afectados por stats nuevas)
+func (s *ImpactService) ScoreRecent(ctx context.Context, game string, n int) ([]string, error) {
+	patches, err := s.repo.PatchesByGame(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(patches))
+	for _, p := range patches {
+		versions = append(versions, p.Version)
+	}
+	version.Sort(versions)
+	if len(versions) > n {
+		versions = versions[len(versions)-n:]
+	}
+	for _, v := range versions {
+		if _, err := s.ScorePatch(ctx, game, v, 0); err != nil {
+			return nil, fmt.Errorf("error scoring patch %s: %w", v, err)
+		}
+	}
+	return versions, nil
+}
+
+func (s *ImpactService) pickRates(ctx context.Context, game, patch string) (map[string]float64, error) {
+	patches, err := s.repo.PatchesByGame(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(patches))
+	for _, p := range patches {
+		versions = append(versions, p.Version)
+	}
+	if prev := version.Previous(versions, patch); prev != "" {
+		rates, err := s.repo.EntityPickRates(ctx, game, prev)
+		if err != nil {
+			return nil, fmt.Errorf("error loading pick rates: %w", err)
+		}
+		if len(rates) > 0 {
+			return rates, nil
+		}
+	}
+	rates, err := s.repo.EntityPickRates(ctx, game, patch)
+	if err != nil {
+		return nil, fmt.Errorf("error loading pick rates: %w", err)
+	}
+	return rates, nil
+}
+
+// ImpactScore puntúa un cambio de 0 a 10 como magnitud × peso del campo × popularidad:
+//
+//   - magnitud: cambio relativo de los valores numéricos (30% o más satura); 0.5 si no son numéricos
+//   - peso: daño 1.0, vida/resistencias 0.9, cooldown 0.8, alcance/duración 0.7, coste 0.6, cosmético 0.05
+//   - popularidad: 0.3 + 0.7 × min(1, pickRate/20); 0.5 si la entidad no tiene datos
+func ImpactScore(field, oldValue, newValue string, pickRate float64, known bool) float64 {
+	magnitude := changeMagnitude(oldValue, newValue)
+	popularity := 0.5
+	if known {
+		popularity = 0.3 + 0.7*math.Min(1, pickRate/20)
+	}
+	return stats.Round(MaxImpactScore*magnitude*fieldWeight(field)*popularity, 2)
+}
+
+// changeMagnitude cambio relativo normalizado a [0,1]
+func changeMagnitude(oldValue, newValue string) float64 {
+	if strings.TrimSpace(oldValue) == strings.TrimSpace(newValue) {
+		return 0
+	}
+	oldNum, okOld := meanNumber(oldValue)
+	newNum, okNew := meanNumber(newValue)
+	if !okOld || !okNew {
+		return 0.5
+	}
+	base := math.Max(math.Abs(oldNum), 1e-9)
+	return math.Min(1, math.Abs(newNum-oldNum)/base/0.3)
+}
+
+// meanNumber media de los números de un valor ("40/45/50" -> 45)
+func meanNumber(value string) (float64, bool) {
+	matches := numberPattern.FindAllString(value, -1)
+	if len(matches) == 0 {
+		return 0, false
+	}
+	sum := 0.0
+	for _, m := range matches {
+		n, err := strconv.ParseFloat(m, 64)
+		if err != nil {
+			return 0, false
+		}
+		sum += n
+	}
+	return sum / float64(len(matches)), true
+}
+
+// fieldWeight peso del tipo de campo (por palabras completas del nombre del campo)
+func fieldWeight(field string) float64 {
+	words := strings.FieldsFunc(strings.ToLower(field), func(r rune) bool {
+		return !(r >= 'a' && r <= 'z') && !(r >= '0' && r <= '9')
+	})
+	joined := " " + strings.Join(words, " ") + " "
+	for _, fw := range impactFieldWeights {
+		for _, kw := range fw.Keywords {
+			if strings.Contains(joined, " "+kw+" ") {
+				return fw.Weight
+			}
+		}
+	}
+	return defaultFieldWeight
+}
+
+// SummarizeImpact agrupa los cambios de un parche por tipo de entidad: los top cambios por
+// impacto y las entidades ordenadas por impacto combinado (suma, tope 10)
+func SummarizeImpact(game, patch string, changes []entities.PatchChange, top int) *in.ImpactSummary {
+	if top <= 0 {
+		top = 5
+	}
+	summary := &in.ImpactSummary{Game: game, Patch: patch, Types: []in.ImpactTypeSummary{}}
+
+	byType := make(map[string][]entities.PatchChange)
+	for _, c := range changes {
+		byType[c.EntityType] = append(byType[c.EntityType], c)
+	}
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	for _, t := range types {
+		list := byType[t]
+		sort.SliceStable(list, func(i, j int) bool { return list[i].ImpactScore > list[j].ImpactScore })
+
+		entityScores := make(map[string]*in.ImpactEntity)
+		for _, c := range list {
+			e := entityScores[c.EntityID]
+			if e == nil {
+				e = &in.ImpactEntity{EntityID: c.EntityID}
+				entityScores[c.EntityID] = e
+			}
+			e.Changes++
+			e.Score = stats.Round(math.Min(MaxImpactScore, e.Score+c.ImpactScore), 2)
+		}
+		ts := in.ImpactTypeSummary{EntityType: t, TopChanges: list, Entities: []in.ImpactEntity{}}
+		if len(ts.TopChanges) > top {
+			ts.TopChanges = ts.TopChanges[:top]
+		}
+		for _, e := range entityScores {
+			ts.Entities = append(ts.Entities, *e)
+		}
+		sort.Slice(ts.Entities, func(i, j int) bool {
+			if ts.Entities[i].Score != ts.Entities[j].Score {
+				return ts.Entities[i].Score > ts.Entities[j].Score
+			}
+			return ts.Entities[i].EntityID < ts.Entities[j].EntityID
+		})
+		if len(ts.Entities) > top {
+			ts.Entities = ts.Entities[:top]
+		}
+		summary.Types = append(summary.Types, ts)
+	}
+	return summary
+}
@@ -0,0 +1,246 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+)
+
+// ApexTiers tiers sin divisiones, servidos como una única liga por cola
+var ApexTiers = []string{"CHALLENGER", "GRANDMASTER", "MASTER"}
+
+// DivisionTiers tiers paginados por división (League-V4 entries)
+var DivisionTiers = []string{"DIAMOND", "EMERALD", "PLATINUM", "GOLD", "SILVER", "BRONZE", "IRON"}
+
+// Divisions divisiones de los tiers no apex
+var Divisions = []string{"I", "II", "III", "IV"}
+
+// LadderOptions configura el análisis de ladder
+type LadderOptions struct {
+	Tier     string // vacío = todos los apex tiers
+	Division string // vacío = todas (solo tiers no apex)
+	MaxPages int    // páginas por división (default 1)
+}
+
+// LadderAnalysis análisis de distribución del ladder de una cola
+type LadderAnalysis struct {
+	Platform string            `json:"platform"`
+	Queue    string            `json:"queue"`
+	Tiers    []TierLadderStats `json:"tiers"`
+	Cutoffs  map[string]int    `json:"cutoffs"` // LP mínimo observado por tier
+}
+
+// TierLadderStats estadísticas de un tier (o de una división si se filtró)
+type TierLadderStats struct {
+	Tier            string             `json:"tier"`
+	Players         int                `json:"players"`
+	Divisions       map[string]int     `json:"divisions,omitempty"`
+	Pages           int                `json:"pages,omitempty"`
+	Truncated       bool               `json:"truncated,omitempty"` // quedaron páginas sin leer
+	LP              stats.Distribution `json:"lp"`
+	LPHistogram     []stats.Bucket     `json:"lp_histogram"`
+	WinRate         stats.Distribution `json:"win_rate"`
+	WinRateHist     []stats.Bucket     `json:"win_rate_histogram"`
+	Games           stats.Distribution `json:"games"`
+	HotStreakRatio  float64            `json:"hot_streak_ratio"`
+	VeteranRatio    float64            `json:"veteran_ratio"`
+	FreshBloodRatio float64            `json:"fresh_blood_ratio"`
+	InactiveRatio   float64            `json:"inactive_ratio"`
+	CutoffLP        int                `json:"cutoff_lp"`
+	TopLP           int                `json:"top_lp"`
+}
+
+// IsKnownTier indica si tier es un tier válido de League-V4
+func IsKnownTier(tier string) bool {
+	tier = strings.ToUpper(tier)
+	for _, t := range append(append([]string{}, ApexTiers...), DivisionTiers...) {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// AnalyzeLadder recorre Challenger, Grandmaster y Master (o el tier pedido, paginando divisiones)
+// y calcula distribuciones de LP y win rate, ratios y cutoffs
+func (s *MetaGameService) AnalyzeLadder(ctx context.Context, platform, queue string, opts LadderOptions) (*LadderAnalysis, error) {
+	tiers := ApexTiers
+	if opts.Tier != "" {
+		tier := strings.ToUpper(opts.Tier)
+		if !IsKnownTier(tier) {
+			return nil, fmt.Errorf("unknown tier: %s", opts.Tier)
+		}
+		tiers = []string{tier}
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = 1
+	}
+
+	analysis := &LadderAnalysis{Platform: platform, Queue: queue, Cutoffs: map[string]int{}}
+	for _, tier := range tiers {
+		var (
+			entries   []riot.LeagueEntry
+			pages     int
+			truncated bool
+			err       error
+		)
+		if isApexTier(tier) {
+			entries, err = s.apexEntries(ctx, platform, queue, tier)
+		} else {
+			entries, pages, truncated, err = s.divisionEntries(ctx, platform, queue, tier, opts)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error getting %s entries: %w", tier, err)
+		}
+
+		tierStats := describeLadder(tier, entries)
+		tierStats.Pages = pages
+		tierStats.Truncated = truncated
+		if tierStats.Players > 0 {
+			analysis.Cutoffs[tier] = tierStats.CutoffLP
+		}
+		analysis.Tiers = append(analysis.Tiers, tierStats)
+	}
+	return analysis, nil
+}
+
+// apexEntries obtiene la liga completa de un apex tier
+func (s *MetaGameService) apexEntries(ctx context.Context, platform, queue, tier string) ([]riot.LeagueEntry, error) {
+	var (
+		league *riot.LeagueData
+		err    error
+	)
+	switch tier {
+	case "CHALLENGER":
+		league, err = s.riotSvc.GetChallengerLeague(ctx, platform, queue)
+	case "GRANDMASTER":
+		league, err = s.riotSvc.GetGrandmasterLeague(ctx, platform, queue)
+	default:
+		league, err = s.riotSvc.GetMasterLeague(ctx, platform, queue)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return league.Entries, nil
+}
+
+// leagueEntriesPageSize entradas por página de league-v4 entries; una página más corta es la última
+const leagueEntriesPageSize = 205
+
+// divisionEntries pagina las divisiones de un tier hasta MaxPages por división. truncated solo
+// se marca si se alcanzó MaxPages con la última página llena (quedaban entradas sin leer).
+func (s *MetaGameService) divisionEntries(ctx context.Context, platform, queue, tier string, opts LadderOptions) ([]riot.LeagueEntry, int, bool, error) {
+	divisions := Divisions
+	if opts.Division != "" {
+		divisions = []string{strings.ToUpper(opts.Division)}
+	}
+
+	var all []riot.LeagueEntry
+	pages := 0
+	truncated := false
+	for _, division := range divisions {
+		for page := 1; ; page++ {
+			if page > opts.MaxPages {
+				truncated = true
+				break
+			}
+			if err := ctx.Err(); err != nil {
+				return all, pages, truncated, err
+			}
+			entries, err := s.riotSvc.GetLeagueEntries(ctx, platform, queue, tier, division, page)
+			if err != nil {
+				return all, pages, truncated, err
+			}
+			pages++
+			if len(entries) == 0 {
+				break
+			}
+			for i := range entries {
+				if entries[i].Rank == "" {
+					entries[i].Rank = division
+				}
+			}
+			all = append(all, entries...)
+			if len(entries) < leagueEntriesPageSize {
+				break
+			}
+		}
+	}
+	return all, pages, truncated, nil
+}
+
+// describeLadder calcula las estadísticas de un conjunto de entradas
+func describeLadder(tier string, entries []riot.LeagueEntry) TierLadderStats {
+	out := TierLadderStats{Tier: tier, Players: len(entries)}
+	if len(entries) == 0 {
+		return out
+	}
+
+	lp := make([]float64, 0, len(entries))
+	winRates := make([]float64, 0, len(entries))
+	games := make([]float64, 0, len(entries))
+	var hot, veteran, fresh, inactive int
+	out.CutoffLP = entries[0].LeaguePoints
+	apex := isApexTier(tier)
+	if !apex {
+		out.Divisions = map[string]int{}
+	}
+
+	for _, e := range entries {
+		lp = append(lp, float64(e.LeaguePoints))
+		if total := e.Wins + e.Losses; total > 0 {
+			winRates = append(winRates, float64(e.Wins)/float64(total)*100)
+			games = append(games, float64(total))
+		}
+		if e.HotStreak {
+			hot++
+		}
+		if e.Veteran {
+			veteran++
+		}
+		if e.FreshBlood {
+			fresh++
+		}
+		if e.Inactive {
+			inactive++
+		}
+		if e.LeaguePoints > out.TopLP {
+			out.TopLP = e.LeaguePoints
+		}
+		if e.LeaguePoints < out.CutoffLP {
+			out.CutoffLP = e.LeaguePoints
+		}
+		if !apex {
+			out.Divisions[e.Rank]++
+		}
+	}
+
+	// En apex el LP no tiene techo: buckets de 100; en divisiones va de 0 a 100
+	lpWidth := 100.0
+	if !apex {
+		lpWidth = 10
+	}
+
+	out.LP = stats.Describe(lp)
+	out.LPHistogram = stats.Histogram(lp, lpWidth)
+	out.WinRate = stats.Describe(winRates)
+	out.WinRateHist = stats.Histogram(winRates, 5)
+	out.Games = stats.Describe(games)
+	out.HotStreakRatio = stats.Ratio(hot, len(entries))
+	out.VeteranRatio = stats.Ratio(veteran, len(entries))
+	out.FreshBloodRatio = stats.Ratio(fresh, len(entries))
+	out.InactiveRatio = stats.Ratio(inactive, len(entries))
+	return out
+}
+
+func isApexTier(tier string) bool {
+	for _, t := range ApexTiers {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
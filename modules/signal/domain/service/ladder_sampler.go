@@ -0,0 +1,75 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+)
+
+// LadderSampler obtiene PUUIDs de la cima del ladder (Challenger y luego Grandmaster)
+type LadderSampler struct {
+	riotSvc *riot.Service
+}
+
+// NewLadderSampler crea un nuevo sampler de ladder
+func NewLadderSampler(riotSvc *riot.Service) *LadderSampler {
+	return &LadderSampler{riotSvc: riotSvc}
+}
+
+// SamplePUUIDs devuelve hasta n PUUIDs ordenados por LP, empezando por Challenger
+func (l *LadderSampler) SamplePUUIDs(ctx context.Context, platform, queue string, n int) ([]string, error) {
+	if n <= 0 {
+		return nil, nil
+	}
+
+	challenger, err := l.riotSvc.GetChallengerLeague(ctx, platform, queue)
+	if err != nil {
+		return nil, fmt.Errorf("error getting challenger league: %w", err)
+	}
+	entries := sortedByLP(challenger.Entries)
+
+	// Solo bajamos a Grandmaster si Challenger no alcanza para la muestra
+	if len(entries) < n {
+		grandmaster, err := l.riotSvc.GetGrandmasterLeague(ctx, platform, queue)
+		if err != nil {
+			return nil, fmt.Errorf("error getting grandmaster league: %w", err)
+		}
+		entries = append(entries, sortedByLP(grandmaster.Entries)...)
+	}
+
+	puuids := make([]string, 0, n)
+	for _, entry := range entries {
+		if len(puuids) >= n {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return puuids, err
+		}
+		puuid := entry.PUUID
+		if puuid == "" && entry.SummonerID != "" {
+			// Respuestas antiguas de League-V4 no traen puuid
+			summoner, err := l.riotSvc.GetSummonerBySummonerID(ctx, platform, entry.SummonerID)
+			if err != nil {
+				continue
+			}
+			puuid = summoner.PUUID
+		}
+		if puuid != "" {
+			puuids = append(puuids, puuid)
+		}
+	}
+
+	return puuids, nil
+}
+
+// sortedByLP devuelve una copia de las entradas ordenadas por LP descendente
+func sortedByLP(entries []riot.LeagueEntry) []riot.LeagueEntry {
+	sorted := make([]riot.LeagueEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].LeaguePoints > sorted[j].LeaguePoints
+	})
+	return sorted
+}
@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+	"github.com/steven230500/hypeatlas-api/shared/version"
+)
+
+// lolRoles orden canónico de los slots de LoL
+var lolRoles = []string{"top", "jungle", "mid", "bot", "support"}
+
+// lolPositionRoles traduce teamPosition de Match-V5 al rol de los slots
+var lolPositionRoles = map[string]string{
+	"TOP": "top", "JUNGLE": "jungle", "MIDDLE": "mid", "BOTTOM": "bot", "UTILITY": "support",
+}
+
+// lolShapes roles que forman cada forma de comp
+var lolShapes = []struct {
+	Shape string
+	Roles []string
+}{
+	{entities.CompShapeFull, lolRoles},
+	{entities.CompShapeBotDuo, []string{"bot", "support"}},
+	{entities.CompShapeJungleMid, []string{"jungle", "mid"}},
+}
+
+// lolQueueLeagues liga con la que se guardan las comps de cada cola ranked
+var lolQueueLeagues = map[int]string{
+	riot.QueueIDRankedSolo: "Ranked Solo",
+	riot.QueueIDRankedFlex: "Ranked Flex",
+}
+
+// LolCompService agrega composiciones de LoL (completas y parciales) y las guarda en app.comps
+type LolCompService struct {
+	repo out.Repository
+}
+
+// NewLolCompService crea un nuevo servicio de composiciones de LoL
+func NewLolCompService(repo out.Repository) *LolCompService {
+	return &LolCompService{repo: repo}
+}
+
+// LolRegion región de las comps de una plataforma (euw1 -> EUW, kr -> KR)
+func LolRegion(platform string) string {
+	return strings.ToUpper(strings.TrimRight(platform, "0123456789"))
+}
+
+// CanonicalLolSlots arma los slots de cada forma (full, bot_duo, jungle_mid) a partir de los
+// picks de un equipo. Una forma se omite si falta alguno de sus roles o está repetido.
+func CanonicalLolSlots(picks []entities.MatchPickSummary) []entities.CompSlots {
+	byRole := make(map[string]string, len(picks))
+	for _, p := range picks {
+		role := lolPositionRoles[p.Role]
+		if role == "" {
+			continue
+		}
+		if _, dup := byRole[role]; dup {
+			byRole[role] = "" // rol repetido: posición no fiable
+			continue
+		}
+		byRole[role] = p.ChampionName
+	}
+
+	var result []entities.CompSlots
+	for _, shape := range lolShapes {
+		slots := entities.CompSlots{Shape: shape.Shape}
+		complete := true
+		for _, role := range shape.Roles {
+			champion := byRole[role]
+			if champion == "" {
+				complete = false
+				break
+			}
+			slots.Roles = append(slots.Roles, role)
+			slots.Members = append(slots.Members, entities.CompSlotMember{Champion: champion})
+		}
+		if complete {
+			result = append(result, slots)
+		}
+	}
+	return result
+}
+
+// RebuildPatch recalcula las comps de LoL de un parche a partir de las partidas de Match-V5
+// registradas y las sube con UpsertComp (ver compAggregator). Cada equipo cuenta en su lado
+// (blue|red) y en side "". Devuelve cuántas filas escribió.
+func (s *LolCompService) RebuildPatch(ctx context.Context, patch string) (int, error) {
+	matches, err := s.repo.RiotMatchesByPatch(ctx, "lol", patch)
+	if err != nil {
+		return 0, fmt.Errorf("error loading matches: %w", err)
+	}
+
+	agg := newCompAggregator()
+	for _, m := range matches {
+		league, ok := lolQueueLeagues[m.QueueID]
+		if !ok {
+			continue
+		}
+		var summary entities.MatchSummary
+		if err := json.Unmarshal(m.Summary, &summary); err != nil {
+			continue
+		}
+		if err := addLolMatch(agg, LolRegion(m.Platform), league, summary); err != nil {
+			return 0, err
+		}
+	}
+
+	written := 0
+	for _, comp := range agg.rows("lol", patch) {
+		if err := s.repo.UpsertComp(ctx, &comp); err != nil {
+			return written, fmt.Errorf("error upserting comp: %w", err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// RebuildRecent recalcula las comps de los últimos n parches con partidas registradas
+func (s *LolCompService) RebuildRecent(ctx context.Context, n int) (map[string]int, error) {
+	patches, err := s.repo.RiotMatchPatches(ctx, "lol", "")
+	if err != nil {
+		return nil, fmt.Errorf("error loading match patches: %w", err)
+	}
+	version.Sort(patches)
+	if len(patches) > n {
+		patches = patches[len(patches)-n:]
+	}
+	written := make(map[string]int, len(patches))
+	for _, patch := range patches {
+		count, err := s.RebuildPatch(ctx, patch)
+		if err != nil {
+			return written, fmt.Errorf("error rebuilding patch %s: %w", patch, err)
+		}
+		written[patch] = count
+	}
+	return written, nil
+}
+
+// addLolMatch suma los equipos de una partida al agregador
+func addLolMatch(agg *compAggregator, region, league string, summary entities.MatchSummary) error {
+	for _, team := range summary.Teams {
+		win := 0
+		if team.Win {
+			win = 1
+		}
+		for _, cs := range CanonicalLolSlots(team.Picks) {
+			slots, err := slotsJSON(cs)
+			if err != nil {
+				return err
+			}
+			key := compKey{Region: region, League: league, Shape: cs.Shape}
+			agg.pick(key, slots)
+			agg.result(key, slots, 1, win)
+			sided := key
+			sided.Side = team.Side
+			agg.result(sided, slots, 1, win)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+)
+
+// MasteryService agrega maestrías de campeón de una muestra del ladder
+type MasteryService struct {
+	repo    out.Repository
+	riotSvc *riot.Service
+	sampler *LadderSampler
+}
+
+// NewMasteryService crea un nuevo servicio de maestrías
+func NewMasteryService(repo out.Repository, riotSvc *riot.Service) *MasteryService {
+	return &MasteryService{
+		repo:    repo,
+		riotSvc: riotSvc,
+		sampler: NewLadderSampler(riotSvc),
+	}
+}
+
+// MasteryAggregateResult resumen de una corrida de agregación
+type MasteryAggregateResult struct {
+	Platform   string `json:"platform"`
+	SampleSize int    `json:"sample_size"`
+	Failed     int    `json:"failed"`
+	Champions  int    `json:"champions"`
+}
+
+// masteryAccumulator acumula las maestrías de un campeón durante la corrida
+type masteryAccumulator struct {
+	players   int
+	points    int64
+	levels    int
+	topPoints int64
+	topLevel  int
+}
+
+// AggregatePlatform muestrea n jugadores del ladder y reemplaza las estadísticas de maestría de la plataforma
+func (s *MasteryService) AggregatePlatform(ctx context.Context, platform, queue string, n int) (*MasteryAggregateResult, error) {
+	if queue == "" {
+		queue = "RANKED_SOLO_5x5"
+	}
+	puuids, err := s.sampler.SamplePUUIDs(ctx, platform, queue, n)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &MasteryAggregateResult{Platform: platform}
+	acc := make(map[int]*masteryAccumulator)
+	for _, puuid := range puuids {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		masteries, err := s.riotSvc.GetChampionMasteries(ctx, platform, puuid)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		result.SampleSize++
+		for _, m := range masteries {
+			a, ok := acc[m.ChampionID]
+			if !ok {
+				a = &masteryAccumulator{}
+				acc[m.ChampionID] = a
+			}
+			a.players++
+			a.points += int64(m.ChampionPoints)
+			a.levels += m.ChampionLevel
+			if int64(m.ChampionPoints) > a.topPoints {
+				a.topPoints = int64(m.ChampionPoints)
+			}
+			if m.ChampionLevel > a.topLevel {
+				a.topLevel = m.ChampionLevel
+			}
+		}
+	}
+	if result.SampleSize == 0 {
+		return result, fmt.Errorf("no masteries fetched for %s (%d players failed)", platform, result.Failed)
+	}
+
+	// Nombres de campeones desde Data Dragon (no crítico: sin nombre se usa el ID)
+	names, err := championNames(ctx, s.riotSvc)
+	if err != nil {
+		names = map[int]string{}
+	}
+
+	rows := make([]entities.ChampionMasteryStats, 0, len(acc))
+	for id, a := range acc {
+		name := names[id]
+		if name == "" {
+			name = fmt.Sprintf("Champion_%d", id)
+		}
+		rows = append(rows, entities.ChampionMasteryStats{
+			Game:             "lol",
+			Platform:         platform,
+			ChampionID:       id,
+			ChampionName:     name,
+			AvgMasteryPoints: a.points / int64(a.players),
+			AvgMasteryLevel:  math.Round(float64(a.levels)/float64(a.players)*100) / 100,
+			TotalPlayers:     a.players,
+			TopMasteryPoints: a.topPoints,
+			TopMasteryLevel:  a.topLevel,
+			SampleSize:       result.SampleSize,
+		})
+	}
+
+	if err := s.repo.ReplaceChampionMasteryStats(ctx, "lol", platform, rows); err != nil {
+		return result, fmt.Errorf("error saving mastery stats: %w", err)
+	}
+	result.Champions = len(rows)
+	return result, nil
+}
+
+// MasteryReport estadísticas de maestría listas para servir
+type MasteryReport struct {
+	Platform   string                          `json:"platform"`
+	SampleSize int                             `json:"sample_size"`
+	UpdatedAt  *time.Time                      `json:"updated_at"`
+	Champions  []entities.ChampionMasteryStats `json:"champions"`
+}
+
+// ListMastery devuelve las estadísticas de maestría guardadas de una plataforma
+func (s *MasteryService) ListMastery(ctx context.Context, platform string, q out.MasteryQuery) (*MasteryReport, error) {
+	rows, err := s.repo.ChampionMasteryStats(ctx, "lol", platform, q)
+	if err != nil {
+		return nil, err
+	}
+	report := &MasteryReport{Platform: platform, Champions: rows}
+	for i := range rows {
+		if rows[i].SampleSize > report.SampleSize {
+			report.SampleSize = rows[i].SampleSize
+		}
+		if report.UpdatedAt == nil || rows[i].UpdatedAt.After(*report.UpdatedAt) {
+			report.UpdatedAt = &rows[i].UpdatedAt
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+)
+
+// MatchIngestService descarga partidas de Match-V5 y agrega estadísticas por campeón
+type MatchIngestService struct {
+	repo    out.Repository
+	riotSvc *riot.Service
+	sampler *LadderSampler
+}
+
+// NewMatchIngestService crea un nuevo servicio de ingesta de partidas
+func NewMatchIngestService(repo out.Repository, riotSvc *riot.Service) *MatchIngestService {
+	return &MatchIngestService{
+		repo:    repo,
+		riotSvc: riotSvc,
+		sampler: NewLadderSampler(riotSvc),
+	}
+}
+
+// MatchIngestOptions configura una corrida de ingesta
+type MatchIngestOptions struct {
+	Queue            string // RANKED_SOLO_5x5 | RANKED_FLEX_SR
+	Players          int    // PUUIDs a muestrear del ladder
+	MatchesPerPlayer int    // IDs de partidas por PUUID (máx. 100)
+}
+
+// MatchIngestResult resumen de una corrida de ingesta
+type MatchIngestResult struct {
+	Platform       string   `json:"platform"`
+	Players        int      `json:"players"`
+	MatchIDs       int      `json:"match_ids"`
+	NewMatches     int      `json:"new_matches"`
+	SkippedMatches int      `json:"skipped_matches"`
+	FailedMatches  int      `json:"failed_matches"`
+	Patches        []string `json:"patches"`
+}
+
+// IngestPlatform muestrea el ladder de una plataforma, descarga partidas nuevas y actualiza estadísticas
+func (s *MatchIngestService) IngestPlatform(ctx context.Context, platform string, opts MatchIngestOptions) (*MatchIngestResult, error) {
+	if opts.Queue == "" {
+		opts.Queue = "RANKED_SOLO_5x5"
+	}
+	queueID, ok := riot.QueueIDs[opts.Queue]
+	if !ok {
+		return nil, fmt.Errorf("unsupported queue: %s", opts.Queue)
+	}
+	if opts.Players <= 0 {
+		opts.Players = 20
+	}
+	if opts.MatchesPerPlayer <= 0 {
+		opts.MatchesPerPlayer = 10
+	}
+
+	result := &MatchIngestResult{Platform: platform}
+
+	puuids, err := s.sampler.SamplePUUIDs(ctx, platform, opts.Queue, opts.Players)
+	if err != nil {
+		return nil, err
+	}
+	result.Players = len(puuids)
+
+	// Nombres de campeones (los bans solo traen championId)
+	names, err := championNames(ctx, s.riotSvc)
+	if err != nil {
+		return nil, err
+	}
+
+	region := riot.RegionalRoute(platform)
+
+	// IDs únicos de todas las partidas muestreadas
+	seen := make(map[string]bool)
+	var matchIDs []string
+	for _, puuid := range puuids {
+		ids, err := s.riotSvc.GetMatchIDsByPUUID(ctx, region, puuid, queueID, opts.MatchesPerPlayer)
+		if err != nil {
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			continue
+		}
+		for _, id := range ids {
+			if !seen[id] {
+				seen[id] = true
+				matchIDs = append(matchIDs, id)
+			}
+		}
+	}
+	result.MatchIDs = len(matchIDs)
+
+	known, err := s.repo.KnownRiotMatches(ctx, matchIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error checking known matches: %w", err)
+	}
+
+	touched := make(map[string]bool)
+	for _, id := range matchIDs {
+		if known[id] {
+			result.SkippedMatches++
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		match, err := s.riotSvc.GetMatch(ctx, region, id)
+		if err != nil || match.Info.QueueID != queueID {
+			result.FailedMatches++
+			continue
+		}
+
+		patch, inserted, err := s.ingestMatch(ctx, platform, match, names)
+		if err != nil {
+			result.FailedMatches++
+			continue
+		}
+		if !inserted {
+			result.SkippedMatches++
+			continue
+		}
+		result.NewMatches++
+		touched[patch] = true
+	}
+
+	for patch := range touched {
+		if err := s.repo.RefreshChampionMatchRates(ctx, platform, patch, queueID); err != nil {
+			return result, fmt.Errorf("error refreshing rates for patch %s: %w", patch, err)
+		}
+		result.Patches = append(result.Patches, patch)
+	}
+
+	return result, nil
+}
+
+// ingestMatch registra la partida y suma sus contadores; devuelve el parche y si era nueva
+func (s *MatchIngestService) ingestMatch(ctx context.Context, platform string, match *riot.Match, names map[int]string) (string, bool, error) {
+	patch := riot.PatchFromGameVersion(match.Info.GameVersion)
+	summary := BuildMatchSummary(match, names)
+
+	raw, err := json.Marshal(summary)
+	if err != nil {
+		return patch, false, err
+	}
+
+	// Partida y contadores en una transacción: si algo falla, la partida no queda
+	// registrada y la próxima corrida la vuelve a procesar
+	rows := championRowsFromSummary(platform, patch, match.Info.QueueID, summary, names)
+	inserted, err := s.repo.RecordRiotMatch(ctx, &entities.RiotMatch{
+		MatchID:      match.Metadata.MatchID,
+		Game:         "lol",
+		Platform:     platform,
+		Patch:        patch,
+		QueueID:      match.Info.QueueID,
+		GameCreation: time.UnixMilli(match.Info.GameCreation).UTC(),
+		Summary:      raw,
+	}, rows)
+	return patch, inserted, err
+}
+
+// championNames mapea championId -> nombre usando Data Dragon
+func championNames(ctx context.Context, riotSvc *riot.Service) (map[int]string, error) {
+	version, err := riotSvc.GetLatestVersion(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error getting latest version: %w", err)
+	}
+	champions, err := riotSvc.GetChampions(ctx, version)
+	if err != nil {
+		return nil, fmt.Errorf("error getting champions data: %w", err)
+	}
+	names := make(map[int]string, len(champions.Data))
+	for _, champ := range champions.Data {
+		if id, err := strconv.Atoi(champ.Key); err == nil {
+			names[id] = champ.Name
+		}
+	}
+	return names, nil
+}
+
+// BuildMatchSummary reduce una partida de Match-V5 a picks por rol y bans por equipo
+func BuildMatchSummary(match *riot.Match, names map[int]string) entities.MatchSummary {
+	var summary entities.MatchSummary
+	for _, team := range match.Info.Teams {
+		ts := entities.MatchTeamSummary{
+			TeamID: team.TeamID,
+			Side:   sideFromTeamID(team.TeamID),
+			Win:    team.Win,
+		}
+		for _, ban := range team.Bans {
+			if ban.ChampionID > 0 {
+				ts.Bans = append(ts.Bans, ban.ChampionID)
+			}
+		}
+		for _, p := range match.Info.Participants {
+			if p.TeamID != team.TeamID {
+				continue
+			}
+			name := names[p.ChampionID]
+			if name == "" {
+				name = p.ChampionName
+			}
+			ts.Picks = append(ts.Picks, entities.MatchPickSummary{
+				ChampionID:   p.ChampionID,
+				ChampionName: name,
+				Role:         p.Position(),
+			})
+		}
+		summary.Teams = append(summary.Teams, ts)
+	}
+	return summary
+}
+
+// championRowsFromSummary genera los incrementos de una partida: una fila agregada
+// (role "") por campeón con picks/victorias/bans, y una fila por rol jugado
+func championRowsFromSummary(platform, patch string, queueID int, summary entities.MatchSummary, names map[int]string) []entities.ChampionMatchStats {
+	totals := make(map[int]*entities.ChampionMatchStats)
+	total := func(id int, name string) *entities.ChampionMatchStats {
+		row, ok := totals[id]
+		if !ok {
+			if name == "" {
+				name = names[id]
+			}
+			if name == "" {
+				name = fmt.Sprintf("Champion_%d", id)
+			}
+			row = &entities.ChampionMatchStats{
+				Game: "lol", Platform: platform, Patch: patch, QueueID: queueID,
+				ChampionID: id, ChampionName: name,
+			}
+			totals[id] = row
+		}
+		return row
+	}
+
+	var rows []entities.ChampionMatchStats
+	for _, team := range summary.Teams {
+		for _, pick := range team.Picks {
+			row := total(pick.ChampionID, pick.ChampionName)
+			row.Games++
+			if team.Win {
+				row.Wins++
+			}
+			if pick.Role != "" {
+				roleRow := entities.ChampionMatchStats{
+					Game: "lol", Platform: platform, Patch: patch, QueueID: queueID,
+					ChampionID: pick.ChampionID, ChampionName: row.ChampionName,
+					Role: pick.Role, Games: 1,
+				}
+				if team.Win {
+					roleRow.Wins = 1
+				}
+				rows = append(rows, roleRow)
+			}
+		}
+	}
+
+	// Un campeón baneado por ambos equipos cuenta una sola vez por partida
+	banned := make(map[int]bool)
+	for _, team := range summary.Teams {
+		for _, id := range team.Bans {
+			if !banned[id] {
+				banned[id] = true
+				total(id, "").Bans++
+			}
+		}
+	}
+
+	for _, row := range totals {
+		rows = append(rows, *row)
+	}
+	return rows
+}
+
+// sideFromTeamID traduce el teamId de Riot al lado del mapa
+func sideFromTeamID(teamID int) string {
+	if teamID == 200 {
+		return "red"
+	}
+	return "blue"
+}
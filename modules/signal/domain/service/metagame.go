@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
 	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
 	riot "github.com/steven230500/hypeatlas-api/providers/riot"
 )
@@ -14,6 +16,7 @@ type MetaGameService struct {
 	repo       out.Repository
 	riotSvc    *riot.Service
 	dataDragon *riot.Client
+	tiers      *TierListService
 }
 
 // NewMetaGameService crea un nuevo servicio de análisis de meta-game
@@ -21,7 +24,8 @@ func NewMetaGameService(repo out.Repository, riotSvc *riot.Service) *MetaGameSer
 	return &MetaGameService{
 		repo:       repo,
 		riotSvc:    riotSvc,
-		dataDragon: riot.NewClient(""), // Cliente sin API key para Data Dragon
+		dataDragon: riotSvc.Client(), // Data Dragon no usa la API key ni el rate limiter
+		tiers:      NewTierListService(repo, TierListConfigFromEnv()),
 	}
 }
 
@@ -43,31 +47,46 @@ type ChampionInfo struct {
 	PickRate float64 `json:"pick_rate"`
 	WinRate  float64 `json:"win_rate"`
 	BanRate  float64 `json:"ban_rate"`
+	Games    int     `json:"games,omitempty"`
+	Source   string  `json:"source"` // measured | unmeasured
 }
 
 // AnalyzeChampionRotation analiza el impacto de la rotación semanal de campeones
 func (s *MetaGameService) AnalyzeChampionRotation(ctx context.Context, platform string) (*ChampionRotationAnalysis, error) {
+	analysis, _, _, err := s.analyzeRotation(ctx, platform)
+	return analysis, err
+}
+
+// analyzeRotation analiza la rotación y devuelve también la respuesta cruda de Riot
+// y la versión de Data Dragon usada (para persistir el snapshot)
+func (s *MetaGameService) analyzeRotation(ctx context.Context, platform string) (*ChampionRotationAnalysis, *riot.ChampionRotationResponse, string, error) {
 	// Obtener rotación actual desde Riot API
 	rotation, err := s.riotSvc.GetChampionRotation(ctx, platform)
 	if err != nil {
-		return nil, fmt.Errorf("error getting champion rotation: %w", err)
+		return nil, nil, "", fmt.Errorf("error getting champion rotation: %w", err)
 	}
 
 	// Obtener la versión más reciente desde Data Dragon
-	latestVersion, err := s.dataDragon.GetLatestVersion()
+	latestVersion, err := s.dataDragon.GetLatestVersion(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error getting latest version: %w", err)
+		return nil, nil, "", fmt.Errorf("error getting latest version: %w", err)
 	}
 
 	// Obtener datos de campeones desde Data Dragon (sin autenticación)
-	champions, err := s.dataDragon.GetChampions(latestVersion)
+	champions, err := s.dataDragon.GetChampions(ctx, latestVersion)
 	if err != nil {
-		return nil, fmt.Errorf("error getting champions data: %w", err)
+		return nil, nil, "", fmt.Errorf("error getting champions data: %w", err)
+	}
+
+	// Tier list de las partidas ranked ingeridas (vacío si el worker aún no ingirió partidas)
+	measured := make(map[int]in.TierEntry)
+	if tiers, err := s.tiers.Build(ctx, in.TierListQuery{Game: "lol", Platform: platform}); err == nil {
+		measured = ByChampionID(tiers)
 	}
 
 	// Analizar campeones gratuitos
-	freeChampions := s.analyzeChampions(rotation.FreeChampionIDs, champions)
-	newPlayerChampions := s.analyzeChampions(rotation.FreeChampionIDsForNewPlayers, champions)
+	freeChampions := s.analyzeChampions(rotation.FreeChampionIDs, champions, measured)
+	newPlayerChampions := s.analyzeChampions(rotation.FreeChampionIDsForNewPlayers, champions, measured)
 
 	// Calcular impacto en el meta
 	impactScore := s.calculateRotationImpact(freeChampions, newPlayerChampions)
@@ -82,11 +101,12 @@ func (s *MetaGameService) AnalyzeChampionRotation(ctx context.Context, platform
 		ImpactScore:          impactScore,
 		MetaShiftProbability: metaShiftProb,
 		RecommendedChampions: recommendations,
-	}, nil
+	}, rotation, latestVersion, nil
 }
 
-// analyzeChampions analiza una lista de IDs de campeones
-func (s *MetaGameService) analyzeChampions(championIDs []int, allChampions *riot.ChampionsResponse) []ChampionInfo {
+// analyzeChampions analiza una lista de IDs de campeones. Los que no están en el tier list
+// (sin partidas suficientes) quedan Unranked con el rol de sus tags de Data Dragon.
+func (s *MetaGameService) analyzeChampions(championIDs []int, allChampions *riot.ChampionsResponse, measured map[int]in.TierEntry) []ChampionInfo {
 	var result []ChampionInfo
 
 	// Crear un mapa de key -> champion data para búsqueda rápida
@@ -97,34 +117,40 @@ func (s *MetaGameService) analyzeChampions(championIDs []int, allChampions *riot
 
 	for _, id := range championIDs {
 		// Buscar campeón por key (el ID numérico convertido a string)
-		idStr := fmt.Sprintf("%d", id)
-
-		if champData, exists := keyToChampion[idStr]; exists {
-			// Estimar tier basado en el nombre del campeón
-			tier := s.estimateChampionTier(champData.Name)
-			pickRate := s.estimatePickRate(tier)
-			winRate := s.estimateWinRate(tier)
-			banRate := s.estimateBanRate(tier)
+		champData, exists := keyToChampion[fmt.Sprintf("%d", id)]
 
+		if entry, ok := measured[id]; ok {
+			name := entry.Name
+			if exists {
+				name = champData.Name
+			}
 			result = append(result, ChampionInfo{
 				ID:       id,
-				Name:     champData.Name,
-				Role:     s.estimateChampionRole(champData.Name),
-				Tier:     tier,
-				PickRate: pickRate,
-				WinRate:  winRate,
-				BanRate:  banRate,
+				Name:     name,
+				Role:     entry.Role,
+				Tier:     entry.Tier,
+				PickRate: entry.PickRate,
+				WinRate:  entry.WinRate,
+				BanRate:  entry.BanRate,
+				Games:    entry.Games,
+				Source:   "measured",
+			})
+		} else if exists {
+			result = append(result, ChampionInfo{
+				ID:     id,
+				Name:   champData.Name,
+				Role:   roleFromTags(champData.Tags),
+				Tier:   TierUnranked,
+				Source: "unmeasured",
 			})
 		} else {
 			// Si no encontramos el campeón, crear una entrada básica
 			result = append(result, ChampionInfo{
-				ID:       id,
-				Name:     fmt.Sprintf("Champion_%d", id),
-				Role:     "Unknown",
-				Tier:     "B",
-				PickRate: 5.0,
-				WinRate:  47.5,
-				BanRate:  3.0,
+				ID:     id,
+				Name:   fmt.Sprintf("Champion_%d", id),
+				Role:   "Unknown",
+				Tier:   TierUnranked,
+				Source: "unmeasured",
 			})
 		}
 	}
@@ -132,145 +158,33 @@ func (s *MetaGameService) analyzeChampions(championIDs []int, allChampions *riot
 	return result
 }
 
-// estimateChampionTier estima el tier de un campeón basado en su nombre
-func (s *MetaGameService) estimateChampionTier(championName string) string {
-	// Campeones S-tier actuales (simplificado)
-	sTierChampions := []string{
-		"Yuumi", "Jinx", "Zeri", "Kai'Sa", "Jhin", "Vayne", "Caitlyn",
-		"Lee Sin", "Akali", "Yone", "Fiora", "Jax", "Irelia",
-	}
-
-	// Campeones A-tier
-	aTierChampions := []string{
-		"Lux", "Ahri", "Miss Fortune", "Ezreal", "Thresh", "Leona",
-		"Blitzcrank", "Zed", "Katarina", "Master Yi", "Darius",
-	}
-
-	for _, champ := range sTierChampions {
-		if champ == championName {
-			return "S"
-		}
-	}
-
-	for _, champ := range aTierChampions {
-		if champ == championName {
-			return "A"
-		}
-	}
-
-	return "B" // Default tier
-}
-
-// estimateChampionRole estima el rol principal de un campeón basado en su nombre
-func (s *MetaGameService) estimateChampionRole(championName string) string {
-	// Campeones ADC/Marksman
-	adcChampions := []string{
-		"Jinx", "Kai'Sa", "Jhin", "Vayne", "Caitlyn", "Miss Fortune",
-		"Ezreal", "Lucian", "Ashe", "Draven", "Sivir", "Twitch",
-	}
-
-	// Campeones Assassin
-	assassinChampions := []string{
-		"Akali", "Katarina", "Zed", "Fiora", "Irelia", "Yone",
-		"Qiyana", "Nocturne", "Rengar", "Master Yi", "Jax",
-	}
-
-	// Campeones Mage
-	mageChampions := []string{
-		"Ahri", "Lux", "Ziggs", "Veigar", "Karthus", "Anivia",
-		"Brand", "Cassiopeia", "Kennen", "Orianna", "Swain",
-	}
-
-	// Campeones Tank
-	tankChampions := []string{
-		"Leona", "Thresh", "Nautilus", "Shen", "Malphite", "Maokai",
-		"Poppy", "Rell", "Taric", "Yuumi", "Zac",
-	}
-
-	// Campeones Support
-	supportChampions := []string{
-		"Blitzcrank", "Janna", "Lulu", "Morgana", "Nami", "Soraka",
-		"Zilean", "Bard", "Rakan", "Senna", "Yuumi",
-	}
-
-	for _, champ := range adcChampions {
-		if champ == championName {
-			return "ADC"
-		}
-	}
-
-	for _, champ := range assassinChampions {
-		if champ == championName {
-			return "Assassin"
-		}
-	}
-
-	for _, champ := range mageChampions {
-		if champ == championName {
-			return "Mage"
-		}
-	}
-
-	for _, champ := range tankChampions {
-		if champ == championName {
-			return "Tank"
-		}
-	}
-
-	for _, champ := range supportChampions {
-		if champ == championName {
-			return "Support"
-		}
-	}
-
-	return "Fighter" // Default role
-}
-
-// estimatePickRate estima el pick rate basado en el tier
-func (s *MetaGameService) estimatePickRate(tier string) float64 {
-	switch tier {
-	case "S":
-		return 15.5
-	case "A":
-		return 12.3
-	case "B":
-		return 8.7
+// roleFromPosition traduce la posición de Match-V5 al rol que expone la API
+func roleFromPosition(position string) string {
+	switch strings.ToUpper(position) {
+	case "TOP":
+		return "Top"
+	case "JUNGLE":
+		return "Jungle"
+	case "MIDDLE":
+		return "Mid"
+	case "BOTTOM":
+		return "ADC"
+	case "UTILITY":
+		return "Support"
 	default:
-		return 5.2
+		return ""
 	}
 }
 
-// estimateWinRate estima el win rate basado en el tier
-func (s *MetaGameService) estimateWinRate(tier string) float64 {
-	switch tier {
-	case "S":
-		return 52.1
-	case "A":
-		return 50.8
-	case "B":
-		return 49.2
-	default:
-		return 47.5
+// roleFromTags rol principal según los tags de Data Dragon (Marksman -> ADC)
+func roleFromTags(tags []string) string {
+	if len(tags) == 0 {
+		return "Unknown"
 	}
-}
-
-// estimateBanRate estima el ban rate basado en el tier
-func (s *MetaGameService) estimateBanRate(tier string) float64 {
-	switch tier {
-	case "S":
-		return 25.3
-	case "A":
-		return 18.7
-	case "B":
-		return 12.1
-	default:
-		return 6.8
+	if tags[0] == "Marksman" {
+		return "ADC"
 	}
-}
-
-// getPrimaryRole obtiene el rol primario de un campeón (deprecated - usar estimateChampionRole)
-func (s *MetaGameService) getPrimaryRole(tags []string) string {
-	return "Unknown"
+	return tags[0]
 }
 
 // calculateRotationImpact calcula el impacto de la rotación en el meta
@@ -387,6 +301,7 @@ func (s *MetaGameService) AnalyzeLeagueRankings(ctx context.Context, platform, q
 		AvgLP:        avgLP,
 		AvgWinRate:   avgWinRate,
 		TopLP:        topLP,
+		LeagueID:     challenger.LeagueID,
 		LeagueName:   challenger.Name,
 	}, nil
 }
@@ -399,6 +314,7 @@ type LeagueAnalysis struct {
 	AvgLP        float64 `json:"avg_lp"`
 	AvgWinRate   float64 `json:"avg_win_rate"`
 	TopLP        int     `json:"top_lp"`
+	LeagueID     string  `json:"league_id"`
 	LeagueName   string  `json:"league_name"`
 }
 
@@ -455,29 +371,30 @@ func (s *MetaGameService) findTopLP(entries []riot.LeagueEntry) int {
 
 // GenerateMetaReport genera un reporte completo de meta-game
 func (s *MetaGameService) GenerateMetaReport(ctx context.Context, platform string) (*MetaReport, error) {
-	report := &MetaReport{
-		Platform:    platform,
-		GeneratedAt: time.Now(),
-	}
-
 	// Análisis de rotación de campeones
 	rotationAnalysis, err := s.AnalyzeChampionRotation(ctx, platform)
 	if err != nil {
 		return nil, fmt.Errorf("error analyzing champion rotation: %w", err)
 	}
-	report.ChampionRotation = rotationAnalysis
 
 	// Análisis de ligas
 	soloQAnalysis, err := s.AnalyzeLeagueRankings(ctx, platform, "RANKED_SOLO_5x5")
 	if err != nil {
 		return nil, fmt.Errorf("error analyzing solo queue: %w", err)
 	}
-	report.LeagueAnalysis = soloQAnalysis
 
-	// Generar insights
-	report.Insights = s.generateMetaInsights(rotationAnalysis, soloQAnalysis)
+	return s.buildMetaReport(platform, rotationAnalysis, soloQAnalysis), nil
+}
 
-	return report, nil
+// buildMetaReport arma un reporte a partir de análisis ya calculados
+func (s *MetaGameService) buildMetaReport(platform string, rotation *ChampionRotationAnalysis, league *LeagueAnalysis) *MetaReport {
+	return &MetaReport{
+		Platform:         platform,
+		GeneratedAt:      time.Now(),
+		ChampionRotation: rotation,
+		LeagueAnalysis:   league,
+		Insights:         s.generateMetaInsights(rotation, league),
+	}
 }
 
 // MetaReport representa un reporte completo de meta-game
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+)
+
+// Tipos de análisis persistidos en meta_game_analyses
+const (
+	AnalysisTypeRotation    = "rotation"
+	AnalysisTypeReport      = "report"
+	AnalysisTypeCrossRegion = "cross_region"
+)
+
+// LeagueAnalysisType devuelve el analysis_type de un análisis de liga (league:RANKED_SOLO_5x5)
+func LeagueAnalysisType(queue string) string {
+	return "league:" + queue
+}
+
+// SnapshotResult resumen de un snapshot de meta-game
+type SnapshotResult struct {
+	Platform        string   `json:"platform"`
+	Patch           string   `json:"patch"`
+	RotationChanged bool     `json:"rotation_changed"`
+	Queues          []string `json:"queues"`
+}
+
+// SnapshotPlatform calcula rotación, ligas y reporte de una plataforma y los persiste
+func (s *MetaGameService) SnapshotPlatform(ctx context.Context, platform string, queues []string) (*SnapshotResult, error) {
+	if len(queues) == 0 {
+		queues = []string{"RANKED_SOLO_5x5"}
+	}
+
+	analysis, rotation, version, err := s.analyzeRotation(ctx, platform)
+	if err != nil {
+		return nil, err
+	}
+	patch := riot.PatchFromGameVersion(version)
+	result := &SnapshotResult{Platform: platform, Patch: patch}
+
+	freeIDs, err := json.Marshal(rotation.FreeChampionIDs)
+	if err != nil {
+		return nil, err
+	}
+	newIDs, err := json.Marshal(rotation.FreeChampionIDsForNewPlayers)
+	if err != nil {
+		return nil, err
+	}
+	changed, err := s.repo.SaveChampionRotation(ctx, &entities.ChampionRotation{
+		Game:                  "lol",
+		Platform:              platform,
+		FreeChampionIDs:       freeIDs,
+		FreeChampionIDsForNew: newIDs,
+		MaxNewPlayerLevel:     rotation.MaxNewPlayerLevel,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error saving champion rotation: %w", err)
+	}
+	result.RotationChanged = changed
+
+	if err := s.saveAnalysis(ctx, platform, patch, AnalysisTypeRotation, "7d", analysis, analysis.RecommendedChampions); err != nil {
+		return nil, err
+	}
+
+	var soloQ *LeagueAnalysis
+	for _, queue := range queues {
+		league, err := s.AnalyzeLeagueRankings(ctx, platform, queue)
+		if err != nil {
+			return result, fmt.Errorf("error analyzing %s: %w", queue, err)
+		}
+		if err := s.repo.SaveLeagueRanking(ctx, &entities.LeagueRanking{
+			Game:         "lol",
+			Platform:     platform,
+			Queue:        queue,
+			Tier:         league.Tier,
+			LeagueID:     league.LeagueID,
+			LeagueName:   league.LeagueName,
+			TotalPlayers: league.TotalPlayers,
+			AvgLP:        league.AvgLP,
+			AvgWinRate:   league.AvgWinRate,
+			TopLP:        league.TopLP,
+		}); err != nil {
+			return result, fmt.Errorf("error saving league ranking: %w", err)
+		}
+		if err := s.saveAnalysis(ctx, platform, patch, LeagueAnalysisType(queue), "snapshot", league, nil); err != nil {
+			return result, err
+		}
+		if queue == "RANKED_SOLO_5x5" || soloQ == nil {
+			soloQ = league
+		}
+		result.Queues = append(result.Queues, queue)
+	}
+
+	report := s.buildMetaReport(platform, analysis, soloQ)
+	if err := s.saveAnalysis(ctx, platform, patch, AnalysisTypeReport, "snapshot", report, report.Insights); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// saveAnalysis serializa y guarda un análisis en meta_game_analyses
+func (s *MetaGameService) saveAnalysis(ctx context.Context, platform, patch, analysisType, timeFrame string, data any, insights []string) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	row := &entities.MetaGameAnalysis{
+		Game:         "lol",
+		Platform:     platform,
+		Patch:        patch,
+		AnalysisType: analysisType,
+		TimeFrame:    timeFrame,
+		Data:         raw,
+	}
+	if len(insights) > 0 {
+		if row.Insights, err = json.Marshal(insights); err != nil {
+			return err
+		}
+	}
+	if err := s.repo.SaveMetaAnalysis(ctx, row); err != nil {
+		return fmt.Errorf("error saving %s analysis: %w", analysisType, err)
+	}
+	return nil
+}
+
+// LatestAnalysis devuelve el último análisis guardado (nil si el worker aún no corrió)
+func (s *MetaGameService) LatestAnalysis(ctx context.Context, platform, analysisType string) (*entities.MetaGameAnalysis, error) {
+	return s.repo.LatestMetaAnalysis(ctx, "lol", platform, analysisType)
+}
+
+// AnalysisHistory devuelve los análisis guardados más recientes primero
+func (s *MetaGameService) AnalysisHistory(ctx context.Context, platform, analysisType string, limit int) ([]entities.MetaGameAnalysis, error) {
+	return s.repo.MetaAnalysisHistory(ctx, "lol", platform, analysisType, limit)
+}
+
+// RotationHistory devuelve las rotaciones registradas de una plataforma
+func (s *MetaGameService) RotationHistory(ctx context.Context, platform string, limit int) ([]entities.ChampionRotation, error) {
+	return s.repo.ChampionRotationHistory(ctx, "lol", platform, limit)
+}
+
+// LeagueHistory devuelve la evolución de las estadísticas de una liga
+func (s *MetaGameService) LeagueHistory(ctx context.Context, platform, queue string, limit int) ([]entities.LeagueRanking, error) {
+	return s.repo.LeagueRankingHistory(ctx, "lol", platform, queue, "", limit)
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ProMatchFile archivo JSON de partidas profesionales de un juego
+type ProMatchFile struct {
+	Game    string           `json:"game"` // lol|val
+	Matches []ProMatchRecord `json:"matches"`
+}
+
+// ProMatchRecord partida profesional tal como viene en el archivo de importación
+type ProMatchRecord struct {
+	MatchID  string          `json:"match_id"`
+	League   string          `json:"league"` // código de la liga (LEC, LCK, VCT EMEA, ...)
+	Season   string          `json:"season"` // Summer 2024, Stage 1 2025, ...
+	Patch    string          `json:"patch"`
+	Map      string          `json:"map,omitempty"` // solo Valorant
+	PlayedAt *time.Time      `json:"played_at,omitempty"`
+	Teams    []ProTeamRecord `json:"teams"`
+}
+
+// ProTeamRecord equipo de una partida profesional
+type ProTeamRecord struct {
+	Name  string          `json:"name"`
+	Side  string          `json:"side"` // LoL: blue|red, VAL: opcional
+	Win   bool            `json:"win"`
+	Picks []ProPickRecord `json:"picks"`
+	Bans  []string        `json:"bans"`
+}
+
+// ProPickRecord campeón (o agente) elegido por un jugador; Role es opcional en Valorant
+type ProPickRecord struct {
+	Role     string `json:"role"`
+	Champion string `json:"champion"`
+}
+
+// proCSVColumns columnas obligatorias del CSV de partidas profesionales
+var proCSVColumns = []string{"match_id", "league", "season", "patch", "team", "side", "win", "picks"}
+
+// ParseProMatchesJSON lee un archivo ProMatchFile
+func ParseProMatchesJSON(r io.Reader) (*ProMatchFile, error) {
+	var file ProMatchFile
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid json: %w", err)
+	}
+	return &file, nil
+}
+
+// ParseProMatchesCSV lee partidas profesionales en CSV, una fila por equipo y partida:
+//
+//	match_id,league,season,patch,played_at,map,team,side,win,picks,bans
+//	LEC-2024-S-001,LEC,Summer 2024,14.14,2024-06-15T17:00:00Z,,G2,blue,true,top:Jax|jungle:Vi|mid:Ahri|bot:Jinx|support:Leona,Yuumi|Kalista
+//
+// picks es "rol:campeón" separado por "|" (en Valorant el rol es opcional: "Jett|Omen|...").
+// played_at, map y bans son opcionales; las columnas se buscan por nombre en la cabecera.
+func ParseProMatchesCSV(r io.Reader) ([]ProMatchRecord, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid csv header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, h := range header {
+		cols[strings.ToLower(strings.TrimSpace(h))] = i
+	}
+	for _, c := range proCSVColumns {
+		if _, ok := cols[c]; !ok {
+			return nil, fmt.Errorf("csv column %q missing", c)
+		}
+	}
+	get := func(row []string, col string) string {
+		i, ok := cols[col]
+		if !ok || i >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[i])
+	}
+
+	var records []ProMatchRecord
+	index := make(map[string]int) // league/match_id -> posición en records
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: %w", line, err)
+		}
+
+		win, err := strconv.ParseBool(get(row, "win"))
+		if err != nil {
+			return nil, fmt.Errorf("csv line %d: invalid win %q", line, get(row, "win"))
+		}
+		team := ProTeamRecord{Name: get(row, "team"), Side: get(row, "side"), Win: win}
+		for _, p := range splitPipe(get(row, "picks")) {
+			pick := ProPickRecord{Champion: p}
+			if role, champion, ok := strings.Cut(p, ":"); ok {
+				pick = ProPickRecord{Role: strings.TrimSpace(role), Champion: strings.TrimSpace(champion)}
+			}
+			team.Picks = append(team.Picks, pick)
+		}
+		team.Bans = splitPipe(get(row, "bans"))
+
+		key := get(row, "league") + "/" + get(row, "match_id")
+		i, ok := index[key]
+		if !ok {
+			record := ProMatchRecord{
+				MatchID: get(row, "match_id"), League: get(row, "league"), Season: get(row, "season"),
+				Patch: get(row, "patch"), Map: get(row, "map"),
+			}
+			if v := get(row, "played_at"); v != "" {
+				t, err := parseProTime(v)
+				if err != nil {
+					return nil, fmt.Errorf("csv line %d: invalid played_at %q", line, v)
+				}
+				record.PlayedAt = &t
+			}
+			i = len(records)
+			index[key] = i
+			records = append(records, record)
+		}
+		records[i].Teams = append(records[i].Teams, team)
+	}
+	return records, nil
+}
+
+// parseProTime acepta RFC3339 o fecha sola (2024-06-15)
+func parseProTime(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", v)
+}
+
+// splitPipe separa una lista "a|b|c" descartando elementos vacíos
+func splitPipe(v string) []string {
+	var result []string
+	for _, p := range strings.Split(v, "|") {
+		if p = strings.TrimSpace(p); p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
@@ -0,0 +1,426 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+)
+
+// ErrProLeagueNotFound la liga profesional pedida no existe
+var ErrProLeagueNotFound = errors.New("professional league not found")
+
+// ErrInvalidProImport el archivo de partidas profesionales no es válido
+var ErrInvalidProImport = errors.New("invalid pro match import")
+
+// proRolePositions roles aceptados en los archivos de LoL -> teamPosition de Match-V5
+var proRolePositions = map[string]string{
+	"top": "TOP", "jungle": "JUNGLE", "jg": "JUNGLE", "jgl": "JUNGLE",
+	"mid": "MIDDLE", "middle": "MIDDLE",
+	"bot": "BOTTOM", "bottom": "BOTTOM", "adc": "BOTTOM",
+	"support": "UTILITY", "sup": "UTILITY", "utility": "UTILITY",
+}
+
+// proPositionLabels teamPosition -> LeagueChampionStats.Position
+var proPositionLabels = map[string]string{
+	"TOP": "Top", "JUNGLE": "Jungle", "MIDDLE": "Mid", "BOTTOM": "Bot", "UTILITY": "Support",
+}
+
+// ProLeagueService sirve las ligas profesionales desde la base de datos e importa sus partidas
+type ProLeagueService struct {
+	repo out.Repository
+}
+
+// NewProLeagueService crea un nuevo servicio de ligas profesionales
+func NewProLeagueService(repo out.Repository) *ProLeagueService {
+	return &ProLeagueService{repo: repo}
+}
+
+// ProLeague liga profesional con su temporada más reciente
+type ProLeague struct {
+	Code          string     `json:"code"`
+	Game          string     `json:"game"`
+	Name          string     `json:"name"`
+	Region        string     `json:"region"`
+	Platform      string     `json:"platform"`
+	Seasons       []string   `json:"seasons"`
+	Teams         int        `json:"teams"`
+	Description   string     `json:"description"`
+	CurrentSeason string     `json:"current_season"` // "" si no hay estadísticas
+	GamesAnalyzed int        `json:"games_analyzed"`
+	LastUpdated   *time.Time `json:"last_updated,omitempty"`
+}
+
+// ProLeagueList ligas profesionales activas
+type ProLeagueList struct {
+	TotalLeagues int         `json:"total_leagues"`
+	Leagues      []ProLeague `json:"leagues"`
+	LastUpdated  *time.Time  `json:"last_updated,omitempty"`
+}
+
+// ProLeagueChampions estadísticas de campeones de una liga y temporada
+type ProLeagueChampions struct {
+	League             string                         `json:"league"`
+	Game               string                         `json:"game"`
+	Season             string                         `json:"season"`
+	Seasons            []string                       `json:"seasons"` // temporadas con datos, la más reciente primero
+	TotalGamesAnalyzed int                            `json:"total_games_analyzed"`
+	LastUpdated        *time.Time                     `json:"last_updated,omitempty"`
+	MostPicked         []entities.LeagueChampionStats `json:"most_picked_champions"`
+	MostBanned         []entities.LeagueChampionStats `json:"most_banned_champions"`
+}
+
+// ProImportResult resumen de una importación de partidas profesionales
+type ProImportResult struct {
+	Game           string            `json:"game"`
+	Matches        int               `json:"matches"`
+	CreatedLeagues []string          `json:"created_leagues"`
+	Seasons        []ProSeasonResult `json:"seasons"`
+}
+
+// ProSeasonResult liga y temporada recalculada por una importación
+type ProSeasonResult struct {
+	League    string `json:"league"`
+	Season    string `json:"season"`
+	Games     int    `json:"games"`
+	Champions int    `json:"champions"`
+}
+
+// Leagues devuelve las ligas activas (game "" = todas) con su temporada más reciente
+func (s *ProLeagueService) Leagues(ctx context.Context, game string) (*ProLeagueList, error) {
+	leagues, err := s.repo.ProfessionalLeagues(ctx, game)
+	if err != nil {
+		return nil, err
+	}
+	result := &ProLeagueList{Leagues: make([]ProLeague, 0, len(leagues))}
+	for _, l := range leagues {
+		item := ProLeague{
+			Code: l.Code, Game: l.Game, Name: l.Name, Region: l.Region, Platform: l.Platform,
+			Seasons: []string{}, Teams: l.Teams, Description: l.Description,
+		}
+		if l.Seasons != "" {
+			_ = json.Unmarshal([]byte(l.Seasons), &item.Seasons)
+		}
+		seasons, err := s.repo.LeagueSeasons(ctx, l.Code)
+		if err != nil {
+			return nil, err
+		}
+		if len(seasons) > 0 {
+			latest := seasons[0]
+			item.CurrentSeason = latest.Season
+			item.GamesAnalyzed = latest.Games
+			item.LastUpdated = &latest.LastUpdated
+			if result.LastUpdated == nil || latest.LastUpdated.After(*result.LastUpdated) {
+				result.LastUpdated = &latest.LastUpdated
+			}
+		}
+		result.Leagues = append(result.Leagues, item)
+	}
+	result.TotalLeagues = len(result.Leagues)
+	return result, nil
+}
+
+// LeagueChampions devuelve los campeones más elegidos y más baneados de una liga.
+// Sin season usa la temporada con datos más reciente.
+func (s *ProLeagueService) LeagueChampions(ctx context.Context, code, season string, limit int) (*ProLeagueChampions, error) {
+	if limit <= 0 || limit > 200 {
+		limit = 20
+	}
+	league, err := s.repo.ProfessionalLeague(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if league == nil {
+		return nil, ErrProLeagueNotFound
+	}
+	seasons, err := s.repo.LeagueSeasons(ctx, league.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ProLeagueChampions{
+		League: league.Code, Game: league.Game, Season: season, Seasons: []string{},
+		MostPicked: []entities.LeagueChampionStats{}, MostBanned: []entities.LeagueChampionStats{},
+	}
+	for _, ls := range seasons {
+		result.Seasons = append(result.Seasons, ls.Season)
+	}
+	if result.Season == "" {
+		if len(seasons) == 0 {
+			return result, nil
+		}
+		result.Season = seasons[0].Season
+	}
+
+	rows, err := s.repo.LeagueChampionStats(ctx, league.Code, result.Season)
+	if err != nil {
+		return nil, err
+	}
+	for i, row := range rows {
+		if row.GamesAnalyzed > result.TotalGamesAnalyzed {
+			result.TotalGamesAnalyzed = row.GamesAnalyzed
+		}
+		if result.LastUpdated == nil || row.LastUpdated.After(*result.LastUpdated) {
+			result.LastUpdated = &rows[i].LastUpdated
+		}
+		if row.PickRate > 0 && len(result.MostPicked) < limit {
+			result.MostPicked = append(result.MostPicked, row)
+		}
+	}
+
+	banned := make([]entities.LeagueChampionStats, 0, len(rows))
+	for _, row := range rows {
+		if row.BanRate > 0 {
+			banned = append(banned, row)
+		}
+	}
+	sort.SliceStable(banned, func(i, j int) bool { return banned[i].BanRate > banned[j].BanRate })
+	if len(banned) > limit {
+		banned = banned[:limit]
+	}
+	result.MostBanned = banned
+	return result, nil
+}
+
+// Import guarda partidas profesionales (upsert por liga y match_id) y recalcula las
+// estadísticas de campeones de cada liga y temporada tocada. Las ligas desconocidas se
+// crean con el código como nombre.
+func (s *ProLeagueService) Import(ctx context.Context, game string, records []ProMatchRecord) (*ProImportResult, error) {
+	if game != "lol" && game != "val" {
+		return nil, fmt.Errorf("%w: game must be lol or val", ErrInvalidProImport)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%w: no matches", ErrInvalidProImport)
+	}
+
+	result := &ProImportResult{Game: game, CreatedLeagues: []string{}, Seasons: []ProSeasonResult{}}
+	leagues := make(map[string]*entities.ProfessionalLeague)
+	type seasonKey struct{ league, season string }
+	var touched []seasonKey
+	seen := make(map[seasonKey]bool)
+
+	matches := make([]entities.ProMatch, 0, len(records))
+	for _, rec := range records {
+		if rec.MatchID == "" || rec.League == "" || rec.Season == "" {
+			return nil, fmt.Errorf("%w: match %q: match_id, league and season required", ErrInvalidProImport, rec.MatchID)
+		}
+		if len(rec.Teams) != 2 {
+			return nil, fmt.Errorf("%w: match %q: expected 2 teams, got %d", ErrInvalidProImport, rec.MatchID, len(rec.Teams))
+		}
+
+		league, err := s.league(ctx, game, rec.League, leagues, result)
+		if err != nil {
+			return nil, err
+		}
+
+		summary, err := proMatchSummary(game, rec)
+		if err != nil {
+			return nil, fmt.Errorf("%w: match %q: %v", ErrInvalidProImport, rec.MatchID, err)
+		}
+		raw, err := json.Marshal(summary)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, entities.ProMatch{
+			Game: game, LeagueCode: league.Code, MatchID: rec.MatchID, Season: rec.Season,
+			Patch: rec.Patch, Map: rec.Map, PlayedAt: rec.PlayedAt, Summary: raw,
+		})
+
+		key := seasonKey{league.Code, rec.Season}
+		if !seen[key] {
+			seen[key] = true
+			touched = append(touched, key)
+		}
+	}
+
+	if err := s.repo.UpsertProMatches(ctx, matches); err != nil {
+		return nil, fmt.Errorf("error saving pro matches: %w", err)
+	}
+	result.Matches = len(matches)
+
+	for _, key := range touched {
+		season, err := s.RebuildSeason(ctx, game, key.league, key.season)
+		if err != nil {
+			return nil, fmt.Errorf("error rebuilding %s %s: %w", key.league, key.season, err)
+		}
+		result.Seasons = append(result.Seasons, *season)
+	}
+	return result, nil
+}
+
+// league resuelve (o crea) la liga de una partida importada
+func (s *ProLeagueService) league(ctx context.Context, game, code string, cache map[string]*entities.ProfessionalLeague, result *ProImportResult) (*entities.ProfessionalLeague, error) {
+	code = strings.TrimSpace(code)
+	if l, ok := cache[strings.ToUpper(code)]; ok {
+		return l, nil
+	}
+	league, err := s.repo.ProfessionalLeague(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if league == nil {
+		if len(code) > 10 {
+			return nil, fmt.Errorf("%w: league code %q longer than 10 characters", ErrInvalidProImport, code)
+		}
+		league = &entities.ProfessionalLeague{Code: code, Game: game, Name: code, IsActive: true}
+		if err := s.repo.EnsureProfessionalLeague(ctx, league); err != nil {
+			return nil, fmt.Errorf("error creating league %s: %w", code, err)
+		}
+		log.Ctx(ctx).Info().Str("league", code).Str("game", game).Msg("professional league created from import")
+		result.CreatedLeagues = append(result.CreatedLeagues, code)
+	}
+	if league.Game != game {
+		return nil, fmt.Errorf("%w: league %s belongs to game %s", ErrInvalidProImport, league.Code, league.Game)
+	}
+	cache[strings.ToUpper(code)] = league
+	return league, nil
+}
+
+// proMatchSummary convierte una partida del archivo al MatchSummary que se guarda.
+// En LoL los roles se traducen a teamPosition para reutilizar CanonicalLolSlots.
+func proMatchSummary(game string, rec ProMatchRecord) (entities.MatchSummary, error) {
+	summary := entities.MatchSummary{Teams: make([]entities.MatchTeamSummary, 0, len(rec.Teams))}
+	for i, t := range rec.Teams {
+		team := entities.MatchTeamSummary{
+			TeamID: 100 * (i + 1), Name: t.Name, Side: strings.ToLower(t.Side), Win: t.Win,
+			Picks: []entities.MatchPickSummary{}, Bans: []int{},
+		}
+		for _, p := range t.Picks {
+			name := normalizeMember(game, p.Champion)
+			if name == "" {
+				return summary, errors.New("empty pick")
+			}
+			role := strings.ToLower(strings.TrimSpace(p.Role))
+			if game == "lol" {
+				position, ok := proRolePositions[role]
+				if !ok {
+					return summary, fmt.Errorf("unknown role %q for %s", p.Role, p.Champion)
+				}
+				role = position
+			}
+			team.Picks = append(team.Picks, entities.MatchPickSummary{ChampionName: name, Role: role})
+		}
+		for _, b := range t.Bans {
+			team.BanNames = append(team.BanNames, normalizeMember(game, b))
+		}
+		summary.Teams = append(summary.Teams, team)
+	}
+	return summary, nil
+}
+
+// proChampionTally acumulado de un campeón (o agente) en una liga y temporada
+type proChampionTally struct {
+	name                  string
+	presence, picks, wins int
+	bans                  int
+	positions             map[string]int
+}
+
+// RebuildSeason recalcula app.league_champion_stats de una liga y temporada a partir de sus
+// partidas: pick_rate y ban_rate son % de partidas en las que el campeón fue elegido/baneado
+// y win_rate es % de picks ganados. La posición es la más jugada.
+func (s *ProLeagueService) RebuildSeason(ctx context.Context, game, code, season string) (*ProSeasonResult, error) {
+	matches, err := s.repo.ProMatches(ctx, code, season)
+	if err != nil {
+		return nil, err
+	}
+
+	tallies := make(map[string]*proChampionTally)
+	tally := func(name string) *proChampionTally {
+		key := strings.ToLower(name)
+		t := tallies[key]
+		if t == nil {
+			t = &proChampionTally{name: name, positions: make(map[string]int)}
+			tallies[key] = t
+		}
+		return t
+	}
+
+	games := 0
+	for _, m := range matches {
+		var summary entities.MatchSummary
+		if err := json.Unmarshal(m.Summary, &summary); err != nil {
+			continue
+		}
+		games++
+		picked := make(map[string]bool)
+		banned := make(map[string]bool)
+		for _, team := range summary.Teams {
+			for _, p := range team.Picks {
+				t := tally(p.ChampionName)
+				t.picks++
+				if team.Win {
+					t.wins++
+				}
+				if position := proPosition(game, p); position != "" {
+					t.positions[position]++
+				}
+				if key := strings.ToLower(p.ChampionName); !picked[key] {
+					picked[key] = true
+					t.presence++
+				}
+			}
+			for _, b := range team.BanNames {
+				if key := strings.ToLower(b); b != "" && !banned[key] {
+					banned[key] = true
+					tally(b).bans++
+				}
+			}
+		}
+	}
+
+	now := time.Now().UTC()
+	rows := make([]entities.LeagueChampionStats, 0, len(tallies))
+	for _, t := range tallies {
+		row := entities.LeagueChampionStats{
+			LeagueCode: code, ChampionName: t.name, Season: season,
+			GamesAnalyzed: games, LastUpdated: now, Position: topPosition(t.positions),
+		}
+		if games > 0 {
+			row.PickRate = stats.Round(100*float64(t.presence)/float64(games), 2)
+			row.BanRate = stats.Round(100*float64(t.bans)/float64(games), 2)
+		}
+		if t.picks > 0 {
+			row.WinRate = stats.Round(100*float64(t.wins)/float64(t.picks), 2)
+		}
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ChampionName < rows[j].ChampionName })
+
+	if err := s.repo.ReplaceLeagueChampionStats(ctx, code, season, rows); err != nil {
+		return nil, err
+	}
+	return &ProSeasonResult{League: code, Season: season, Games: games, Champions: len(rows)}, nil
+}
+
+// proPosition etiqueta de la posición de un pick (en Valorant, el rol del agente)
+func proPosition(game string, p entities.MatchPickSummary) string {
+	if game == "lol" {
+		return proPositionLabels[p.Role]
+	}
+	role := p.Role
+	if role == "" {
+		role = valAgentRoles[NormalizeAgent(p.ChampionName)]
+	}
+	return titleRole(role)
+}
+
+// topPosition posición más frecuente (la primera alfabéticamente en caso de empate)
+func topPosition(positions map[string]int) string {
+	best, count := "", 0
+	for p, n := range positions {
+		if n > count || (n == count && p < best) {
+			best, count = p, n
+		}
+	}
+	return best
+}
@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+)
+
+// rotationEffectMinMatches partidas mínimas dentro y fuera de la semana gratis para medir el efecto
+const rotationEffectMinMatches = 50
+
+const week = 7 * 24 * time.Hour
+
+// RotationFrequency frecuencia de cada campeón en las rotaciones guardadas de una plataforma
+type RotationFrequency struct {
+	Platform  string              `json:"platform"`
+	Rotations int                 `json:"rotations"`
+	From      *time.Time          `json:"from,omitempty"`
+	To        *time.Time          `json:"to,omitempty"`
+	Champions []ChampionFrequency `json:"champions"`
+}
+
+// ChampionFrequency apariciones de un campeón en la rotación gratuita
+type ChampionFrequency struct {
+	ID             int             `json:"id"`
+	Name           string          `json:"name"`
+	Appearances    int             `json:"appearances"`
+	Rate           float64         `json:"rate"` // % de rotaciones en las que estuvo gratis
+	CurrentlyFree  bool            `json:"currently_free"`
+	LastFreeAt     *time.Time      `json:"last_free_at,omitempty"`
+	WeeksSinceFree *float64        `json:"weeks_since_free,omitempty"` // desde el inicio de su última semana gratis
+	AvgGapWeeks    *float64        `json:"avg_gap_weeks,omitempty"`    // semanas medias entre inicios de semanas gratis
+	FreeWeekEffect *FreeWeekEffect `json:"free_week_effect,omitempty"`
+}
+
+// FreeWeekEffect pick rate de un campeón en sus semanas gratis frente al resto del período
+type FreeWeekEffect struct {
+	FreeMatches      int     `json:"free_matches"`
+	FreePickRate     float64 `json:"free_pick_rate"`
+	BaselineMatches  int     `json:"baseline_matches"`
+	BaselinePickRate float64 `json:"baseline_pick_rate"`
+	Lift             float64 `json:"lift"` // puntos de pick rate
+}
+
+// RotationForecast campeones atrasados respecto de su frecuencia en la rotación
+type RotationForecast struct {
+	Platform        string             `json:"platform"`
+	Rotations       int                `json:"rotations"`
+	Overdue         []ChampionForecast `json:"overdue"`
+	NeverFree       []string           `json:"never_free"`
+	AvgFreeWeekLift *float64           `json:"avg_free_week_lift,omitempty"` // media de Lift de los campeones medidos
+	Methodology     string             `json:"methodology"`
+}
+
+// ChampionForecast campeón atrasado respecto de su frecuencia habitual
+type ChampionForecast struct {
+	ChampionFrequency
+	OverdueRatio  float64 `json:"overdue_ratio"`  // weeks_since_free / avg_gap_weeks
+	ForecastScore float64 `json:"forecast_score"` // rate × overdue_ratio (ranking, no probabilidad)
+}
+
+// rotationWindow una rotación guardada con su intervalo de vigencia
+type rotationWindow struct {
+	start, end time.Time
+	free       map[int]bool
+}
+
+// RotationFrequency calcula cuántas veces estuvo gratis cada campeón en las últimas weeks
+// rotaciones guardadas, cuánto hace de la última vez y el efecto de la semana gratis en su
+// pick rate (partidas Ranked Solo ingeridas de la plataforma)
+func (s *MetaGameService) RotationFrequency(ctx context.Context, platform string, weeks int) (*RotationFrequency, error) {
+	windows, err := s.rotationWindows(ctx, platform, weeks)
+	if err != nil {
+		return nil, err
+	}
+	result := &RotationFrequency{Platform: platform, Rotations: len(windows), Champions: []ChampionFrequency{}}
+	if len(windows) == 0 {
+		return result, nil
+	}
+	result.From, result.To = &windows[0].start, &windows[len(windows)-1].end
+
+	names := s.championNames(ctx)
+	effects, err := s.freeWeekEffects(ctx, platform, windows, names)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	current := windows[len(windows)-1]
+	ids := make(map[int]bool)
+	for id := range names {
+		ids[id] = true
+	}
+	for _, w := range windows {
+		for id := range w.free {
+			ids[id] = true
+		}
+	}
+
+	for id := range ids {
+		cf := ChampionFrequency{ID: id, Name: names[id], CurrentlyFree: current.free[id], FreeWeekEffect: effects[id]}
+		if cf.Name == "" {
+			cf.Name = "Champion_" + strconv.Itoa(id)
+		}
+		var starts []time.Time
+		for _, w := range windows {
+			if w.free[id] {
+				starts = append(starts, w.start)
+			}
+		}
+		cf.Appearances = len(starts)
+		cf.Rate = stats.Round(100*float64(len(starts))/float64(len(windows)), 2)
+		if len(starts) > 0 {
+			last := starts[len(starts)-1]
+			since := roundWeeks(now.Sub(last))
+			cf.LastFreeAt, cf.WeeksSinceFree = &last, &since
+		}
+		if len(starts) > 1 {
+			gap := roundWeeks(starts[len(starts)-1].Sub(starts[0]) / time.Duration(len(starts)-1))
+			cf.AvgGapWeeks = &gap
+		}
+		result.Champions = append(result.Champions, cf)
+	}
+
+	sort.Slice(result.Champions, func(i, j int) bool {
+		a, b := result.Champions[i], result.Champions[j]
+		if a.Appearances != b.Appearances {
+			return a.Appearances > b.Appearances
+		}
+		return a.Name < b.Name
+	})
+	return result, nil
+}
+
+// RotationForecast lista los campeones que no están gratis y ya superaron su intervalo habitual
+// entre semanas gratis, ordenados por forecast_score. Los que estuvieron gratis una sola vez usan como intervalo todo el
+// período analizado.
+func (s *MetaGameService) RotationForecast(ctx context.Context, platform string, weeks, limit int) (*RotationForecast, error) {
+	freq, err := s.RotationFrequency(ctx, platform, weeks)
+	if err != nil {
+		return nil, err
+	}
+	result := &RotationForecast{
+		Platform: platform, Rotations: freq.Rotations, Overdue: []ChampionForecast{}, NeverFree: []string{},
+		Methodology: "overdue_ratio = weeks since the start of the champion's last free week / its average weeks between free weeks " +
+			"(the whole analyzed period if it was free only once). forecast_score = rate × overdue_ratio ranks the champions that are " +
+			"not free now; it is not a calibrated probability. free_week_effect compares the champion's Ranked Solo pick rate during " +
+			"its free weeks with the rest of the period; stream (HypeMap) data has no per-champion breakdown and is not used.",
+	}
+	if freq.Rotations == 0 {
+		return result, nil
+	}
+
+	span := freq.To.Sub(*freq.From).Hours() / week.Hours()
+	lifts, measured := 0.0, 0
+	for _, cf := range freq.Champions {
+		if cf.FreeWeekEffect != nil {
+			lifts += cf.FreeWeekEffect.Lift
+			measured++
+		}
+		if cf.Appearances == 0 {
+			result.NeverFree = append(result.NeverFree, cf.Name)
+			continue
+		}
+		if cf.CurrentlyFree {
+			continue
+		}
+		gap := span
+		if cf.AvgGapWeeks != nil && *cf.AvgGapWeeks > 0 {
+			gap = *cf.AvgGapWeeks
+		}
+		if gap <= 0 || cf.WeeksSinceFree == nil {
+			continue
+		}
+		ratio := *cf.WeeksSinceFree / gap
+		if ratio < 1 {
+			continue
+		}
+		result.Overdue = append(result.Overdue, ChampionForecast{
+			ChampionFrequency: cf,
+			OverdueRatio:      stats.Round(ratio, 2),
+			ForecastScore:     stats.Round(cf.Rate*ratio, 2),
+		})
+	}
+	if measured > 0 {
+		avg := stats.Round(lifts/float64(measured), 2)
+		result.AvgFreeWeekLift = &avg
+	}
+
+	sort.Slice(result.Overdue, func(i, j int) bool {
+		if result.Overdue[i].ForecastScore != result.Overdue[j].ForecastScore {
+			return result.Overdue[i].ForecastScore > result.Overdue[j].ForecastScore
+		}
+		return result.Overdue[i].Name < result.Overdue[j].Name
+	})
+	if limit > 0 && len(result.Overdue) > limit {
+		result.Overdue = result.Overdue[:limit]
+	}
+	sort.Strings(result.NeverFree)
+	return result, nil
+}
+
+// rotationWindows carga las últimas weeks rotaciones en orden cronológico; cada una dura hasta
+// rotation_ends_at, el inicio de la siguiente o ahora
+func (s *MetaGameService) rotationWindows(ctx context.Context, platform string, weeks int) ([]rotationWindow, error) {
+	rotations, err := s.repo.ChampionRotationHistory(ctx, "lol", platform, weeks)
+	if err != nil {
+		return nil, fmt.Errorf("error loading rotations: %w", err)
+	}
+	windows := make([]rotationWindow, 0, len(rotations))
+	now := time.Now().UTC()
+	for i := len(rotations) - 1; i >= 0; i-- {
+		rot := rotations[i]
+		var ids []int
+		if err := json.Unmarshal(rot.FreeChampionIDs, &ids); err != nil {
+			continue
+		}
+		w := rotationWindow{start: rot.CreatedAt, end: now, free: make(map[int]bool, len(ids))}
+		if rot.RotationStartedAt != nil {
+			w.start = *rot.RotationStartedAt
+		}
+		if rot.RotationEndsAt != nil {
+			w.end = *rot.RotationEndsAt
+		} else if i > 0 {
+			w.end = rotations[i-1].CreatedAt
+			if rotations[i-1].RotationStartedAt != nil {
+				w.end = *rotations[i-1].RotationStartedAt
+			}
+		}
+		for _, id := range ids {
+			w.free[id] = true
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// freeWeekEffects compara el pick rate de cada campeón (presencia por partida) dentro y fuera
+// de sus semanas gratis, con las partidas Ranked Solo registradas durante las rotaciones
+func (s *MetaGameService) freeWeekEffects(ctx context.Context, platform string, windows []rotationWindow, names map[int]string) (map[int]*FreeWeekEffect, error) {
+	matches, err := s.repo.RiotMatchesSince(ctx, "lol", platform, riot.QueueIDRankedSolo, windows[0].start)
+	if err != nil {
+		return nil, fmt.Errorf("error loading matches: %w", err)
+	}
+
+	windowMatches := make([]int, len(windows))
+	picked := make(map[int][]int) // campeón -> partidas con el campeón por ventana
+	for _, m := range matches {
+		w := sort.Search(len(windows), func(i int) bool { return windows[i].end.After(m.GameCreation) })
+		if w == len(windows) || m.GameCreation.Before(windows[w].start) {
+			continue
+		}
+		var summary entities.MatchSummary
+		if err := json.Unmarshal(m.Summary, &summary); err != nil {
+			continue
+		}
+		windowMatches[w]++
+		seen := make(map[int]bool)
+		for _, team := range summary.Teams {
+			for _, p := range team.Picks {
+				if seen[p.ChampionID] {
+					continue
+				}
+				seen[p.ChampionID] = true
+				if names[p.ChampionID] == "" {
+					names[p.ChampionID] = p.ChampionName
+				}
+				if picked[p.ChampionID] == nil {
+					picked[p.ChampionID] = make([]int, len(windows))
+				}
+				picked[p.ChampionID][w]++
+			}
+		}
+	}
+
+	effects := make(map[int]*FreeWeekEffect)
+	for id, byWindow := range picked {
+		var freeMatches, freePicks, baseMatches, basePicks int
+		for w := range windows {
+			if windows[w].free[id] {
+				freeMatches += windowMatches[w]
+				freePicks += byWindow[w]
+			} else {
+				baseMatches += windowMatches[w]
+				basePicks += byWindow[w]
+			}
+		}
+		if freeMatches < rotationEffectMinMatches || baseMatches < rotationEffectMinMatches {
+			continue
+		}
+		free := 100 * float64(freePicks) / float64(freeMatches)
+		base := 100 * float64(basePicks) / float64(baseMatches)
+		effects[id] = &FreeWeekEffect{
+			FreeMatches: freeMatches, FreePickRate: stats.Round(free, 2),
+			BaselineMatches: baseMatches, BaselinePickRate: stats.Round(base, 2),
+			Lift: stats.Round(free-base, 2),
+		}
+	}
+	return effects, nil
+}
+
+// championNames ID -> nombre según Data Dragon (vacío si no responde)
+func (s *MetaGameService) championNames(ctx context.Context) map[int]string {
+	names := make(map[int]string)
+	latest, err := s.dataDragon.GetLatestVersion(ctx)
+	if err != nil {
+		return names
+	}
+	champions, err := s.dataDragon.GetChampions(ctx, latest)
+	if err != nil {
+		return names
+	}
+	for _, c := range champions.Data {
+		if id, err := strconv.Atoi(c.Key); err == nil {
+			names[id] = c.Name
+		}
+	}
+	return names
+}
+
+// roundWeeks semanas entre dos instantes con un decimal
+func roundWeeks(d time.Duration) float64 {
+	return math.Round(d.Hours()/week.Hours()*10) / 10
+}
@@ -9,9 +9,14 @@ import (
 	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
 )
 
-type svc struct{ repo out.Repository }
+type svc struct {
+	repo  out.Repository
+	tiers *TierListService
+}
 
-func New(r out.Repository) in.Service { return &svc{repo: r} }
+func New(r out.Repository) in.Service {
+	return &svc{repo: r, tiers: NewTierListService(r, TierListConfigFromEnv())}
+}
 
 func (s *svc) ListPatches(ctx context.Context, game string) ([]entities.Patch, error) {
 	if game == "" {
@@ -27,6 +32,18 @@ func (s *svc) ListChanges(ctx context.Context, game, version, entityType string)
 	return s.repo.PatchChanges(ctx, game, version, entityType)
 }
 
+// ImpactSummary resume el impacto guardado de los cambios de un parche (ver ImpactService)
+func (s *svc) ImpactSummary(ctx context.Context, game, version string, top int) (*in.ImpactSummary, error) {
+	if game == "" || version == "" {
+		return nil, errors.New("game and version required")
+	}
+	changes, err := s.repo.PatchChanges(ctx, game, version, "")
+	if err != nil {
+		return nil, err
+	}
+	return SummarizeImpact(game, version, changes, top), nil
+}
+
 func (s *svc) ListLeagues(ctx context.Context, game, region string) ([]entities.League, error) {
 	if game == "" {
 		return nil, errors.New("game required")
@@ -34,12 +51,34 @@ func (s *svc) ListLeagues(ctx context.Context, game, region string) ([]entities.
 	return s.repo.Leagues(ctx, game, region)
 }
 
-func (s *svc) ListComps(ctx context.Context, game, region, league, patch, mapp, side string, limit int) ([]entities.Comp, error) {
-	if game == "" || region == "" || patch == "" {
+func (s *svc) ListComps(ctx context.Context, q out.CompQuery) ([]entities.Comp, error) {
+	if q.Game == "" || q.Region == "" || q.Patch == "" {
 		return nil, errors.New("game, region and patch required")
 	}
-	if limit <= 0 || limit > 200 {
-		limit = 50
+	switch q.Shape {
+	case "", entities.CompShapeFull, entities.CompShapeBotDuo, entities.CompShapeJungleMid:
+	default:
+		return nil, errors.New("shape must be one of full, bot_duo, jungle_mid")
+	}
+	switch q.Sort {
+	case "", out.CompSortWilson, out.CompSortWinRate, out.CompSortPickRate, out.CompSortDelta:
+	default:
+		return nil, errors.New("sort must be one of wilson, win_rate, pick_rate, delta")
+	}
+	if q.MinGames < 0 {
+		return nil, errors.New("min_games must be >= 0")
+	}
+	if q.Limit <= 0 || q.Limit > 200 {
+		q.Limit = 50
+	}
+	q.Includes = normalizeMembers(q.Game, q.Includes)
+	q.Excludes = normalizeMembers(q.Game, q.Excludes)
+	return s.repo.Comps(ctx, q)
+}
+
+func (s *svc) TierList(ctx context.Context, q in.TierListQuery) (*in.TierList, error) {
+	if q.Game == "" {
+		return nil, errors.New("game required")
 	}
-	return s.repo.Comps(ctx, game, region, league, patch, mapp, side, limit)
+	return s.tiers.Build(ctx, q)
 }
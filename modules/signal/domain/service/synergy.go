@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+)
+
+// Synergy calcula, sobre las comps completas guardadas (side ""), con qué compañeros
+// aparece with y cuánto cambia su win rate respecto al de with en general.
+// Las victorias se reconstruyen como win_rate * games.
+func (s *svc) Synergy(ctx context.Context, q out.CompQuery, with string, minGames int) (*in.SynergyReport, error) {
+	if q.Game == "" || with == "" {
+		return nil, errors.New("game and with required")
+	}
+	with = normalizeMember(q.Game, with)
+	q.Includes = append(normalizeMembers(q.Game, q.Includes), with)
+	q.Excludes = normalizeMembers(q.Game, q.Excludes)
+
+	comps, err := s.repo.SynergyComps(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &in.SynergyReport{Game: q.Game, With: with, Comps: len(comps), Pairs: []in.SynergyPartner{}}
+	type tally struct{ games, wins int }
+	partners := make(map[string]*tally)
+	var total tally
+	for _, c := range comps {
+		if c.WinRate == nil {
+			continue
+		}
+		var slots entities.CompSlots
+		if err := json.Unmarshal(c.Slots, &slots); err != nil {
+			continue
+		}
+		wins := int(math.Round(*c.WinRate * float64(c.Games) / 100))
+		total.games += c.Games
+		total.wins += wins
+		for _, m := range slots.Members {
+			name := m.Agent
+			if name == "" {
+				name = m.Champion
+			}
+			if name == "" || name == with {
+				continue
+			}
+			t := partners[name]
+			if t == nil {
+				t = &tally{}
+				partners[name] = t
+			}
+			t.games += c.Games
+			t.wins += wins
+		}
+	}
+	if total.games == 0 {
+		return report, nil
+	}
+
+	baseWin := 100 * float64(total.wins) / float64(total.games)
+	report.Games = total.games
+	report.WinRate = stats.Round(baseWin, 3)
+	for name, t := range partners {
+		if t.games < minGames {
+			continue
+		}
+		win := 100 * float64(t.wins) / float64(t.games)
+		low, high := stats.WilsonInterval(t.wins, t.games, stats.Z95)
+		report.Pairs = append(report.Pairs, in.SynergyPartner{
+			Member:       name,
+			Games:        t.games,
+			CoOccurrence: stats.Round(100*float64(t.games)/float64(total.games), 3),
+			WinRate:      stats.Round(win, 3),
+			Lift:         stats.Round(win-baseWin, 3),
+			WinCILow:     stats.Round(100*low, 3),
+			WinCIHigh:    stats.Round(100*high, 3),
+		})
+	}
+	sort.Slice(report.Pairs, func(i, j int) bool {
+		if report.Pairs[i].Lift != report.Pairs[j].Lift {
+			return report.Pairs[i].Lift > report.Pairs[j].Lift
+		}
+		return report.Pairs[i].Games > report.Pairs[j].Games
+	})
+	return report, nil
+}
+
+// normalizeMember lleva un agente a la forma de los slots (VAL); los campeones se usan tal cual
+func normalizeMember(game, member string) string {
+	member = strings.TrimSpace(member)
+	if game == "val" {
+		return NormalizeAgent(member)
+	}
+	return member
+}
+
+func normalizeMembers(game string, members []string) []string {
+	var result []string
+	for _, m := range members {
+		if m = normalizeMember(game, m); m != "" {
+			result = append(result, m)
+		}
+	}
+	return result
+}
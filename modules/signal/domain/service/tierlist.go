@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+	"github.com/steven230500/hypeatlas-api/shared/version"
+)
+
+// Fuentes de datos del tier list
+const (
+	TierSourceRanked = "ranked"
+	TierSourcePro    = "pro"
+)
+
+// TierUnranked tier de los campeones sin datos suficientes
+const TierUnranked = "Unranked"
+
+// tierWeights peso de cada tasa (estandarizada dentro del rol) en el score
+var tierWeights = map[string]float64{"win_rate": 0.6, "pick_rate": 0.25, "ban_rate": 0.15}
+
+// lolTierRoles orden de los roles de LoL en el tier list
+var lolTierRoles = []string{"Top", "Jungle", "Mid", "ADC", "Support"}
+
+// lolTierRoleAliases roles aceptados en ?role= y en LeagueChampionStats.Position
+var lolTierRoleAliases = map[string]string{
+	"top": "Top", "jungle": "Jungle", "jg": "Jungle", "mid": "Mid", "middle": "Mid",
+	"adc": "ADC", "bot": "ADC", "bottom": "ADC", "marksman": "ADC",
+	"support": "Support", "sup": "Support", "utility": "Support",
+}
+
+// TierListConfig parámetros del tier list
+type TierListConfig struct {
+	Cuts         []in.TierCut // ordenados de mayor a menor MinScore; por debajo del último es D
+	MinGames     int          // partidas mínimas en el rol para entrar en el tier list
+	MinRoleShare float64      // % mínimo de las partidas del campeón jugadas en el rol
+	PriorGames   int          // partidas "virtuales" con el win rate del rol (suavizado)
+}
+
+// DefaultTierListConfig cortes en desviaciones estándar del score dentro del rol
+func DefaultTierListConfig() TierListConfig {
+	return TierListConfig{
+		Cuts: []in.TierCut{
+			{Tier: "S", MinScore: 1.25},
+			{Tier: "A", MinScore: 0.5},
+			{Tier: "B", MinScore: -0.5},
+			{Tier: "C", MinScore: -1.25},
+		},
+		MinGames:     20,
+		MinRoleShare: 10,
+		PriorGames:   50,
+	}
+}
+
+// TierListConfigFromEnv aplica TIERLIST_CUTS ("S:1.25,A:0.5,B:-0.5,C:-1.25"),
+// TIERLIST_MIN_GAMES, TIERLIST_MIN_ROLE_SHARE y TIERLIST_PRIOR_GAMES sobre los defaults
+func TierListConfigFromEnv() TierListConfig {
+	cfg := DefaultTierListConfig()
+	if v := os.Getenv("TIERLIST_CUTS"); v != "" {
+		if cuts, err := ParseTierCuts(v); err == nil {
+			cfg.Cuts = cuts
+		}
+	}
+	if n, err := strconv.Atoi(os.Getenv("TIERLIST_MIN_GAMES")); err == nil && n > 0 {
+		cfg.MinGames = n
+	}
+	if f, err := strconv.ParseFloat(os.Getenv("TIERLIST_MIN_ROLE_SHARE"), 64); err == nil && f >= 0 {
+		cfg.MinRoleShare = f
+	}
+	if n, err := strconv.Atoi(os.Getenv("TIERLIST_PRIOR_GAMES")); err == nil && n >= 0 {
+		cfg.PriorGames = n
+	}
+	return cfg
+}
+
+// ParseTierCuts lee cortes "S:1.25,A:0.5,..." y los ordena de mayor a menor
+func ParseTierCuts(v string) ([]in.TierCut, error) {
+	var cuts []in.TierCut
+	for _, part := range strings.Split(v, ",") {
+		tier, score, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok || strings.TrimSpace(tier) == "" {
+			return nil, fmt.Errorf("invalid tier cut %q", part)
+		}
+		f, err := strconv.ParseFloat(strings.TrimSpace(score), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tier cut %q", part)
+		}
+		cuts = append(cuts, in.TierCut{Tier: strings.TrimSpace(tier), MinScore: f})
+	}
+	if len(cuts) == 0 {
+		return nil, errors.New("no tier cuts")
+	}
+	sort.SliceStable(cuts, func(i, j int) bool { return cuts[i].MinScore > cuts[j].MinScore })
+	return cuts, nil
+}
+
+// TierListService calcula tier lists por rol a partir de las tasas medidas
+type TierListService struct {
+	repo out.Repository
+	cfg  TierListConfig
+}
+
+// NewTierListService crea un nuevo servicio de tier list
+func NewTierListService(repo out.Repository, cfg TierListConfig) *TierListService {
+	return &TierListService{repo: repo, cfg: cfg}
+}
+
+// tierSample partidas de un campeón (o agente) en un rol
+type tierSample struct {
+	id          int
+	name, role  string
+	games, wins int
+	presence    int // partidas en las que se eligió en el rol
+	bans        int // partidas en las que se baneó (todo el campeón)
+}
+
+// tierData muestras de una fuente con el total de partidas analizadas
+type tierData struct {
+	samples []*tierSample
+	matches int
+}
+
+// Build calcula el tier list de q
+func (s *TierListService) Build(ctx context.Context, q in.TierListQuery) (*in.TierList, error) {
+	if q.Game != "lol" && q.Game != "val" {
+		return nil, errors.New("game must be lol or val")
+	}
+	role := ""
+	if q.Role != "" {
+		if role = normalizeTierRole(q.Game, q.Role); role == "" {
+			return nil, fmt.Errorf("unknown role: %s", q.Role)
+		}
+	}
+
+	result := &in.TierList{Game: q.Game, Roles: []in.TierRole{}, Methodology: s.methodology()}
+	var data *tierData
+	var err error
+	if q.League != "" {
+		result.Source, result.League = TierSourcePro, q.League
+		data, result.Season, err = s.proSamples(ctx, q.Game, q.League, q.Season)
+	} else {
+		result.Source, result.Platform = TierSourceRanked, q.Platform
+		data, result.Patch, err = s.rankedSamples(ctx, q.Game, q.Patch, q.Platform)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.Matches = data.matches
+	result.Roles = s.rank(q.Game, data, role)
+	return result, nil
+}
+
+// ByChampionID entrada principal (el rol con más partidas) de cada campeón de un tier list
+func ByChampionID(list *in.TierList) map[int]in.TierEntry {
+	best := make(map[int]in.TierEntry)
+	for _, r := range list.Roles {
+		for _, e := range r.Entries {
+			if cur, ok := best[e.ChampionID]; !ok || e.Games > cur.Games {
+				best[e.ChampionID] = e
+			}
+		}
+	}
+	return best
+}
+
+func (s *TierListService) methodology() in.TierMethodology {
+	return in.TierMethodology{
+		Summary: "Per role, the win rate is smoothed toward the role's win rate with prior_games virtual games. " +
+			"Smoothed win rate, pick rate and ban rate are standardized (z-score) among the role's champions with at least " +
+			"min_games games and min_role_share % of their games in the role. The score is the weighted sum of the z-scores " +
+			"and each tier starts at its min_score; lower scores are D.",
+		Weights:      tierWeights,
+		Cuts:         s.cfg.Cuts,
+		MinGames:     s.cfg.MinGames,
+		MinRoleShare: s.cfg.MinRoleShare,
+		PriorGames:   s.cfg.PriorGames,
+	}
+}
+
+// rankedSamples lee las partidas ranked ingeridas (LoL: Ranked Solo) de un parche
+func (s *TierListService) rankedSamples(ctx context.Context, game, patch, platform string) (*tierData, string, error) {
+	if patch == "" {
+		patches, err := s.repo.RiotMatchPatches(ctx, game, platform)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(patches) == 0 {
+			return &tierData{}, "", nil
+		}
+		version.Sort(patches)
+		patch = patches[len(patches)-1]
+	}
+	matches, err := s.repo.RiotMatchesByPatch(ctx, game, patch)
+	if err != nil {
+		return nil, "", fmt.Errorf("error loading matches: %w", err)
+	}
+
+	data := &tierData{}
+	samples := make(map[string]*tierSample)
+	sample := func(id int, name, role string) *tierSample {
+		key := fmt.Sprintf("%d/%s/%s", id, strings.ToLower(name), role)
+		t := samples[key]
+		if t == nil {
+			t = &tierSample{id: id, name: name, role: role}
+			samples[key] = t
+			data.samples = append(data.samples, t)
+		}
+		return t
+	}
+
+	names := make(map[int]string)
+	bans := make(map[int]int)
+	for _, m := range matches {
+		if platform != "" && !strings.EqualFold(m.Platform, platform) {
+			continue
+		}
+		picked := make(map[*tierSample]bool)
+		switch game {
+		case "lol":
+			if m.QueueID != riot.QueueIDRankedSolo {
+				continue
+			}
+			var summary entities.MatchSummary
+			if err := json.Unmarshal(m.Summary, &summary); err != nil {
+				continue
+			}
+			banned := make(map[int]bool)
+			for _, team := range summary.Teams {
+				for _, p := range team.Picks {
+					names[p.ChampionID] = p.ChampionName
+					role := roleFromPosition(p.Role)
+					if role == "" {
+						continue
+					}
+					addTierPick(sample(p.ChampionID, p.ChampionName, role), team.Win, picked)
+				}
+				for _, id := range team.Bans {
+					if id > 0 && !banned[id] {
+						banned[id] = true
+						bans[id]++
+					}
+				}
+			}
+		case "val":
+			var summary entities.VALMatchSummary
+			if err := json.Unmarshal(m.Summary, &summary); err != nil {
+				continue
+			}
+			for _, team := range summary.Teams {
+				for _, agent := range team.Agents {
+					role := titleRole(valAgentRoles[agent])
+					if role == "" {
+						role = "Flex"
+					}
+					addTierPick(sample(0, agent, role), team.Win, picked)
+				}
+			}
+		}
+		data.matches++
+	}
+
+	for _, t := range data.samples {
+		t.bans = bans[t.id]
+		if t.name == "" {
+			t.name = names[t.id]
+		}
+	}
+	return data, patch, nil
+}
+
+// addTierPick suma un pick (la presencia cuenta una vez por partida)
+func addTierPick(t *tierSample, win bool, picked map[*tierSample]bool) {
+	t.games++
+	if win {
+		t.wins++
+	}
+	if !picked[t] {
+		picked[t] = true
+		t.presence++
+	}
+}
+
+// proSamples lee las estadísticas de una liga profesional (season "" = la más reciente)
+func (s *TierListService) proSamples(ctx context.Context, game, league, season string) (*tierData, string, error) {
+	if season == "" {
+		seasons, err := s.repo.LeagueSeasons(ctx, league)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(seasons) == 0 {
+			return &tierData{}, "", nil
+		}
+		season = seasons[0].Season
+	}
+	rows, err := s.repo.LeagueChampionStats(ctx, league, season)
+	if err != nil {
+		return nil, "", err
+	}
+
+	data := &tierData{}
+	for _, row := range rows {
+		if row.GamesAnalyzed > data.matches {
+			data.matches = row.GamesAnalyzed
+		}
+		role := normalizeTierRole(game, row.Position)
+		if role == "" {
+			continue
+		}
+		games := int(math.Round(row.PickRate * float64(row.GamesAnalyzed) / 100))
+		data.samples = append(data.samples, &tierSample{
+			name: row.ChampionName, role: role, games: games, presence: games,
+			wins: int(math.Round(row.WinRate * float64(games) / 100)),
+			bans: int(math.Round(row.BanRate * float64(row.GamesAnalyzed) / 100)),
+		})
+	}
+	return data, season, nil
+}
+
+// rank calcula scores y tiers por rol (role "" = todos)
+func (s *TierListService) rank(game string, data *tierData, role string) []in.TierRole {
+	championGames := make(map[string]int)
+	byRole := make(map[string][]*tierSample)
+	for _, t := range data.samples {
+		championGames[tierChampionKey(t)] += t.games
+		byRole[t.role] = append(byRole[t.role], t)
+	}
+
+	roles := make([]in.TierRole, 0, len(byRole))
+	for _, name := range tierRoleOrder(game, byRole) {
+		if role != "" && name != role {
+			continue
+		}
+		samples := byRole[name]
+		games, wins := 0, 0
+		for _, t := range samples {
+			games += t.games
+			wins += t.wins
+		}
+		if games == 0 {
+			continue
+		}
+		baseline := 100 * float64(wins) / float64(games)
+
+		entries := make([]in.TierEntry, 0, len(samples))
+		for _, t := range samples {
+			share := 100 * float64(t.games) / float64(championGames[tierChampionKey(t)])
+			if t.games < s.cfg.MinGames || share < s.cfg.MinRoleShare {
+				continue
+			}
+			adjusted := (float64(t.wins) + float64(s.cfg.PriorGames)*baseline/100) / float64(t.games+s.cfg.PriorGames) * 100
+			entry := in.TierEntry{
+				ChampionID: t.id, Name: t.name, Role: name, Games: t.games,
+				RoleShare:       stats.Round(share, 2),
+				WinRate:         stats.Round(100*float64(t.wins)/float64(t.games), 2),
+				AdjustedWinRate: stats.Round(adjusted, 2),
+			}
+			if data.matches > 0 {
+				entry.PickRate = stats.Round(100*float64(t.presence)/float64(data.matches), 2)
+				entry.BanRate = stats.Round(100*float64(t.bans)/float64(data.matches), 2)
+			}
+			entries = append(entries, entry)
+		}
+
+		winZ := zScores(entries, func(e in.TierEntry) float64 { return e.AdjustedWinRate })
+		pickZ := zScores(entries, func(e in.TierEntry) float64 { return e.PickRate })
+		banZ := zScores(entries, func(e in.TierEntry) float64 { return e.BanRate })
+		for i := range entries {
+			score := tierWeights["win_rate"]*winZ[i] + tierWeights["pick_rate"]*pickZ[i] + tierWeights["ban_rate"]*banZ[i]
+			entries[i].Score = stats.Round(score, 3)
+			entries[i].Tier = s.tierFor(score)
+		}
+		sort.SliceStable(entries, func(i, j int) bool {
+			if entries[i].Score != entries[j].Score {
+				return entries[i].Score > entries[j].Score
+			}
+			return entries[i].Name < entries[j].Name
+		})
+		roles = append(roles, in.TierRole{Role: name, BaselineWinRate: stats.Round(baseline, 2), Entries: entries})
+	}
+	return roles
+}
+
+// tierFor tier del primer corte que alcanza el score
+func (s *TierListService) tierFor(score float64) string {
+	for _, c := range s.cfg.Cuts {
+		if score >= c.MinScore {
+			return c.Tier
+		}
+	}
+	return "D"
+}
+
+// zScores estandariza un valor entre las entradas (0 si no hay dispersión)
+func zScores(entries []in.TierEntry, value func(in.TierEntry) float64) []float64 {
+	z := make([]float64, len(entries))
+	if len(entries) < 2 {
+		return z
+	}
+	mean := 0.0
+	for _, e := range entries {
+		mean += value(e)
+	}
+	mean /= float64(len(entries))
+	variance := 0.0
+	for _, e := range entries {
+		d := value(e) - mean
+		variance += d * d
+	}
+	std := math.Sqrt(variance / float64(len(entries)))
+	if std == 0 {
+		return z
+	}
+	for i, e := range entries {
+		z[i] = (value(e) - mean) / std
+	}
+	return z
+}
+
+func tierChampionKey(t *tierSample) string {
+	return fmt.Sprintf("%d/%s", t.id, strings.ToLower(t.name))
+}
+
+// tierRoleOrder roles conocidos en su orden y luego el resto alfabéticamente
+func tierRoleOrder(game string, byRole map[string][]*tierSample) []string {
+	known := lolTierRoles
+	if game == "val" {
+		known = nil
+		for _, r := range valRoleOrder {
+			known = append(known, titleRole(r))
+		}
+	}
+	var order []string
+	seen := make(map[string]bool)
+	for _, r := range known {
+		if _, ok := byRole[r]; ok {
+			order = append(order, r)
+			seen[r] = true
+		}
+	}
+	var rest []string
+	for r := range byRole {
+		if !seen[r] {
+			rest = append(rest, r)
+		}
+	}
+	sort.Strings(rest)
+	return append(order, rest...)
+}
+
+// normalizeTierRole lleva un rol a su nombre en el tier list ("" si no es de LoL)
+func normalizeTierRole(game, role string) string {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if game == "lol" {
+		return lolTierRoleAliases[role]
+	}
+	return titleRole(role)
+}
+
+// titleRole "duelist" -> "Duelist"
+func titleRole(role string) string {
+	if role == "" {
+		return ""
+	}
+	return strings.ToUpper(role[:1]) + role[1:]
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	riot "github.com/steven230500/hypeatlas-api/providers/riot"
+)
+
+// Lados de Valorant en app.comps ("" = partida completa)
+const (
+	SideAttack  = "attack"
+	SideDefense = "defense"
+)
+
+// valRoleOrder orden canónico de los slots; lo que no entra en un rol fijo va a "flex"
+var valRoleOrder = []string{"smokes", "initiator", "duelist", "sentinel"}
+
+// valAgentRoles rol de cada agente (VAL-CONTENT no trae roles)
+var valAgentRoles = map[string]string{
+	"astra": "smokes", "brimstone": "smokes", "clove": "smokes", "harbor": "smokes", "omen": "smokes", "viper": "smokes",
+	"breach": "initiator", "fade": "initiator", "gekko": "initiator", "kayo": "initiator", "skye": "initiator", "sova": "initiator", "tejo": "initiator",
+	"iso": "duelist", "jett": "duelist", "neon": "duelist", "phoenix": "duelist", "raze": "duelist", "reyna": "duelist", "waylay": "duelist", "yoru": "duelist",
+	"chamber": "sentinel", "cypher": "sentinel", "deadlock": "sentinel", "killjoy": "sentinel", "sage": "sentinel", "vyse": "sentinel",
+}
+
+// ValCompService agrega composiciones de Valorant a partir de partidas y las guarda en app.comps
+type ValCompService struct {
+	repo    out.Repository
+	riotSvc *riot.Service
+}
+
+// NewValCompService crea un nuevo servicio de composiciones de Valorant
+func NewValCompService(repo out.Repository, riotSvc *riot.Service) *ValCompService {
+	return &ValCompService{repo: repo, riotSvc: riotSvc}
+}
+
+// ValIngestOptions configura una corrida de ingesta de partidas de Valorant
+type ValIngestOptions struct {
+	Queue      string // competitive, premier, ...
+	League     string // liga con la que se guardan las comps (default "Ranked")
+	MaxMatches int    // tope de partidas nuevas a descargar por corrida
+}
+
+// ValIngestResult resumen de una ingesta de partidas de Valorant
+type ValIngestResult struct {
+	Shard          string   `json:"shard"`
+	MatchIDs       int      `json:"match_ids"`
+	NewMatches     int      `json:"new_matches"`
+	SkippedMatches int      `json:"skipped_matches"`
+	FailedMatches  int      `json:"failed_matches"`
+	Patches        []string `json:"patches"`
+	Comps          int      `json:"comps"`
+}
+
+// IngestShard descarga las partidas recientes de una cola, registra las nuevas y recalcula las comps
+func (s *ValCompService) IngestShard(ctx context.Context, shard string, opts ValIngestOptions) (*ValIngestResult, error) {
+	if !riot.IsVALShard(shard) {
+		return nil, fmt.Errorf("unsupported shard: %s", shard)
+	}
+	if opts.Queue == "" {
+		opts.Queue = "competitive"
+	}
+	if opts.League == "" {
+		opts.League = "Ranked"
+	}
+	if opts.MaxMatches <= 0 {
+		opts.MaxMatches = 50
+	}
+
+	recent, err := s.riotSvc.GetVALRecentMatches(ctx, shard, opts.Queue)
+	if err != nil {
+		return nil, fmt.Errorf("error getting recent matches: %w", err)
+	}
+	content, err := s.riotSvc.GetVALContent(ctx, shard, "")
+	if err != nil {
+		return nil, fmt.Errorf("error getting content: %w", err)
+	}
+
+	result := &ValIngestResult{Shard: shard, MatchIDs: len(recent.MatchIDs)}
+	known, err := s.repo.KnownRiotMatches(ctx, recent.MatchIDs)
+	if err != nil {
+		return nil, fmt.Errorf("error checking known matches: %w", err)
+	}
+
+	touched := make(map[string]bool)
+	for _, id := range recent.MatchIDs {
+		if known[id] {
+			result.SkippedMatches++
+			continue
+		}
+		if result.NewMatches+result.FailedMatches >= opts.MaxMatches {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		match, err := s.riotSvc.GetVALMatch(ctx, shard, id)
+		if err != nil {
+			result.FailedMatches++
+			continue
+		}
+		patch, inserted, err := s.registerMatch(ctx, shard, opts.League, match, content)
+		if err != nil {
+			result.FailedMatches++
+			continue
+		}
+		if !inserted {
+			result.SkippedMatches++
+			continue
+		}
+		result.NewMatches++
+		touched[patch] = true
+	}
+
+	if err := s.rebuildTouched(ctx, touched, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// IngestMatches registra partidas subidas a mano (p.ej. un archivo de un torneo) con la
+// liga indicada y recalcula las comps de los parches afectados
+func (s *ValCompService) IngestMatches(ctx context.Context, shard, league string, matches []riot.VALMatch) (*ValIngestResult, error) {
+	if !riot.IsVALShard(shard) {
+		return nil, fmt.Errorf("unsupported shard: %s", shard)
+	}
+	if league == "" {
+		return nil, fmt.Errorf("league is required")
+	}
+	content, err := s.riotSvc.GetVALContent(ctx, shard, "")
+	if err != nil {
+		return nil, fmt.Errorf("error getting content: %w", err)
+	}
+
+	result := &ValIngestResult{Shard: shard, MatchIDs: len(matches)}
+	touched := make(map[string]bool)
+	for i := range matches {
+		patch, inserted, err := s.registerMatch(ctx, shard, league, &matches[i], content)
+		if err != nil {
+			result.FailedMatches++
+			continue
+		}
+		if !inserted {
+			result.SkippedMatches++
+			continue
+		}
+		result.NewMatches++
+		touched[patch] = true
+	}
+
+	if err := s.rebuildTouched(ctx, touched, result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (s *ValCompService) rebuildTouched(ctx context.Context, touched map[string]bool, result *ValIngestResult) error {
+	for patch := range touched {
+		n, err := s.RebuildPatch(ctx, patch)
+		if err != nil {
+			return fmt.Errorf("error rebuilding comps for patch %s: %w", patch, err)
+		}
+		result.Patches = append(result.Patches, patch)
+		result.Comps += n
+	}
+	sort.Strings(result.Patches)
+	return nil
+}
+
+// registerMatch resume la partida y la registra en app.riot_matches; devuelve el parche y si era nueva
+func (s *ValCompService) registerMatch(ctx context.Context, shard, league string, match *riot.VALMatch, content *riot.VALContentResponse) (string, bool, error) {
+	if match.MatchInfo.MatchID == "" {
+		return "", false, fmt.Errorf("match without matchId")
+	}
+	patch := riot.VALPatchFromGameVersion(match.MatchInfo.GameVersion)
+	if patch == "" {
+		return "", false, fmt.Errorf("match %s without gameVersion", match.MatchInfo.MatchID)
+	}
+	summary, err := BuildVALMatchSummary(match, content, league)
+	if err != nil {
+		return patch, false, err
+	}
+
+	raw, err := json.Marshal(summary)
+	if err != nil {
+		return patch, false, err
+	}
+	inserted, err := s.repo.RegisterRiotMatch(ctx, &entities.RiotMatch{
+		MatchID:      match.MatchInfo.MatchID,
+		Game:         "val",
+		Platform:     shard,
+		Patch:        patch,
+		GameCreation: time.UnixMilli(match.MatchInfo.GameStartMillis).UTC(),
+		Summary:      raw,
+	})
+	return patch, inserted, err
+}
+
+// BuildVALMatchSummary reduce una partida de VAL-MATCH-V1 a agentes y rondas por lado de cada equipo
+func BuildVALMatchSummary(match *riot.VALMatch, content *riot.VALContentResponse, league string) (entities.VALMatchSummary, error) {
+	summary := entities.VALMatchSummary{
+		Map:    content.MapName(match.MatchInfo.MapID),
+		Queue:  match.MatchInfo.QueueID,
+		League: league,
+	}
+	if summary.Map == "" {
+		return summary, fmt.Errorf("unknown map: %s", match.MatchInfo.MapID)
+	}
+
+	for _, team := range match.Teams {
+		ts := entities.VALTeamSummary{TeamID: team.TeamID, Win: team.Won}
+		for _, p := range match.Players {
+			if p.TeamID != team.TeamID {
+				continue
+			}
+			name := content.AgentName(p.CharacterID)
+			if name == "" {
+				return summary, fmt.Errorf("unknown agent: %s", p.CharacterID)
+			}
+			ts.Agents = append(ts.Agents, NormalizeAgent(name))
+		}
+		if len(ts.Agents) != 5 {
+			return summary, fmt.Errorf("team %s has %d agents", team.TeamID, len(ts.Agents))
+		}
+		for _, round := range match.RoundResults {
+			won := round.WinningTeam == team.TeamID
+			if attackingTeam(round.RoundNum) == team.TeamID {
+				ts.AttackRounds++
+				if won {
+					ts.AttackWins++
+				}
+			} else {
+				ts.DefenseRounds++
+				if won {
+					ts.DefenseWins++
+				}
+			}
+		}
+		summary.Teams = append(summary.Teams, ts)
+	}
+	if len(summary.Teams) != 2 {
+		return summary, fmt.Errorf("match has %d teams", len(summary.Teams))
+	}
+	return summary, nil
+}
+
+// attackingTeam equipo atacante de una ronda (0-based): Red ataca la primera mitad,
+// Blue la segunda y en prórroga se alterna cada ronda empezando por Red
+func attackingTeam(roundNum int) string {
+	switch {
+	case roundNum < 12:
+		return "Red"
+	case roundNum < 24:
+		return "Blue"
+	case (roundNum-24)%2 == 0:
+		return "Red"
+	default:
+		return "Blue"
+	}
+}
+
+// NormalizeAgent lleva el nombre de un agente a la forma de los slots (KAY/O -> kayo)
+func NormalizeAgent(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// CanonicalVALSlots ordena cinco agentes en slots canónicos: un agente por rol fijo
+// (smokes, initiator, duelist, sentinel; el primero alfabéticamente si hay varios) y
+// el resto como flex en orden alfabético. Así la misma comp siempre da el mismo slots_fp.
+func CanonicalVALSlots(agents []string) entities.CompSlots {
+	pool := make([]string, len(agents))
+	copy(pool, agents)
+	sort.Strings(pool)
+
+	used := make([]bool, len(pool))
+	slots := entities.CompSlots{Shape: entities.CompShapeFull}
+	for _, role := range valRoleOrder {
+		for i, agent := range pool {
+			if !used[i] && valAgentRoles[agent] == role {
+				used[i] = true
+				slots.Roles = append(slots.Roles, role)
+				slots.Members = append(slots.Members, entities.CompSlotMember{Agent: agent})
+				break
+			}
+		}
+	}
+	for i, agent := range pool {
+		if !used[i] {
+			slots.Roles = append(slots.Roles, "flex")
+			slots.Members = append(slots.Members, entities.CompSlotMember{Agent: agent})
+		}
+	}
+	return slots
+}
+
+// RebuildPatch recalcula todas las comps de Valorant de un parche a partir de las partidas
+// registradas y las sube con UpsertComp (ver compAggregator). El win rate de side "" es por
+// partidas; el de attack/defense, por rondas jugadas en ese lado. Devuelve cuántas filas escribió.
+func (s *ValCompService) RebuildPatch(ctx context.Context, patch string) (int, error) {
+	matches, err := s.repo.RiotMatchesByPatch(ctx, "val", patch)
+	if err != nil {
+		return 0, fmt.Errorf("error loading matches: %w", err)
+	}
+
+	agg := newCompAggregator()
+	for _, m := range matches {
+		var summary entities.VALMatchSummary
+		if err := json.Unmarshal(m.Summary, &summary); err != nil {
+			continue
+		}
+		region := riot.VALShardRegions[m.Platform]
+		if region == "" {
+			region = strings.ToUpper(m.Platform)
+		}
+		for _, team := range summary.Teams {
+			slots, err := slotsJSON(CanonicalVALSlots(team.Agents))
+			if err != nil {
+				return 0, err
+			}
+			key := compKey{Region: region, League: summary.League, Map: summary.Map, Shape: entities.CompShapeFull}
+			agg.pick(key, slots)
+
+			win := 0
+			if team.Win {
+				win = 1
+			}
+			agg.result(key, slots, 1, win)
+			attack, defense := key, key
+			attack.Side, defense.Side = SideAttack, SideDefense
+			agg.result(attack, slots, team.AttackRounds, team.AttackWins)
+			agg.result(defense, slots, team.DefenseRounds, team.DefenseWins)
+		}
+	}
+
+	written := 0
+	for _, comp := range agg.rows("val", patch) {
+		if err := s.repo.UpsertComp(ctx, &comp); err != nil {
+			return written, fmt.Errorf("error upserting comp: %w", err)
+		}
+		written++
+	}
+	return written, nil
+}
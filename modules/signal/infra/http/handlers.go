@@ -2,11 +2,16 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	"github.com/steven230500/hypeatlas-api/modules/signal/domain/service"
 
 	"github.com/steven230500/hypeatlas-api/domain/entities"
 )
@@ -18,8 +23,14 @@ func New(s in.Service) *Handler { return &Handler{svc: s} }
 func (h *Handler) Register(r chi.Router) {
 	r.Get("/patches", h.patches)
 	r.Get("/changes", h.changes)
+	r.Get("/changes/impact", h.changesImpact)
+	r.Get("/changes/range", h.changesRange)
 	r.Get("/leagues", h.leagues)
 	r.Get("/comps", h.comps)
+	r.Get("/comps/movers", h.compMovers)
+	r.Get("/comps/{uuid}/history", h.compHistory)
+	r.Get("/synergy", h.synergy)
+	r.Get("/tierlist", h.tierList)
 }
 
 // ====== Wrappers de respuesta para Swagger ======
@@ -74,6 +85,51 @@ func (h *Handler) changes(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(map[string]any{"items": items})
 }
 
+// @Summary Efecto neto de los cambios entre dos versiones
+// @Description Pliega los cambios de los parches (from, to] en un old→new neto por campo, con los parches que contribuyen. Las versiones se ordenan semánticamente (14.9 < 14.10).
+// @Tags signal
+// @Param game   query string true  "lol | val" enums(lol,val)
+// @Param from   query string true  "Versión base (excluida). Ej: 14.10"
+// @Param to     query string true  "Versión final (incluida). Ej: 14.14"
+// @Param entity query string false "entity_id (sin distinguir mayúsculas). Ej: Ahri | sova"
+// @Param type   query string false "agent|champion|item|weapon|map" enums(agent,champion,item,weapon,map)
+// @Produce json
+// @Success 200 {object} in.ChangeRange
+// @Failure 400 {string} string "game, from and to required"
+// @Router /v1/signal/changes/range [get]
+func (h *Handler) changesRange(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	result, err := h.svc.ChangesRange(r.Context(), q.Get("game"), q.Get("from"), q.Get("to"), q.Get("type"), q.Get("entity"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(result)
+}
+
+// @Summary Impacto de los cambios de un parche
+// @Description Ranking por tipo de entidad de los cambios y entidades más impactantes (impact_score 0-10)
+// @Tags signal
+// @Param game    query string true  "lol | val" enums(lol,val)
+// @Param version query string true  "Ej: 14.14 | 9.15"
+// @Param top     query int    false "Elementos por tipo" minimum(1) default(5)
+// @Produce json
+// @Success 200 {object} in.ImpactSummary
+// @Failure 400 {string} string "game and version required"
+// @Router /v1/signal/changes/impact [get]
+func (h *Handler) changesImpact(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	top, _ := strconv.Atoi(q.Get("top"))
+	summary, err := h.svc.ImpactSummary(r.Context(), q.Get("game"), q.Get("version"), top)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(summary)
+}
+
 // @Summary Listar ligas por juego y región
 // @Tags signal
 // @Param game   query string true  "lol | val" enums(lol,val)
@@ -101,7 +157,13 @@ func (h *Handler) leagues(w http.ResponseWriter, r *http.Request) {
 // @Param patch  query string true  "14.14 | 9.15"
 // @Param map    query string false "Ascent (solo VAL)"
 // @Param side   query string false "LoL: blue/red | VAL: attack/defense" enums(blue,red,attack,defense)
+// @Param shape  query string false "full | bot_duo | jungle_mid (parciales solo LoL)" enums(full,bot_duo,jungle_mid)
+// @Param includes query string false "Agentes/campeones que debe contener (coma-separado). Ej: jett,viper"
+// @Param excludes query string false "Agentes/campeones que no debe contener (coma-separado)"
+// @Param min_games query int false "Mínimo de partidas de la comp" minimum(0)
+// @Param sort   query string false "wilson (límite inferior del IC 95%) | win_rate | pick_rate | delta" enums(wilson,win_rate,pick_rate,delta) default(wilson)
 // @Param limit  query int    false "1-100" minimum(1) maximum(200) default(50)
+// @Description Cada comp incluye games y el intervalo de Wilson al 95% del win rate (win_ci_low/win_ci_high)
 // @Produce json
 // @Success 200 {object} CompsResp
 // @Failure 400 {string} string "game, region and patch required"
@@ -109,10 +171,21 @@ func (h *Handler) leagues(w http.ResponseWriter, r *http.Request) {
 func (h *Handler) comps(w http.ResponseWriter, r *http.Request) {
 	q := r.URL.Query()
 	limit, _ := strconv.Atoi(q.Get("limit"))
-	items, err := h.svc.ListComps(
-		r.Context(), q.Get("game"), q.Get("region"), q.Get("league"),
-		q.Get("patch"), q.Get("map"), q.Get("side"), limit,
-	)
+	minGames := 0
+	if v := q.Get("min_games"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			http.Error(w, "min_games must be an integer", http.StatusBadRequest)
+			return
+		}
+		minGames = n
+	}
+	items, err := h.svc.ListComps(r.Context(), out.CompQuery{
+		Game: q.Get("game"), Region: q.Get("region"), League: q.Get("league"),
+		Patch: q.Get("patch"), Map: q.Get("map"), Side: q.Get("side"), Shape: q.Get("shape"),
+		Includes: splitList(q.Get("includes")), Excludes: splitList(q.Get("excludes")),
+		MinGames: minGames, Sort: q.Get("sort"), Limit: limit,
+	})
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -120,3 +193,145 @@ func (h *Handler) comps(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]any{"items": items})
 }
+
+// @Summary Sinergias de un agente/campeón
+// @Description Co-ocurrencia y lift de win rate de cada compañero de with, calculados sobre las comps completas guardadas (ambos lados)
+// @Tags signal
+// @Param game     query string true  "lol | val" enums(lol,val)
+// @Param with     query string true  "Agente o campeón. Ej: sova | Ahri"
+// @Param region   query string false "EMEA"
+// @Param league   query string false "Ranked | VCT EMEA"
+// @Param patch    query string false "14.14 | 9.15"
+// @Param map      query string false "Breeze (solo VAL)"
+// @Param includes query string false "Otros miembros obligatorios (coma-separado)"
+// @Param excludes query string false "Miembros excluidos (coma-separado)"
+// @Param min_games query int  false "Mínimo de partidas juntos" minimum(0)
+// @Produce json
+// @Success 200 {object} in.SynergyReport
+// @Failure 400 {string} string "game and with required"
+// @Router /v1/signal/synergy [get]
+func (h *Handler) synergy(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	minGames, _ := strconv.Atoi(q.Get("min_games"))
+	report, err := h.svc.Synergy(r.Context(), out.CompQuery{
+		Game: q.Get("game"), Region: q.Get("region"), League: q.Get("league"),
+		Patch: q.Get("patch"), Map: q.Get("map"),
+		Includes: splitList(q.Get("includes")), Excludes: splitList(q.Get("excludes")),
+	}, q.Get("with"), minGames)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(report)
+}
+
+// @Summary Tier list por rol
+// @Description Tiers calculados con win rate (suavizado), pick rate y ban rate medidos, estandarizados dentro de cada rol. Sin league usa las partidas ranked ingeridas (LoL: Ranked Solo); con league, las estadísticas de la liga profesional. Incluye la metodología y los cortes usados.
+// @Tags signal
+// @Param game     query string true  "lol | val" enums(lol,val)
+// @Param patch    query string false "14.14 | 9.15 (default: último parche con partidas)"
+// @Param role     query string false "LoL: top|jungle|mid|adc|support; VAL: smokes|initiator|duelist|sentinel"
+// @Param platform query string false "LoL: euw1, kr...; VAL: shard eu, na... (default: todas)"
+// @Param league   query string false "Liga profesional (LEC, LCK...); usa sus estadísticas en lugar de ranked"
+// @Param season   query string false "Temporada de la liga (default: la más reciente)"
+// @Produce json
+// @Success 200 {object} in.TierList
+// @Failure 400 {string} string "game must be lol or val"
+// @Router /v1/signal/tierlist [get]
+func (h *Handler) tierList(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	list, err := h.svc.TierList(r.Context(), in.TierListQuery{
+		Game: q.Get("game"), Patch: q.Get("patch"), Role: q.Get("role"),
+		Platform: q.Get("platform"), League: q.Get("league"), Season: q.Get("season"),
+	})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(list)
+}
+
+// splitList parte un parámetro coma-separado descartando vacíos
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// @Summary Historial de una composición
+// @Description Snapshots de la comp (misma región, liga, mapa, lado y slots) en todos los parches, en orden cronológico
+// @Tags signal
+// @Param uuid  path  string true  "UUID de la comp"
+// @Param limit query int    false "Máximo de snapshots (más recientes)" minimum(1) maximum(500) default(100)
+// @Produce json
+// @Success 200 {object} in.CompHistory
+// @Failure 400 {string} string "invalid uuid"
+// @Failure 404 {string} string "comp not found"
+// @Router /v1/signal/comps/{uuid}/history [get]
+func (h *Handler) compHistory(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		http.Error(w, "invalid uuid", http.StatusBadRequest)
+		return
+	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+	history, err := h.svc.CompHistory(r.Context(), id, limit)
+	if errors.Is(err, service.ErrCompNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(history)
+}
+
+// @Summary Comps que más suben y bajan entre parches
+// @Description Compara el win rate de cada comp entre from y patch y adjunta los cambios de balance de patch que tocan a sus miembros
+// @Tags signal
+// @Param game      query string true  "lol | val" enums(lol,val)
+// @Param patch     query string true  "Parche actual. Ej: 9.15"
+// @Param from      query string false "Parche base (por defecto el anterior)"
+// @Param region    query string false "EMEA"
+// @Param league    query string false "VCT EMEA"
+// @Param map       query string false "Ascent (solo VAL)"
+// @Param side      query string false "vacío = ambos lados (default)" enums(blue,red,attack,defense)
+// @Param shape     query string false "full | bot_duo | jungle_mid" enums(full,bot_duo,jungle_mid)
+// @Param min_games query int    false "Mínimo de partidas en ambos parches" minimum(0) default(10)
+// @Param limit     query int    false "Risers y fallers a devolver" minimum(1) maximum(100) default(10)
+// @Produce json
+// @Success 200 {object} in.CompMovers
+// @Failure 400 {string} string "game and patch required"
+// @Router /v1/signal/comps/movers [get]
+func (h *Handler) compMovers(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	minGames := 10
+	if v := q.Get("min_games"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			http.Error(w, "min_games must be a non-negative integer", http.StatusBadRequest)
+			return
+		}
+		minGames = n
+	}
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	movers, err := h.svc.CompMovers(r.Context(), out.CompQuery{
+		Game: q.Get("game"), Region: q.Get("region"), League: q.Get("league"),
+		Patch: q.Get("patch"), Map: q.Get("map"), Side: q.Get("side"), Shape: q.Get("shape"),
+		MinGames: minGames, Limit: limit,
+	}, q.Get("from"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(movers)
+}
@@ -2,21 +2,55 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
+	"github.com/steven230500/hypeatlas-api/modules/signal/domain/service"
 	signalrepo "github.com/steven230500/hypeatlas-api/modules/signal/infra/repository"
+	"github.com/steven230500/hypeatlas-api/providers/riot"
+	"github.com/steven230500/hypeatlas-api/shared/ingestion"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
 	"gorm.io/gorm"
 )
 
 type IngestHandler struct {
-	db *gorm.DB
+	db         *gorm.DB
+	repo       out.Repository
+	valComps   *service.ValCompService // nil sin RIOT_API_KEY
+	impact     *service.ImpactService
+	proLeagues *service.ProLeagueService
 }
 
-func NewIngest(db *gorm.DB) *IngestHandler { return &IngestHandler{db: db} }
+func NewIngest(db *gorm.DB, valComps *service.ValCompService, impact *service.ImpactService, proLeagues *service.ProLeagueService) *IngestHandler {
+	return &IngestHandler{db: db, repo: signalrepo.New(db), valComps: valComps, impact: impact, proLeagues: proLeagues}
+}
+
+// NewIngestRouter crea el router de ingesta del módulo signal (se monta en /v1/ingest/signal).
+// riotSvc es el servicio compartido de la API (nil sin RIOT_API_KEY): así la ingesta usa el
+// mismo rate limiter que el resto de rutas.
+func NewIngestRouter(db *gorm.DB, riotSvc *riot.Service) chi.Router {
+	r := chi.NewRouter()
+	repo := signalrepo.New(db)
+	var valComps *service.ValCompService
+	if riotSvc != nil {
+		valComps = service.NewValCompService(repo, riotSvc)
+	}
+	NewIngest(db, valComps, service.NewImpactService(repo), service.NewProLeagueService(repo)).Register(r)
+	return r
+}
 
 func (h *IngestHandler) Register(r chi.Router) {
 	r.Post("/comps:upsert", h.upsertComp)
+	r.Post("/patch-changes:upsert", h.upsertPatchChanges)
+	r.Post("/val/matches:ingest", h.ingestVALMatches)
+	r.Post("/pro-matches:import", h.importProMatches)
 }
 
 type upsertCompReq struct {
@@ -26,7 +60,9 @@ type upsertCompReq struct {
 	Patch  string         `json:"patch"`
 	Map    string         `json:"map"`
 	Side   string         `json:"side"`
+	Shape  string         `json:"shape"` // full|bot_duo|jungle_mid (default full)
 	Slots  map[string]any `json:"slots"`
+	Games  int            `json:"games"` // con win_rate permite calcular el intervalo de Wilson
 	Pick   *float64       `json:"pick_rate"`
 	Win    *float64       `json:"win_rate"`
 	Delta  *float64       `json:"delta_win"`
@@ -48,14 +84,22 @@ func (h *IngestHandler) upsertComp(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "bad json", http.StatusBadRequest)
 		return
 	}
+	ingestion.Describe(r.Context(), entities.IngestionEntityComp, req.Game+":"+req.Patch+":"+req.Shape)
 	raw, _ := json.Marshal(req.Slots)
+	comp := &entities.Comp{
+		Game: req.Game, Region: req.Region, League: req.League, Patch: req.Patch,
+		Map: req.Map, Side: req.Side, Shape: req.Shape, Slots: raw, Games: req.Games,
+		PickRate: req.Pick, WinRate: req.Win, DeltaWin: req.Delta,
+	}
+	if req.Games > 0 && req.Win != nil {
+		wins := int(math.Round(*req.Win * float64(req.Games) / 100))
+		low, high := stats.WilsonInterval(wins, req.Games, stats.Z95)
+		low, high = stats.Round(100*low, 3), stats.Round(100*high, 3)
+		comp.WinCILow, comp.WinCIHigh = &low, &high
+	}
 	repo := signalrepo.New(h.db)
 	if pgRepo, ok := repo.(*signalrepo.Repo); ok {
-		if err := pgRepo.UpsertComp(
-			r.Context(),
-			req.Game, req.Region, req.League, req.Patch, req.Map, req.Side,
-			string(raw), req.Pick, req.Win, req.Delta,
-		); err != nil {
+		if err := pgRepo.UpsertComp(r.Context(), comp); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
 		}
@@ -65,3 +109,164 @@ func (h *IngestHandler) upsertComp(w http.ResponseWriter, r *http.Request) {
 	}
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type ingestVALMatchesReq struct {
+	Shard   string          `json:"shard"`  // na, latam, br, eu, ap, kr
+	League  string          `json:"league"` // VCT EMEA, Challengers, ...
+	Matches []riot.VALMatch `json:"matches"`
+}
+
+// ingestVALMatches godoc
+// @Summary     Ingesta de partidas de Valorant (archivo de partidas)
+// @Description Registra partidas en formato VAL-MATCH-V1 con la liga indicada y recalcula las comps de sus parches
+// @Tags        ingest
+// @Security    ApiKeyAuth
+// @Accept      json
+// @Produce     json
+// @Param       body body   ingestVALMatchesReq true "payload"
+// @Success     200 {object} service.ValIngestResult
+// @Failure     400 {string} string "bad json"
+// @Failure     503 {string} string "riot api not configured"
+// @Failure     500 {string} string "ingest error"
+// @Router      /v1/ingest/signal/val/matches:ingest [post]
+func (h *IngestHandler) ingestVALMatches(w http.ResponseWriter, r *http.Request) {
+	if h.valComps == nil {
+		http.Error(w, "riot api not configured", http.StatusServiceUnavailable)
+		return
+	}
+	var req ingestVALMatchesReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	ingestion.Describe(r.Context(), entities.IngestionEntityMatch, "val:"+req.Shard)
+	if !riot.IsVALShard(req.Shard) {
+		http.Error(w, "shard must be one of na, latam, br, eu, ap, kr", http.StatusBadRequest)
+		return
+	}
+	if req.League == "" || len(req.Matches) == 0 {
+		http.Error(w, "league and matches are required", http.StatusBadRequest)
+		return
+	}
+
+	res, err := h.valComps.IngestMatches(r.Context(), req.Shard, req.League, req.Matches)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+// importProMatches godoc
+// @Summary     Importación de partidas profesionales (CSV o JSON)
+// @Description Guarda partidas profesionales y recalcula pick, ban y win rate por liga y temporada.
+// @Description CSV (Content-Type text/csv, ?game= obligatorio): una fila por equipo con columnas match_id,league,season,patch,played_at,map,team,side,win,picks,bans; picks "rol:campeón|..." y bans "campeón|...".
+// @Description JSON: {"game":"lol","matches":[{"match_id","league","season","patch","played_at","teams":[{"name","side","win","picks":[{"role","champion"}],"bans":[]}]}]}
+// @Tags        ingest
+// @Security    ApiKeyAuth
+// @Accept      json
+// @Accept      text/csv
+// @Produce     json
+// @Param       game query string false "lol | val (obligatorio con CSV; en JSON sobrescribe el del archivo)" enums(lol,val)
+// @Param       body body service.ProMatchFile true "archivo de partidas"
+// @Success     200 {object} service.ProImportResult
+// @Failure     400 {string} string "invalid file"
+// @Failure     500 {string} string "import error"
+// @Router      /v1/ingest/signal/pro-matches:import [post]
+func (h *IngestHandler) importProMatches(w http.ResponseWriter, r *http.Request) {
+	game := r.URL.Query().Get("game")
+	var records []service.ProMatchRecord
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "text/csv") {
+		parsed, err := service.ParseProMatchesCSV(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		records = parsed
+	} else {
+		file, err := service.ParseProMatchesJSON(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if game == "" {
+			game = file.Game
+		}
+		records = file.Matches
+	}
+
+	ingestion.Describe(r.Context(), entities.IngestionEntityProMatch, game)
+	res, err := h.proLeagues.Import(r.Context(), game, records)
+	if errors.Is(err, service.ErrInvalidProImport) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+type upsertPatchChangesReq struct {
+	Game       string     `json:"game"`
+	Version    string     `json:"version"`
+	ReleasedAt *time.Time `json:"released_at"`
+	Changes    []struct {
+		EntityType string `json:"entity_type"` // champion|agent|item|weapon|map
+		EntityID   string `json:"entity_id"`
+		Field      string `json:"field"`
+		Old        string `json:"old"`
+		New        string `json:"new"`
+	} `json:"changes"`
+}
+
+// upsertPatchChanges godoc
+// @Summary     Upsert de cambios de un parche (ingesta)
+// @Description Crea el parche si no existe, crea/actualiza sus cambios y recalcula impact_score
+// @Tags        ingest
+// @Security    ApiKeyAuth
+// @Accept      json
+// @Produce     json
+// @Param       body body   upsertPatchChangesReq true "payload"
+// @Success     200 {object} in.ImpactSummary
+// @Failure     400 {string} string "bad json"
+// @Failure     500 {string} string "db error"
+// @Router      /v1/ingest/signal/patch-changes:upsert [post]
+func (h *IngestHandler) upsertPatchChanges(w http.ResponseWriter, r *http.Request) {
+	var req upsertPatchChangesReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad json", http.StatusBadRequest)
+		return
+	}
+	ingestion.Describe(r.Context(), entities.IngestionEntityPatch, req.Game+":"+req.Version)
+	if req.Game == "" || req.Version == "" {
+		http.Error(w, "game and version required", http.StatusBadRequest)
+		return
+	}
+	changes := make([]entities.PatchChange, 0, len(req.Changes))
+	for _, c := range req.Changes {
+		if c.EntityType == "" || c.EntityID == "" || c.Field == "" {
+			http.Error(w, "entity_type, entity_id and field required", http.StatusBadRequest)
+			return
+		}
+		changes = append(changes, entities.PatchChange{
+			EntityType: c.EntityType, EntityID: c.EntityID, Field: c.Field, Old: c.Old, New: c.New,
+		})
+	}
+
+	if _, err := h.repo.UpsertPatchChanges(r.Context(), req.Game, req.Version, req.ReleasedAt, changes); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	var summary *in.ImpactSummary
+	summary, err := h.impact.ScorePatch(r.Context(), req.Game, req.Version, 0)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(summary)
+}
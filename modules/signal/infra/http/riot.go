@@ -2,40 +2,45 @@ package http
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/steven230500/hypeatlas-api/domain/entities"
 	in "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/in"
+	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
 	"github.com/steven230500/hypeatlas-api/modules/signal/domain/service"
 	"github.com/steven230500/hypeatlas-api/providers/riot"
 )
 
 type RiotHandler struct {
-	riotSvc               *riot.Service
-	sigSvc                in.Service
-	metaGameSvc           *service.MetaGameService
-	championStatsSvc      *riot.ChampionStatsService
-	professionalLeagueSvc *riot.ProfessionalLeagueService
-	dataDragonSvc         *riot.DataDragonService
-	imageSvc              *riot.ImageService
+	riotSvc          *riot.Service
+	sigSvc           in.Service
+	metaGameSvc      *service.MetaGameService
+	masterySvc       *service.MasteryService
+	championStatsSvc *riot.ChampionStatsService
+	proLeagueSvc     *service.ProLeagueService
+	dataDragonSvc    *riot.DataDragonService
+	imageSvc         *riot.ImageService
 }
 
-func NewRiotHandler(riotSvc *riot.Service, sigSvc in.Service, metaGameSvc *service.MetaGameService) *RiotHandler {
-	// Crear cliente temporal para los servicios especializados
-	// En una implementación más robusta, el cliente debería ser inyectado
-	tempClient := riot.NewClient("temp-key") // Esto será reemplazado por el cliente real del servicio
+func NewRiotHandler(riotSvc *riot.Service, sigSvc in.Service, metaGameSvc *service.MetaGameService, masterySvc *service.MasteryService, proLeagueSvc *service.ProLeagueService) *RiotHandler {
+	// Los servicios especializados comparten el cliente del servicio (API key, limiter y base URLs)
+	client := riotSvc.Client()
 
 	return &RiotHandler{
-		riotSvc:               riotSvc,
-		sigSvc:                sigSvc,
-		metaGameSvc:           metaGameSvc,
-		championStatsSvc:      riot.NewChampionStatsService(tempClient),
-		professionalLeagueSvc: riot.NewProfessionalLeagueService(tempClient),
-		dataDragonSvc:         riot.NewDataDragonService(tempClient),
-		imageSvc:              riot.NewImageService(tempClient),
+		riotSvc:          riotSvc,
+		sigSvc:           sigSvc,
+		metaGameSvc:      metaGameSvc,
+		masterySvc:       masterySvc,
+		championStatsSvc: riot.NewChampionStatsService(client),
+		proLeagueSvc:     proLeagueSvc,
+		dataDragonSvc:    riot.NewDataDragonService(client),
+		imageSvc:         riot.NewImageService(client),
 	}
 }
 
@@ -44,8 +49,17 @@ func (h *RiotHandler) Register(r chi.Router) {
 		r.Post("/sync/patches", h.syncPatches)
 		r.Get("/patches/{version}", h.getPatchInfo)
 		r.Get("/metagame/rotation/{platform}", h.analyzeChampionRotation)
+		r.Get("/metagame/rotation/{platform}/history", h.getRotationHistory)
+		r.Get("/metagame/rotation/{platform}/frequency", h.getRotationFrequency)
+		r.Get("/metagame/rotation/{platform}/forecast", h.getRotationForecast)
 		r.Get("/metagame/league/{platform}/{queue}", h.analyzeLeagueRankings)
+		r.Get("/metagame/league/{platform}/{queue}/history", h.getLeagueHistory)
+		r.Get("/metagame/ladder/{platform}/{queue}", h.analyzeLadder)
 		r.Get("/metagame/report/{platform}", h.generateMetaReport)
+		r.Get("/metagame/report/{platform}/history", h.getMetaReportHistory)
+		r.Get("/metagame/cross-region", h.getCrossRegionReport)
+		r.Get("/metagame/cross-region/history", h.getCrossRegionHistory)
+		r.Get("/mastery/{platform}", h.getChampionMastery)
 		r.Get("/games", h.getGames)
 		r.Get("/leagues/{platform}", h.getLeagues)
 		r.Get("/regions", h.getRegions)
@@ -72,6 +86,9 @@ func (h *RiotHandler) Register(r chi.Router) {
 		r.Get("/images/maps/{version}/{mapID}", h.getMapImage)
 		r.Get("/images/abilities/{version}/{abilityName}", h.getAbilityImage)
 		r.Get("/images/passives/{version}/{passiveFile}", h.getPassiveImage)
+
+		// Valorant endpoints
+		h.registerVAL(r)
 	})
 }
 
@@ -104,6 +121,7 @@ func (h *RiotHandler) syncPatches(w http.ResponseWriter, r *http.Request) {
 // @Accept json
 // @Produce json
 // @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param fresh query bool false "Force a live analysis instead of the latest stored snapshot"
 // @Success 200 {object} map[string]interface{} "Analysis result with champion data and recommendations"
 // @Failure 400 {string} string "Platform parameter is required"
 // @Failure 500 {string} string "Internal server error"
@@ -114,13 +132,89 @@ func (h *RiotHandler) analyzeChampionRotation(w http.ResponseWriter, r *http.Req
 		http.Error(w, "Platform parameter is required", http.StatusBadRequest)
 		return
 	}
+	if h.writeStoredAnalysis(w, r, platform, service.AnalysisTypeRotation, map[string]any{"platform": platform}, "analysis") {
+		return
+	}
 	analysis, err := h.metaGameSvc.AnalyzeChampionRotation(r.Context(), platform)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error analyzing champion rotation: %v", err), http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "analysis": analysis})
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "source": "live", "generated_at": time.Now().UTC(), "analysis": analysis})
+}
+
+// @Summary Champion rotation history
+// @Description List the stored free champion rotations of a platform, newest first
+// @Tags riot
+// @Produce json
+// @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param limit query int false "Max rows (default 20, max 200)"
+// @Success 200 {object} map[string]interface{} "Stored rotations"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/metagame/rotation/{platform}/history [get]
+func (h *RiotHandler) getRotationHistory(w http.ResponseWriter, r *http.Request) {
+	platform := chi.URLParam(r, "platform")
+	rotations, err := h.metaGameSvc.RotationHistory(r.Context(), platform, historyLimit(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting rotation history: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "rotations": rotations})
+}
+
+// @Summary Champion rotation frequency
+// @Description How often each champion was free in the stored rotations, weeks since its last free week, average weeks between free weeks and the pick-rate effect of its free weeks (from ingested Ranked Solo matches)
+// @Tags riot
+// @Produce json
+// @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param weeks query int false "Stored rotations to analyze (default 52, max 520)"
+// @Success 200 {object} service.RotationFrequency "Rotation frequency"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/metagame/rotation/{platform}/frequency [get]
+func (h *RiotHandler) getRotationFrequency(w http.ResponseWriter, r *http.Request) {
+	platform := chi.URLParam(r, "platform")
+	freq, err := h.metaGameSvc.RotationFrequency(r.Context(), platform, rotationWeeks(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting rotation frequency: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "frequency": freq})
+}
+
+// @Summary Champion rotation forecast
+// @Description Champions that are not free now and have gone longer than their usual interval between free weeks, ranked by forecast score, with the champions never free in the period and the average free-week pick-rate lift
+// @Tags riot
+// @Produce json
+// @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param weeks query int false "Stored rotations to analyze (default 52, max 520)"
+// @Param limit query int false "Max overdue champions (default 20, max 200)"
+// @Success 200 {object} service.RotationForecast "Rotation forecast"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/metagame/rotation/{platform}/forecast [get]
+func (h *RiotHandler) getRotationForecast(w http.ResponseWriter, r *http.Request) {
+	platform := chi.URLParam(r, "platform")
+	forecast, err := h.metaGameSvc.RotationForecast(r.Context(), platform, rotationWeeks(r), historyLimit(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting rotation forecast: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "forecast": forecast})
+}
+
+// rotationWeeks lee ?weeks= (default 52, max 520)
+func rotationWeeks(r *http.Request) int {
+	weeks := 52
+	if v, err := strconv.Atoi(r.URL.Query().Get("weeks")); err == nil && v > 0 {
+		weeks = v
+	}
+	if weeks > 520 {
+		weeks = 520
+	}
+	return weeks
 }
 
 // @Summary Analyze league rankings and statistics
@@ -130,6 +224,7 @@ func (h *RiotHandler) analyzeChampionRotation(w http.ResponseWriter, r *http.Req
 // @Produce json
 // @Param platform path string true "Platform (e.g., na1, euw1, kr)"
 // @Param queue path string true "Queue type (e.g., RANKED_SOLO_5x5, RANKED_FLEX_SR)"
+// @Param fresh query bool false "Force a live analysis instead of the latest stored snapshot"
 // @Success 200 {object} map[string]interface{} "League analysis with statistics and rankings"
 // @Failure 400 {string} string "Platform and queue parameters are required"
 // @Failure 500 {string} string "Internal server error"
@@ -141,12 +236,82 @@ func (h *RiotHandler) analyzeLeagueRankings(w http.ResponseWriter, r *http.Reque
 		http.Error(w, "Platform and queue parameters are required", http.StatusBadRequest)
 		return
 	}
+	if h.writeStoredAnalysis(w, r, platform, service.LeagueAnalysisType(queue), map[string]any{"platform": platform, "queue": queue}, "analysis") {
+		return
+	}
 	analysis, err := h.metaGameSvc.AnalyzeLeagueRankings(r.Context(), platform, queue)
 	if err != nil {
 		http.Error(w, fmt.Errorf("Error analyzing league rankings: %w", err).Error(), http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "queue": queue, "source": "live", "generated_at": time.Now().UTC(), "analysis": analysis})
+}
+
+// @Summary League statistics history
+// @Description List the stored ladder snapshots (players, avg LP, avg win rate, top LP) of a queue, newest first
+// @Tags riot
+// @Produce json
+// @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param queue path string true "Queue type (e.g., RANKED_SOLO_5x5, RANKED_FLEX_SR)"
+// @Param limit query int false "Max rows (default 20, max 200)"
+// @Success 200 {object} map[string]interface{} "Stored league rankings"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/metagame/league/{platform}/{queue}/history [get]
+func (h *RiotHandler) getLeagueHistory(w http.ResponseWriter, r *http.Request) {
+	platform := chi.URLParam(r, "platform")
+	queue := chi.URLParam(r, "queue")
+	rankings, err := h.metaGameSvc.LeagueHistory(r.Context(), platform, queue, historyLimit(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting league history: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "queue": queue, "rankings": rankings})
+}
+
+// @Summary Analyze ladder distribution
+// @Description LP and win-rate distributions (percentiles and histograms), hot-streak/veteran/fresh-blood ratios and LP cutoffs. Without tier it walks Challenger, Grandmaster and Master; non-apex tiers are read page by page per division.
+// @Tags riot
+// @Produce json
+// @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param queue path string true "Queue type (e.g., RANKED_SOLO_5x5, RANKED_FLEX_SR)"
+// @Param tier query string false "CHALLENGER | GRANDMASTER | MASTER | DIAMOND | EMERALD | PLATINUM | GOLD | SILVER | BRONZE | IRON"
+// @Param division query string false "I | II | III | IV (non-apex tiers only)"
+// @Param pages query int false "Pages per division for non-apex tiers (default 1, max 10)"
+// @Success 200 {object} map[string]interface{} "Ladder analysis"
+// @Failure 400 {string} string "Invalid tier or division"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/metagame/ladder/{platform}/{queue} [get]
+func (h *RiotHandler) analyzeLadder(w http.ResponseWriter, r *http.Request) {
+	platform := chi.URLParam(r, "platform")
+	queue := chi.URLParam(r, "queue")
+	q := r.URL.Query()
+
+	opts := service.LadderOptions{Tier: q.Get("tier"), Division: q.Get("division")}
+	if opts.Tier != "" && !service.IsKnownTier(opts.Tier) {
+		http.Error(w, "Unknown tier", http.StatusBadRequest)
+		return
+	}
+	switch opts.Division {
+	case "", "I", "II", "III", "IV":
+	default:
+		http.Error(w, "division must be one of I, II, III, IV", http.StatusBadRequest)
+		return
+	}
+	if v, err := strconv.Atoi(q.Get("pages")); err == nil && v > 0 {
+		opts.MaxPages = v
+		if opts.MaxPages > 10 {
+			opts.MaxPages = 10
+		}
+	}
+
+	analysis, err := h.metaGameSvc.AnalyzeLadder(r.Context(), platform, queue, opts)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error analyzing ladder: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "queue": queue, "analysis": analysis})
 }
 
@@ -156,6 +321,7 @@ func (h *RiotHandler) analyzeLeagueRankings(w http.ResponseWriter, r *http.Reque
 // @Accept json
 // @Produce json
 // @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param fresh query bool false "Force a live report instead of the latest stored snapshot"
 // @Success 200 {object} map[string]interface{} "Complete meta-game report with analysis and insights"
 // @Failure 400 {string} string "Platform parameter is required"
 // @Failure 500 {string} string "Internal server error"
@@ -166,13 +332,160 @@ func (h *RiotHandler) generateMetaReport(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "Platform parameter is required", http.StatusBadRequest)
 		return
 	}
+	if h.writeStoredAnalysis(w, r, platform, service.AnalysisTypeReport, map[string]any{"platform": platform}, "report") {
+		return
+	}
 	report, err := h.metaGameSvc.GenerateMetaReport(r.Context(), platform)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error generating meta report: %v", err), http.StatusInternalServerError)
 		return
 	}
 	w.Header().Set("Content-Type", "application/json")
-	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "report": report})
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "source": "live", "generated_at": report.GeneratedAt, "report": report})
+}
+
+// @Summary Meta-game report history
+// @Description List the stored meta-game reports of a platform, newest first
+// @Tags riot
+// @Produce json
+// @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param limit query int false "Max rows (default 20, max 200)"
+// @Success 200 {object} map[string]interface{} "Stored reports"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/metagame/report/{platform}/history [get]
+func (h *RiotHandler) getMetaReportHistory(w http.ResponseWriter, r *http.Request) {
+	platform := chi.URLParam(r, "platform")
+	reports, err := h.metaGameSvc.AnalysisHistory(r.Context(), platform, service.AnalysisTypeReport, historyLimit(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting report history: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "reports": reports})
+}
+
+// @Summary Cross-region meta comparison
+// @Description Compare rotation, apex ladder (LP distributions, cutoffs, win rate spread) and champion pick rates across platforms. Without ?platforms the latest snapshot stored by the worker is returned.
+// @Tags riot
+// @Produce json
+// @Param platforms query string false "Comma-separated platforms (e.g., euw1,kr,na1); at least two"
+// @Param queue query string false "Ranked queue (default RANKED_SOLO_5x5)"
+// @Success 200 {object} map[string]interface{} "Cross-region report"
+// @Failure 400 {string} string "At least two platforms are required"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/metagame/cross-region [get]
+func (h *RiotHandler) getCrossRegionReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	platforms := strings.Split(q.Get("platforms"), ",")
+	if q.Get("platforms") == "" {
+		if h.writeStoredAnalysis(w, r, service.CrossRegionPlatform, service.AnalysisTypeCrossRegion, map[string]any{}, "report") {
+			return
+		}
+		http.Error(w, "platforms query parameter is required", http.StatusBadRequest)
+		return
+	}
+	report, err := h.metaGameSvc.CrossRegionReport(r.Context(), platforms, service.CrossRegionOptions{Queue: q.Get("queue")})
+	if errors.Is(err, service.ErrCrossRegionPlatforms) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error generating cross-region report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "source": "live", "generated_at": report.GeneratedAt, "report": report})
+}
+
+// @Summary Cross-region report history
+// @Description List the stored cross-region reports, newest first
+// @Tags riot
+// @Produce json
+// @Param limit query int false "Max rows (default 20, max 200)"
+// @Success 200 {object} map[string]interface{} "Stored reports"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/metagame/cross-region/history [get]
+func (h *RiotHandler) getCrossRegionHistory(w http.ResponseWriter, r *http.Request) {
+	reports, err := h.metaGameSvc.AnalysisHistory(r.Context(), service.CrossRegionPlatform, service.AnalysisTypeCrossRegion, historyLimit(r))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting cross-region history: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "reports": reports})
+}
+
+// writeStoredAnalysis responde con el último análisis guardado por el worker.
+// Devuelve false si se pidió ?fresh=true o todavía no hay snapshot (el caller calcula en vivo).
+func (h *RiotHandler) writeStoredAnalysis(w http.ResponseWriter, r *http.Request, platform, analysisType string, resp map[string]any, key string) bool {
+	if fresh, _ := strconv.ParseBool(r.URL.Query().Get("fresh")); fresh {
+		return false
+	}
+	stored, err := h.metaGameSvc.LatestAnalysis(r.Context(), platform, analysisType)
+	if err != nil || stored == nil {
+		return false
+	}
+	resp["success"] = true
+	resp["source"] = "stored"
+	resp["patch"] = stored.Patch
+	resp["generated_at"] = stored.CreatedAt
+	resp[key] = json.RawMessage(stored.Data)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+	return true
+}
+
+// historyLimit lee ?limit= para los endpoints de historial (default 20, máx. 200)
+func historyLimit(r *http.Request) int {
+	limit := 20
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+		limit = v
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	return limit
+}
+
+// @Summary Champion mastery statistics
+// @Description Average and top mastery points/levels per champion, aggregated by the worker from a ladder sample. sample_size and updated_at tell how much data backs the numbers.
+// @Tags riot
+// @Produce json
+// @Param platform path string true "Platform (e.g., na1, euw1, kr)"
+// @Param sort query string false "avg_points (default) | top_points | avg_level | players"
+// @Param champion query string false "Champion ID or (partial) name"
+// @Param limit query int false "Max rows"
+// @Success 200 {object} map[string]interface{} "Mastery statistics"
+// @Failure 400 {string} string "Invalid sort"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/mastery/{platform} [get]
+func (h *RiotHandler) getChampionMastery(w http.ResponseWriter, r *http.Request) {
+	platform := chi.URLParam(r, "platform")
+	q := out.MasteryQuery{Sort: r.URL.Query().Get("sort")}
+	switch q.Sort {
+	case "", "avg_points", "top_points", "avg_level", "players":
+	default:
+		http.Error(w, "sort must be one of avg_points, top_points, avg_level, players", http.StatusBadRequest)
+		return
+	}
+	if champion := r.URL.Query().Get("champion"); champion != "" {
+		if id, err := strconv.Atoi(champion); err == nil {
+			q.ChampionID = id
+		} else {
+			q.ChampionName = champion
+		}
+	}
+	if v, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && v > 0 {
+		q.Limit = v
+	}
+
+	report, err := h.masterySvc.ListMastery(r.Context(), platform, q)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting mastery stats: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "platform": platform, "sample_size": report.SampleSize, "updated_at": report.UpdatedAt, "champions": report.Champions})
 }
 
 type PatchInfoResponse struct {
@@ -309,7 +622,7 @@ func (h *RiotHandler) getChampionStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Usar el servicio especializado para estadísticas de campeones
-	stats, err := h.championStatsSvc.GetChampionStats(version)
+	stats, err := h.championStatsSvc.GetChampionStats(r.Context(), version)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error getting champion stats: %v", err), http.StatusInternalServerError)
 		return
@@ -363,19 +676,20 @@ func (h *RiotHandler) getPatchChanges(w http.ResponseWriter, r *http.Request) {
 
 type ProfessionalLeaguesResponse struct {
 	Success bool                   `json:"success"`
-	Data    map[string]interface{} `json:"data"`
+	Data    *service.ProLeagueList `json:"data"`
 }
 
-// @Summary Get professional League of Legends leagues information
-// @Description Retrieve detailed information about all major professional leagues (LEC, LCK, LPL, etc.)
+// @Summary Get professional leagues information
+// @Description Retrieve the active professional leagues stored in the database with their latest season and games analyzed
 // @Tags riot
 // @Accept json
 // @Produce json
+// @Param game query string false "lol | val (default: all)" enums(lol,val)
 // @Success 200 {object} ProfessionalLeaguesResponse "Professional leagues information"
 // @Failure 500 {string} string "Internal server error"
 // @Router /v1/signal/riot/pro-leagues [get]
 func (h *RiotHandler) getProfessionalLeagues(w http.ResponseWriter, r *http.Request) {
-	leagues, err := h.professionalLeagueSvc.GetProfessionalLeagues()
+	leagues, err := h.proLeagueSvc.Leagues(r.Context(), r.URL.Query().Get("game"))
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error getting professional leagues: %v", err), http.StatusInternalServerError)
 		return
@@ -386,19 +700,22 @@ func (h *RiotHandler) getProfessionalLeagues(w http.ResponseWriter, r *http.Requ
 }
 
 type LeagueChampionsResponse struct {
-	Success bool                   `json:"success"`
-	League  string                 `json:"league"`
-	Data    map[string]interface{} `json:"data"`
+	Success bool                        `json:"success"`
+	League  string                      `json:"league"`
+	Data    *service.ProLeagueChampions `json:"data"`
 }
 
 // @Summary Get champion statistics for a professional league
-// @Description Retrieve detailed champion statistics including pick rates, win rates, and ban rates for a specific professional league
+// @Description Retrieve the most picked and most banned champions (agents in Valorant) of a professional league season, aggregated from imported pro matches
 // @Tags riot
 // @Accept json
 // @Produce json
 // @Param league path string true "League code (e.g., LEC, LCK, LPL, LTA)"
+// @Param season query string false "Season (default: latest season with data)"
+// @Param limit query int false "Max champions per list (default 20, max 200)"
 // @Success 200 {object} LeagueChampionsResponse "League champion statistics"
 // @Failure 400 {string} string "Invalid league parameter"
+// @Failure 404 {string} string "League not found"
 // @Failure 500 {string} string "Internal server error"
 // @Router /v1/signal/riot/pro-leagues/{league}/champions [get]
 func (h *RiotHandler) getLeagueChampions(w http.ResponseWriter, r *http.Request) {
@@ -407,15 +724,13 @@ func (h *RiotHandler) getLeagueChampions(w http.ResponseWriter, r *http.Request)
 		http.Error(w, "League parameter is required", http.StatusBadRequest)
 		return
 	}
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
 
-	// Usar el servicio especializado para validar y obtener datos
-	if !h.professionalLeagueSvc.ValidateLeague(league) {
-		validLeagues := []string{"LEC", "LCK", "LPL", "LTA", "LCS", "VCS", "PCS"}
-		http.Error(w, fmt.Sprintf("Invalid league: %s. Valid leagues: %v", league, validLeagues), http.StatusBadRequest)
+	champions, err := h.proLeagueSvc.LeagueChampions(r.Context(), league, r.URL.Query().Get("season"), limit)
+	if errors.Is(err, service.ErrProLeagueNotFound) {
+		http.Error(w, fmt.Sprintf("League not found: %s", league), http.StatusNotFound)
 		return
 	}
-
-	champions, err := h.professionalLeagueSvc.GetLeagueChampions(league)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error getting league champions: %v", err), http.StatusInternalServerError)
 		return
@@ -424,7 +739,7 @@ func (h *RiotHandler) getLeagueChampions(w http.ResponseWriter, r *http.Request)
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(LeagueChampionsResponse{
 		Success: true,
-		League:  league,
+		League:  champions.League,
 		Data:    champions,
 	})
 }
@@ -1,35 +1,31 @@
 package http
 
 import (
-	"fmt"
 	"net/http"
-	"os"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/rs/zerolog/log"
 	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
 	"github.com/steven230500/hypeatlas-api/modules/signal/domain/service"
 	"github.com/steven230500/hypeatlas-api/providers/riot"
 )
 
-// NewRouter crea un router con todos los handlers del módulo signal
-func NewRouter(repo out.Repository) chi.Router {
+// NewRouter crea un router con todos los handlers del módulo signal; riotSvc es el servicio
+// de Riot compartido de la API (nil sin RIOT_API_KEY desactiva las rutas de Riot)
+func NewRouter(repo out.Repository, riotSvc *riot.Service) chi.Router {
 	r := chi.NewRouter()
 
 	// Servicio principal del módulo
 	signalSvc := service.New(repo)
 
-	// Cliente de Riot (condicional por env)
-	riotAPIKey := os.Getenv("RIOT_API_KEY")
-	fmt.Println("Checking RIOT_API_KEY:", riotAPIKey != "")
-	var riotSvc *riot.Service
 	var metaGameSvc *service.MetaGameService
-	if riotAPIKey != "" {
-		fmt.Println("RIOT_API_KEY found, initializing Riot services...")
-		riotSvc = riot.NewService(riotAPIKey, repo)
+	var masterySvc *service.MasteryService
+	if riotSvc != nil {
 		metaGameSvc = service.NewMetaGameService(repo, riotSvc)
-		fmt.Println("Riot services initialized successfully")
+		masterySvc = service.NewMasteryService(repo, riotSvc)
+		log.Info().Msg("riot services initialized")
 	} else {
-		fmt.Println("RIOT_API_KEY not found in environment")
+		log.Warn().Msg("RIOT_API_KEY not set: riot routes disabled")
 	}
 
 	// Handlers principales (sin prefijos internos)
@@ -38,23 +34,13 @@ func NewRouter(repo out.Repository) chi.Router {
 
 	// Handler de Riot (si hay key)
 	if riotSvc != nil && metaGameSvc != nil {
-		fmt.Println("Registering Riot handler...")
-		riotHandler := NewRiotHandler(riotSvc, signalSvc, metaGameSvc)
+		riotHandler := NewRiotHandler(riotSvc, signalSvc, metaGameSvc, masterySvc, service.NewProLeagueService(repo))
 		riotHandler.Register(r)
 		r.Get("/riot/_health", func(w http.ResponseWriter, _ *http.Request) {
 			w.Header().Set("Content-Type", "application/json")
 			_, _ = w.Write([]byte(`{"ok":true}`))
 		})
-		fmt.Println("Riot handler registered successfully")
-	} else {
-		fmt.Println("Riot services not available - handler not registered")
 	}
 
-	// Dump del SUBROUTER (paths relativos)
-	_ = chi.Walk(r, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
-		fmt.Printf("[ROUTE] %s %s\n", method, route)
-		return nil
-	})
-
 	return r
 }
@@ -0,0 +1,232 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/steven230500/hypeatlas-api/providers/riot"
+)
+
+// registerVAL monta los endpoints de Valorant bajo /riot/val
+func (h *RiotHandler) registerVAL(r chi.Router) {
+	r.Route("/val", func(r chi.Router) {
+		r.Get("/content/{shard}", h.getVALContent)
+		r.Get("/patch/{shard}", h.getVALPatch)
+		r.Get("/agents/{shard}", h.getVALContentSection(func(c *riot.VALContentResponse) any { return c.Characters }))
+		r.Get("/maps/{shard}", h.getVALContentSection(func(c *riot.VALContentResponse) any { return c.Maps }))
+		r.Get("/acts/{shard}", h.getVALContentSection(func(c *riot.VALContentResponse) any { return c.Acts }))
+		r.Get("/game-modes/{shard}", h.getVALContentSection(func(c *riot.VALContentResponse) any { return c.GameModes }))
+		r.Get("/equipment/{shard}", h.getVALContentSection(func(c *riot.VALContentResponse) any { return c.Equips }))
+		r.Get("/leaderboards/{shard}/{actID}", h.getVALLeaderboard)
+		r.Get("/matches/{shard}/{matchID}", h.getVALMatch)
+		r.Get("/recent-matches/{shard}/{queue}", h.getVALRecentMatches)
+	})
+}
+
+// valShard valida el shard de la URL y responde 400 si no es válido
+func valShard(w http.ResponseWriter, r *http.Request) (string, bool) {
+	shard := chi.URLParam(r, "shard")
+	if !riot.IsVALShard(shard) {
+		http.Error(w, "shard must be one of na, latam, br, eu, ap, kr", http.StatusBadRequest)
+		return "", false
+	}
+	return shard, true
+}
+
+type VALContentHTTPResponse struct {
+	Success bool                     `json:"success"`
+	Shard   string                   `json:"shard"`
+	Content *riot.VALContentResponse `json:"content"`
+}
+
+// @Summary Get Valorant content
+// @Description Full VAL-CONTENT-V1 payload: agents, maps, acts, game modes, equipment and cosmetics
+// @Tags riot-val
+// @Produce json
+// @Param shard path string true "Shard (na, latam, br, eu, ap, kr)"
+// @Param locale query string false "Locale (e.g., en-US, es-ES)"
+// @Success 200 {object} VALContentHTTPResponse "Valorant content"
+// @Failure 400 {string} string "Invalid shard"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/val/content/{shard} [get]
+func (h *RiotHandler) getVALContent(w http.ResponseWriter, r *http.Request) {
+	shard, ok := valShard(w, r)
+	if !ok {
+		return
+	}
+	content, err := h.riotSvc.GetVALContent(r.Context(), shard, r.URL.Query().Get("locale"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting Valorant content: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(VALContentHTTPResponse{Success: true, Shard: shard, Content: content})
+}
+
+type VALPatchResponse struct {
+	Success   bool         `json:"success"`
+	Shard     string       `json:"shard"`
+	Version   string       `json:"version"`
+	ActiveAct *riot.VALAct `json:"active_act,omitempty"`
+}
+
+// @Summary Get current Valorant patch
+// @Description Content version and active act of a shard
+// @Tags riot-val
+// @Produce json
+// @Param shard path string true "Shard (na, latam, br, eu, ap, kr)"
+// @Success 200 {object} VALPatchResponse "Current patch"
+// @Failure 400 {string} string "Invalid shard"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/val/patch/{shard} [get]
+func (h *RiotHandler) getVALPatch(w http.ResponseWriter, r *http.Request) {
+	shard, ok := valShard(w, r)
+	if !ok {
+		return
+	}
+	content, err := h.riotSvc.GetVALContent(r.Context(), shard, "")
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting Valorant content: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(VALPatchResponse{Success: true, Shard: shard, Version: content.Version, ActiveAct: content.ActiveAct()})
+}
+
+// @Summary Get Valorant content section
+// @Description Agents, maps, acts, game modes or equipment of a shard (one endpoint per section)
+// @Tags riot-val
+// @Produce json
+// @Param shard path string true "Shard (na, latam, br, eu, ap, kr)"
+// @Param locale query string false "Locale (e.g., en-US, es-ES)"
+// @Success 200 {object} map[string]interface{} "Section items"
+// @Failure 400 {string} string "Invalid shard"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/val/agents/{shard} [get]
+// @Router /v1/signal/riot/val/maps/{shard} [get]
+// @Router /v1/signal/riot/val/acts/{shard} [get]
+// @Router /v1/signal/riot/val/game-modes/{shard} [get]
+// @Router /v1/signal/riot/val/equipment/{shard} [get]
+func (h *RiotHandler) getVALContentSection(section func(*riot.VALContentResponse) any) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		shard, ok := valShard(w, r)
+		if !ok {
+			return
+		}
+		content, err := h.riotSvc.GetVALContent(r.Context(), shard, r.URL.Query().Get("locale"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error getting Valorant content: %v", err), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "shard": shard, "version": content.Version, "items": section(content)})
+	}
+}
+
+type VALLeaderboardResponse struct {
+	Success     bool                 `json:"success"`
+	Shard       string               `json:"shard"`
+	Leaderboard *riot.VALLeaderboard `json:"leaderboard"`
+}
+
+// @Summary Get Valorant ranked leaderboard
+// @Description VAL-RANKED-V1 leaderboard of an act; use "current" as actID for the active act
+// @Tags riot-val
+// @Produce json
+// @Param shard path string true "Shard (na, latam, br, eu, ap, kr)"
+// @Param actID path string true "Act ID or 'current'"
+// @Param size query int false "Page size (max 200)"
+// @Param start query int false "Start index"
+// @Success 200 {object} VALLeaderboardResponse "Leaderboard page"
+// @Failure 400 {string} string "Invalid shard"
+// @Failure 404 {string} string "No active act"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/val/leaderboards/{shard}/{actID} [get]
+func (h *RiotHandler) getVALLeaderboard(w http.ResponseWriter, r *http.Request) {
+	shard, ok := valShard(w, r)
+	if !ok {
+		return
+	}
+	actID := chi.URLParam(r, "actID")
+	if actID == "current" {
+		content, err := h.riotSvc.GetVALContent(r.Context(), shard, "")
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Error getting Valorant content: %v", err), http.StatusInternalServerError)
+			return
+		}
+		act := content.ActiveAct()
+		if act == nil {
+			http.Error(w, "No active act", http.StatusNotFound)
+			return
+		}
+		actID = act.ID
+	}
+	size, _ := strconv.Atoi(r.URL.Query().Get("size"))
+	start, _ := strconv.Atoi(r.URL.Query().Get("start"))
+
+	leaderboard, err := h.riotSvc.GetVALLeaderboard(r.Context(), shard, actID, size, start)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting Valorant leaderboard: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(VALLeaderboardResponse{Success: true, Shard: shard, Leaderboard: leaderboard})
+}
+
+type VALMatchResponse struct {
+	Success bool           `json:"success"`
+	Shard   string         `json:"shard"`
+	Match   *riot.VALMatch `json:"match"`
+}
+
+// @Summary Get Valorant match
+// @Description VAL-MATCH-V1 match details (info, players, teams and rounds)
+// @Tags riot-val
+// @Produce json
+// @Param shard path string true "Shard (na, latam, br, eu, ap, kr)"
+// @Param matchID path string true "Match ID"
+// @Success 200 {object} VALMatchResponse "Match details"
+// @Failure 400 {string} string "Invalid shard"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/val/matches/{shard}/{matchID} [get]
+func (h *RiotHandler) getVALMatch(w http.ResponseWriter, r *http.Request) {
+	shard, ok := valShard(w, r)
+	if !ok {
+		return
+	}
+	match, err := h.riotSvc.GetVALMatch(r.Context(), shard, chi.URLParam(r, "matchID"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting Valorant match: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(VALMatchResponse{Success: true, Shard: shard, Match: match})
+}
+
+// @Summary Get recent Valorant matches
+// @Description Match IDs recently played in a queue (competitive, unrated, ...)
+// @Tags riot-val
+// @Produce json
+// @Param shard path string true "Shard (na, latam, br, eu, ap, kr)"
+// @Param queue path string true "Queue (e.g., competitive)"
+// @Success 200 {object} map[string]interface{} "Recent match IDs"
+// @Failure 400 {string} string "Invalid shard"
+// @Failure 500 {string} string "Internal server error"
+// @Router /v1/signal/riot/val/recent-matches/{shard}/{queue} [get]
+func (h *RiotHandler) getVALRecentMatches(w http.ResponseWriter, r *http.Request) {
+	shard, ok := valShard(w, r)
+	if !ok {
+		return
+	}
+	queue := chi.URLParam(r, "queue")
+	recent, err := h.riotSvc.GetVALRecentMatches(r.Context(), shard, queue)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting recent Valorant matches: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "shard": shard, "queue": queue, "match_ids": recent.MatchIDs})
+}
@@ -2,11 +2,18 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/steven230500/hypeatlas-api/domain/entities"
 	out "github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
 	"github.com/steven230500/hypeatlas-api/shared/db"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repo struct{ db *gorm.DB }
@@ -34,6 +41,147 @@ func (r *Repo) PatchChanges(ctx context.Context, game, version, entityType strin
 	return changes, result.Error
 }
 
+// PatchChangesByPatches devuelve los cambios de varios parches; entityType y entityID
+// son opcionales (entityID se compara sin distinguir mayúsculas).
+func (r *Repo) PatchChangesByPatches(ctx context.Context, patchUUIDs []uuid.UUID, entityType, entityID string) ([]entities.PatchChange, error) {
+	var changes []entities.PatchChange
+	if len(patchUUIDs) == 0 {
+		return changes, nil
+	}
+	query := r.db.WithContext(ctx).Where("patch_uuid IN ?", patchUUIDs)
+	if entityType != "" {
+		query = query.Where("entity_type = ?", entityType)
+	}
+	if entityID != "" {
+		query = query.Where("lower(entity_id) = lower(?)", entityID)
+	}
+	result := db.Call(query.Order("entity_type, entity_id, field, id").Find(&changes))
+	return changes, result.Error
+}
+
+// UpsertPatchChanges crea el parche si no existe y crea/actualiza sus cambios por
+// (entity_type, entity_id, field). Devuelve el parche.
+func (r *Repo) UpsertPatchChanges(ctx context.Context, game, version string, releasedAt *time.Time, changes []entities.PatchChange) (*entities.Patch, error) {
+	var patch entities.Patch
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := db.Call(tx.Where("game = ? AND version = ?", game, version).
+			Attrs(entities.Patch{Game: game, Version: version, ReleasedAt: releasedAt}).
+			FirstOrCreate(&patch)).Error; err != nil {
+			return err
+		}
+		for _, c := range changes {
+			var existing entities.PatchChange
+			found := db.Call(tx.Where("patch_uuid = ? AND entity_type = ? AND entity_id = ? AND field = ?",
+				patch.UUID, c.EntityType, c.EntityID, c.Field).Limit(1).Find(&existing))
+			if found.Error != nil {
+				return found.Error
+			}
+			if found.RowsAffected == 0 {
+				c.ID = 0
+				c.PatchUUID = patch.UUID
+				if err := db.Call(tx.Create(&c)).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			if err := db.Call(tx.Model(&existing).Updates(map[string]any{"old": c.Old, "new": c.New})).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return &patch, err
+}
+
+// UpdatePatchChangeScores guarda impact_score por id de cambio.
+func (r *Repo) UpdatePatchChangeScores(ctx context.Context, scores map[int64]float64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for id, score := range scores {
+			if err := db.Call(tx.Model(&entities.PatchChange{}).Where("id = ?", id).
+				Updates(map[string]any{"impact_score": score, "updated_at": time.Now()})).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// EntityPickRates devuelve el pick rate (%) de cada agente/campeón en un parche, con la
+// clave en minúsculas. VAL: % de partidas de equipo (comps completas, ambos lados) en que
+// aparece. LoL: media de champion_match_stats por plataforma/cola; los campeones sin
+// partidas medidas caen a league_champion_stats (ligas profesionales), usando solo la
+// temporada más reciente de cada liga (mismo orden que LeagueSeasons) para no mezclar
+// pick rates de temporadas viejas; las temporadas no se asocian a parches.
+func (r *Repo) EntityPickRates(ctx context.Context, game, patch string) (map[string]float64, error) {
+	type row struct {
+		Entity   string
+		PickRate float64
+	}
+	var rows []row
+	rates := make(map[string]float64)
+
+	if game == "val" {
+		// language=SQL
+		const q = `
+WITH c AS (
+  SELECT games, slots FROM app.comps
+  WHERE game = 'val' AND patch = ? AND side = '' AND shape = 'full' AND games > 0
+), total AS (
+  SELECT SUM(games)::numeric AS games FROM c
+)
+SELECT lower(m->>'agent') AS entity, round(SUM(c.games) * 100 / total.games, 3) AS pick_rate
+FROM c
+CROSS JOIN total
+CROSS JOIN LATERAL jsonb_array_elements(c.slots->'members') m
+WHERE total.games > 0
+GROUP BY lower(m->>'agent'), total.games;
+`
+		result := db.Call(r.db.WithContext(ctx).Raw(q, patch).Scan(&rows))
+		for _, row := range rows {
+			rates[row.Entity] = row.PickRate
+		}
+		return rates, result.Error
+	}
+
+	// language=SQL
+	const matchQ = `
+SELECT lower(champion_name) AS entity, round(AVG(pick_rate), 3) AS pick_rate
+FROM app.champion_match_stats
+WHERE patch = ? AND role = ''
+GROUP BY lower(champion_name);
+`
+	if err := db.Call(r.db.WithContext(ctx).Raw(matchQ, patch).Scan(&rows)).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		rates[row.Entity] = row.PickRate
+	}
+
+	// language=SQL
+	const proQ = `
+WITH latest AS (
+  SELECT DISTINCT ON (league_code) league_code, season
+  FROM app.league_champion_stats
+  GROUP BY league_code, season
+  ORDER BY league_code, max(last_updated) DESC, season DESC
+)
+SELECT lower(s.champion_name) AS entity, round(AVG(s.pick_rate), 3) AS pick_rate
+FROM app.league_champion_stats s
+JOIN latest l ON l.league_code = s.league_code AND l.season = s.season
+GROUP BY lower(s.champion_name);
+`
+	rows = nil
+	if err := db.Call(r.db.WithContext(ctx).Raw(proQ).Scan(&rows)).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		if _, ok := rates[row.Entity]; !ok {
+			rates[row.Entity] = row.PickRate
+		}
+	}
+	return rates, nil
+}
+
 func (r *Repo) Leagues(ctx context.Context, game, region string) ([]entities.League, error) {
 	var leagues []entities.League
 	query := r.db.WithContext(ctx).Where("game = ?", game)
@@ -44,44 +192,576 @@ func (r *Repo) Leagues(ctx context.Context, game, region string) ([]entities.Lea
 	return leagues, result.Error
 }
 
-func (r *Repo) Comps(ctx context.Context, game, region, league, patch, mapp, side string, limit int) ([]entities.Comp, error) {
+func (r *Repo) Comps(ctx context.Context, q out.CompQuery) ([]entities.Comp, error) {
 	var comps []entities.Comp
-	query := r.db.WithContext(ctx).Where("game = ? AND region = ? AND patch = ?", game, region, patch)
-	if league != "" {
-		query = query.Where("league = ?", league)
+	query := r.db.WithContext(ctx).Where("game = ? AND region = ? AND patch = ?", q.Game, q.Region, q.Patch)
+	if q.Side != "" {
+		query = query.Where("side = ?", q.Side)
 	}
-	if mapp != "" {
-		query = query.Where("map = ?", mapp)
+	if q.Shape != "" {
+		query = query.Where("shape = ?", q.Shape)
 	}
-	if side != "" {
-		query = query.Where("side = ?", side)
+	query = compFilters(query, q)
+	result := db.Call(query.Order(compOrder(q.Sort)).Limit(q.Limit).Find(&comps))
+	return comps, result.Error
+}
+
+// SynergyComps devuelve las comps completas de ambos lados (side "") que contienen a
+// todos los q.Includes; Region y Patch son opcionales aquí.
+func (r *Repo) SynergyComps(ctx context.Context, q out.CompQuery) ([]entities.Comp, error) {
+	var comps []entities.Comp
+	query := r.db.WithContext(ctx).
+		Where("game = ? AND side = '' AND shape = ? AND games > 0", q.Game, entities.CompShapeFull)
+	if q.Region != "" {
+		query = query.Where("region = ?", q.Region)
+	}
+	if q.Patch != "" {
+		query = query.Where("patch = ?", q.Patch)
 	}
-	result := db.Call(query.Order("win_rate DESC NULLS LAST, pick_rate DESC NULLS LAST, uuid").Limit(limit).Find(&comps))
+	query = compFilters(query, q)
+	result := db.Call(query.Find(&comps))
 	return comps, result.Error
 }
 
+// compFilters aplica league, map, min_games e includes/excludes (containment jsonb sobre
+// slots, servido por el índice GIN idx_comps_slots)
+func compFilters(query *gorm.DB, q out.CompQuery) *gorm.DB {
+	if q.League != "" {
+		query = query.Where("league = ?", q.League)
+	}
+	if q.Map != "" {
+		query = query.Where("map = ?", q.Map)
+	}
+	if q.MinGames > 0 {
+		query = query.Where("games >= ?", q.MinGames)
+	}
+	for _, member := range q.Includes {
+		query = query.Where("slots @> ?::jsonb", memberContainment(q.Game, member))
+	}
+	for _, member := range q.Excludes {
+		query = query.Where("NOT (slots @> ?::jsonb)", memberContainment(q.Game, member))
+	}
+	return query
+}
+
+// memberContainment arma {"members":[{"agent":...}]} (VAL) o {"members":[{"champion":...}]} (LoL)
+func memberContainment(game, member string) string {
+	m := entities.CompSlotMember{Champion: member}
+	if game == "val" {
+		m = entities.CompSlotMember{Agent: member}
+	}
+	raw, _ := json.Marshal(map[string]any{"members": []entities.CompSlotMember{m}})
+	return string(raw)
+}
+
+// compOrder traduce CompQuery.Sort a ORDER BY; por defecto ordena por el límite inferior
+// de Wilson para que una comp con pocas partidas no gane a una con cientos
+func compOrder(sort string) string {
+	switch sort {
+	case out.CompSortWinRate:
+		return "win_rate DESC NULLS LAST, games DESC, uuid"
+	case out.CompSortPickRate:
+		return "pick_rate DESC NULLS LAST, games DESC, uuid"
+	case out.CompSortDelta:
+		return "delta_win DESC NULLS LAST, games DESC, uuid"
+	default:
+		return "win_ci_low DESC NULLS LAST, win_rate DESC NULLS LAST, games DESC, uuid"
+	}
+}
+
 // UpsertComp inserta/actualiza una composición.
-// Usa la UNIQUE (game,region,league,patch,map,side,slots_fp).
-func (r *Repo) UpsertComp(
-	ctx context.Context,
-	game, region, league, patch, mapp, side string,
-	slotsJSON string, // JSON en texto
-	pickRate, winRate, deltaWin *float64, // pueden ser nil
-) error {
+// Usa la UNIQUE (game,region,league,patch,map,side,slots_fp); slots_fp se calcula
+// aquí igual que en Comp.BeforeSave porque el INSERT crudo no pasa por el hook.
+// En la misma sentencia guarda un CompSnapshot si games/pick/win cambiaron respecto al último.
+func (r *Repo) UpsertComp(ctx context.Context, c *entities.Comp) error {
+	shape := c.Shape
+	if shape == "" {
+		shape = entities.CompShapeFull
+	}
 	// language=SQL
 	const q = `
-INSERT INTO app.comps
-  (game, region, league, patch, map, side, slots, pick_rate, win_rate, delta_win)
-VALUES (?, ?, ?, ?, ?, ?, ?::jsonb, ?, ?, ?)
-ON CONFLICT (game, region, league, patch, map, side, slots_fp)
-DO UPDATE SET
-  pick_rate  = EXCLUDED.pick_rate,
-  win_rate   = EXCLUDED.win_rate,
-  delta_win  = EXCLUDED.delta_win,
-  updated_at = now();
+WITH c AS (
+  INSERT INTO app.comps
+    (game, region, league, patch, map, side, shape, slots, slots_fp,
+     games, pick_rate, win_rate, delta_win, win_ci_low, win_ci_high, created_at, updated_at)
+  VALUES (?, ?, ?, ?, ?, ?, ?, ?::jsonb, ?, ?, ?, ?, ?, ?, ?, now(), now())
+  ON CONFLICT (game, region, league, patch, map, side, slots_fp)
+  DO UPDATE SET
+    shape       = EXCLUDED.shape,
+    games       = EXCLUDED.games,
+    pick_rate   = EXCLUDED.pick_rate,
+    win_rate    = EXCLUDED.win_rate,
+    delta_win   = EXCLUDED.delta_win,
+    win_ci_low  = EXCLUDED.win_ci_low,
+    win_ci_high = EXCLUDED.win_ci_high,
+    updated_at  = now()
+  RETURNING uuid, game, region, league, patch, map, side, shape, slots_fp,
+            games, pick_rate, win_rate, delta_win, win_ci_low, win_ci_high
+)
+INSERT INTO app.comp_snapshots
+  (comp_uuid, game, region, league, patch, map, side, shape, slots_fp,
+   games, pick_rate, win_rate, delta_win, win_ci_low, win_ci_high, captured_at)
+SELECT c.uuid, c.game, c.region, c.league, c.patch, c.map, c.side, c.shape, c.slots_fp,
+       c.games, c.pick_rate, c.win_rate, c.delta_win, c.win_ci_low, c.win_ci_high, now()
+FROM c
+WHERE NOT EXISTS (
+  SELECT 1
+  FROM (
+    SELECT s.games, s.pick_rate, s.win_rate
+    FROM app.comp_snapshots s
+    WHERE s.comp_uuid = c.uuid
+    ORDER BY s.captured_at DESC, s.id DESC
+    LIMIT 1
+  ) last
+  WHERE last.games = c.games
+    AND last.pick_rate IS NOT DISTINCT FROM c.pick_rate
+    AND last.win_rate IS NOT DISTINCT FROM c.win_rate
+);
 `
 	result := db.Call(r.db.WithContext(ctx).Exec(q,
-		game, region, league, patch, mapp, side, slotsJSON, pickRate, winRate, deltaWin,
+		c.Game, c.Region, c.League, c.Patch, c.Map, c.Side, shape,
+		c.Slots.String(), entities.SlotsFingerprint(c.Slots),
+		c.Games, c.PickRate, c.WinRate, c.DeltaWin, c.WinCILow, c.WinCIHigh,
 	))
 	return result.Error
 }
+
+// CompByUUID devuelve una comp por UUID (nil si no existe).
+func (r *Repo) CompByUUID(ctx context.Context, id uuid.UUID) (*entities.Comp, error) {
+	var comps []entities.Comp
+	result := db.Call(r.db.WithContext(ctx).Where("uuid = ?", id).Limit(1).Find(&comps))
+	if result.Error != nil || len(comps) == 0 {
+		return nil, result.Error
+	}
+	return &comps[0], nil
+}
+
+// CompHistory devuelve los snapshots de la misma comp (game, region, league, map, side, slots_fp)
+// en todos los parches, del más antiguo al más reciente.
+func (r *Repo) CompHistory(ctx context.Context, comp *entities.Comp, limit int) ([]entities.CompSnapshot, error) {
+	var snapshots []entities.CompSnapshot
+	result := db.Call(r.db.WithContext(ctx).
+		Where("game = ? AND region = ? AND league = ? AND map = ? AND side = ? AND slots_fp = ?",
+			comp.Game, comp.Region, comp.League, comp.Map, comp.Side, comp.SlotsFP).
+		Order("captured_at DESC, id DESC").
+		Limit(limit).
+		Find(&snapshots))
+	// Se piden los más recientes y se devuelven en orden cronológico
+	for i, j := 0, len(snapshots)-1; i < j; i, j = i+1, j-1 {
+		snapshots[i], snapshots[j] = snapshots[j], snapshots[i]
+	}
+	return snapshots, result.Error
+}
+
+// PatchComps devuelve todas las comps de un juego y parche; Region es opcional y
+// Side se compara exacto ("" = filas de ambos lados).
+func (r *Repo) PatchComps(ctx context.Context, q out.CompQuery) ([]entities.Comp, error) {
+	var comps []entities.Comp
+	query := r.db.WithContext(ctx).Where("game = ? AND patch = ?", q.Game, q.Patch)
+	if q.Region != "" {
+		query = query.Where("region = ?", q.Region)
+	}
+	query = query.Where("side = ?", q.Side)
+	if q.Shape != "" {
+		query = query.Where("shape = ?", q.Shape)
+	}
+	query = compFilters(query, q)
+	result := db.Call(query.Find(&comps))
+	return comps, result.Error
+}
+
+// CompPatches devuelve los parches con comps de un juego (sin orden garantizado).
+func (r *Repo) CompPatches(ctx context.Context, game string) ([]string, error) {
+	var patches []string
+	result := db.Call(r.db.WithContext(ctx).Model(&entities.Comp{}).
+		Where("game = ?", game).
+		Distinct("patch").
+		Pluck("patch", &patches))
+	return patches, result.Error
+}
+
+// RiotMatchesByPatch devuelve las partidas registradas de un juego y parche (todas las plataformas).
+func (r *Repo) RiotMatchesByPatch(ctx context.Context, game, patch string) ([]entities.RiotMatch, error) {
+	var matches []entities.RiotMatch
+	result := db.Call(r.db.WithContext(ctx).
+		Where("game = ? AND patch = ?", game, patch).
+		Order("game_creation").
+		Find(&matches))
+	return matches, result.Error
+}
+
+// RiotMatchPatches devuelve los parches con partidas registradas de un juego (platform "" = todas)
+func (r *Repo) RiotMatchPatches(ctx context.Context, game, platform string) ([]string, error) {
+	var patches []string
+	query := r.db.WithContext(ctx).Model(&entities.RiotMatch{}).Where("game = ?", game)
+	if platform != "" {
+		query = query.Where("lower(platform) = lower(?)", platform)
+	}
+	result := db.Call(query.Distinct("patch").Pluck("patch", &patches))
+	return patches, result.Error
+}
+
+// RiotMatchesSince devuelve las partidas de una plataforma y cola jugadas desde since, en orden cronológico
+func (r *Repo) RiotMatchesSince(ctx context.Context, game, platform string, queueID int, since time.Time) ([]entities.RiotMatch, error) {
+	var matches []entities.RiotMatch
+	result := db.Call(r.db.WithContext(ctx).
+		Select("match_id, platform, patch, queue_id, game_creation, summary").
+		Where("game = ? AND platform = ? AND queue_id = ? AND game_creation >= ?", game, platform, queueID, since).
+		Order("game_creation").
+		Find(&matches))
+	return matches, result.Error
+}
+
+// KnownRiotMatches devuelve cuáles de los IDs ya fueron procesados.
+func (r *Repo) KnownRiotMatches(ctx context.Context, matchIDs []string) (map[string]bool, error) {
+	known := make(map[string]bool, len(matchIDs))
+	if len(matchIDs) == 0 {
+		return known, nil
+	}
+	var ids []string
+	result := db.Call(r.db.WithContext(ctx).Model(&entities.RiotMatch{}).
+		Where("match_id IN ?", matchIDs).
+		Pluck("match_id", &ids))
+	for _, id := range ids {
+		known[id] = true
+	}
+	return known, result.Error
+}
+
+// RegisterRiotMatch registra la partida; devuelve false si ya existía.
+// Las partidas de LoL usan RecordRiotMatch para sumar además sus contadores.
+func (r *Repo) RegisterRiotMatch(ctx context.Context, match *entities.RiotMatch) (bool, error) {
+	result := db.Call(r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(match))
+	return result.RowsAffected == 1, result.Error
+}
+
+// RecordRiotMatch registra una partida de LoL y suma sus contadores por campeón en una
+// sola transacción; devuelve false (sin tocar contadores) si la partida ya existía.
+// Las tasas se recalculan aparte con RefreshChampionMatchRates.
+func (r *Repo) RecordRiotMatch(ctx context.Context, match *entities.RiotMatch, rows []entities.ChampionMatchStats) (bool, error) {
+	inserted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := db.Call(tx.Clauses(clause.OnConflict{DoNothing: true}).Create(match))
+		if result.Error != nil || result.RowsAffected != 1 {
+			return result.Error
+		}
+		if err := incrementChampionMatchStats(tx, rows); err != nil {
+			return err
+		}
+		inserted = true
+		return nil
+	})
+	return inserted, err
+}
+
+// language=SQL
+const incrementChampionMatchStatsSQL = `
+INSERT INTO app.champion_match_stats
+  (game, platform, patch, queue_id, champion_id, role, champion_name, games, wins, bans, created_at, updated_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, now(), now())
+ON CONFLICT (platform, patch, queue_id, champion_id, role)
+DO UPDATE SET
+  champion_name = EXCLUDED.champion_name,
+  games         = app.champion_match_stats.games + EXCLUDED.games,
+  wins          = app.champion_match_stats.wins + EXCLUDED.wins,
+  bans          = app.champion_match_stats.bans + EXCLUDED.bans,
+  updated_at    = now();
+`
+
+// incrementChampionMatchStats suma juegos, victorias y bans a los contadores existentes dentro de tx
+func incrementChampionMatchStats(tx *gorm.DB, rows []entities.ChampionMatchStats) error {
+	for _, row := range rows {
+		if err := db.Call(tx.Exec(incrementChampionMatchStatsSQL,
+			row.Game, row.Platform, row.Patch, row.QueueID, row.ChampionID, row.Role,
+			row.ChampionName, row.Games, row.Wins, row.Bans,
+		)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshChampionMatchRates recalcula pick/win/ban rate (en %) de un parche
+// usando como denominador las partidas registradas en app.riot_matches.
+func (r *Repo) RefreshChampionMatchRates(ctx context.Context, platform, patch string, queueID int) error {
+	// language=SQL
+	const q = `
+WITH total AS (
+  SELECT COUNT(*)::numeric AS matches
+  FROM app.riot_matches
+  WHERE platform = ? AND patch = ? AND queue_id = ?
+)
+UPDATE app.champion_match_stats s
+SET pick_rate  = CASE WHEN total.matches > 0 THEN round(s.games * 100 / total.matches, 3) ELSE 0 END,
+    win_rate   = CASE WHEN s.games > 0 THEN round(s.wins * 100.0 / s.games, 3) ELSE 0 END,
+    ban_rate   = CASE WHEN total.matches > 0 THEN round(s.bans * 100 / total.matches, 3) ELSE 0 END,
+    updated_at = now()
+FROM total
+WHERE s.platform = ? AND s.patch = ? AND s.queue_id = ?;
+`
+	return db.Call(r.db.WithContext(ctx).Exec(q, platform, patch, queueID, platform, patch, queueID)).Error
+}
+
+func (r *Repo) ChampionMatchStats(ctx context.Context, platform, patch string, queueID int) ([]entities.ChampionMatchStats, error) {
+	var stats []entities.ChampionMatchStats
+	result := db.Call(r.db.WithContext(ctx).
+		Where("platform = ? AND patch = ? AND queue_id = ?", platform, patch, queueID).
+		Order("role, games DESC, champion_id").
+		Find(&stats))
+	return stats, result.Error
+}
+
+// LatestMatchPatch devuelve el parche más reciente con partidas registradas ("" si no hay).
+func (r *Repo) LatestMatchPatch(ctx context.Context, platform string, queueID int) (string, error) {
+	var patches []string
+	result := db.Call(r.db.WithContext(ctx).Model(&entities.RiotMatch{}).
+		Where("platform = ? AND queue_id = ?", platform, queueID).
+		Order("game_creation DESC").
+		Limit(1).
+		Pluck("patch", &patches))
+	if result.Error != nil || len(patches) == 0 {
+		return "", result.Error
+	}
+	return patches[0], nil
+}
+
+// SaveChampionRotation guarda la rotación si cambió respecto de la última registrada.
+// Si es la misma, solo refresca updated_at. Al cambiar, cierra la anterior (rotation_ends_at).
+// Devuelve true si se insertó una rotación nueva.
+func (r *Repo) SaveChampionRotation(ctx context.Context, rotation *entities.ChampionRotation) (bool, error) {
+	inserted := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var last entities.ChampionRotation
+		result := db.Call(tx.Where("game = ? AND platform = ?", rotation.Game, rotation.Platform).
+			Order("created_at DESC").
+			Limit(1).
+			Find(&last))
+		if result.Error != nil {
+			return result.Error
+		}
+
+		now := time.Now().UTC()
+		if result.RowsAffected == 1 &&
+			sameIDs(last.FreeChampionIDs, rotation.FreeChampionIDs) &&
+			sameIDs(last.FreeChampionIDsForNew, rotation.FreeChampionIDsForNew) {
+			return db.Call(tx.Model(&last).Update("updated_at", now)).Error
+		}
+
+		if result.RowsAffected == 1 && last.RotationEndsAt == nil {
+			if err := db.Call(tx.Model(&last).Update("rotation_ends_at", now)).Error; err != nil {
+				return err
+			}
+		}
+		if rotation.RotationStartedAt == nil {
+			rotation.RotationStartedAt = &now
+		}
+		if err := db.Call(tx.Create(rotation)).Error; err != nil {
+			return err
+		}
+		inserted = true
+		return nil
+	})
+	return inserted, err
+}
+
+func (r *Repo) ChampionRotationHistory(ctx context.Context, game, platform string, limit int) ([]entities.ChampionRotation, error) {
+	var rotations []entities.ChampionRotation
+	result := db.Call(r.db.WithContext(ctx).
+		Where("game = ? AND platform = ?", game, platform).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&rotations))
+	return rotations, result.Error
+}
+
+func (r *Repo) SaveLeagueRanking(ctx context.Context, ranking *entities.LeagueRanking) error {
+	return db.Call(r.db.WithContext(ctx).Create(ranking)).Error
+}
+
+func (r *Repo) LeagueRankingHistory(ctx context.Context, game, platform, queue, tier string, limit int) ([]entities.LeagueRanking, error) {
+	var rankings []entities.LeagueRanking
+	query := r.db.WithContext(ctx).Where("game = ? AND platform = ? AND queue = ?", game, platform, queue)
+	if tier != "" {
+		query = query.Where("tier = ?", tier)
+	}
+	result := db.Call(query.Order("created_at DESC").Limit(limit).Find(&rankings))
+	return rankings, result.Error
+}
+
+func (r *Repo) SaveMetaAnalysis(ctx context.Context, analysis *entities.MetaGameAnalysis) error {
+	return db.Call(r.db.WithContext(ctx).Create(analysis)).Error
+}
+
+// LatestMetaAnalysis devuelve el último análisis guardado o nil si no hay ninguno.
+func (r *Repo) LatestMetaAnalysis(ctx context.Context, game, platform, analysisType string) (*entities.MetaGameAnalysis, error) {
+	var analyses []entities.MetaGameAnalysis
+	result := db.Call(r.db.WithContext(ctx).
+		Where("game = ? AND platform = ? AND analysis_type = ?", game, platform, analysisType).
+		Order("created_at DESC").
+		Limit(1).
+		Find(&analyses))
+	if result.Error != nil || len(analyses) == 0 {
+		return nil, result.Error
+	}
+	return &analyses[0], nil
+}
+
+func (r *Repo) MetaAnalysisHistory(ctx context.Context, game, platform, analysisType string, limit int) ([]entities.MetaGameAnalysis, error) {
+	var analyses []entities.MetaGameAnalysis
+	result := db.Call(r.db.WithContext(ctx).
+		Where("game = ? AND platform = ? AND analysis_type = ?", game, platform, analysisType).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&analyses))
+	return analyses, result.Error
+}
+
+// sameIDs compara dos arrays jsonb de IDs sin depender del formato que devuelve Postgres
+func sameIDs(a, b datatypes.JSON) bool {
+	var x, y []int
+	if json.Unmarshal(a, &x) != nil || json.Unmarshal(b, &y) != nil {
+		return false
+	}
+	if len(x) != len(y) {
+		return false
+	}
+	sort.Ints(x)
+	sort.Ints(y)
+	for i := range x {
+		if x[i] != y[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// ProfessionalLeagues devuelve las ligas profesionales activas (game "" = todas)
+func (r *Repo) ProfessionalLeagues(ctx context.Context, game string) ([]entities.ProfessionalLeague, error) {
+	var leagues []entities.ProfessionalLeague
+	query := r.db.WithContext(ctx).Where("is_active")
+	if game != "" {
+		query = query.Where("game = ?", game)
+	}
+	result := db.Call(query.Order("code").Find(&leagues))
+	return leagues, result.Error
+}
+
+// ProfessionalLeague devuelve una liga por código (sin distinguir mayúsculas) o nil si no existe
+func (r *Repo) ProfessionalLeague(ctx context.Context, code string) (*entities.ProfessionalLeague, error) {
+	var league entities.ProfessionalLeague
+	result := db.Call(r.db.WithContext(ctx).Where("upper(code) = upper(?)", code).Limit(1).Find(&league))
+	if result.Error != nil || result.RowsAffected == 0 {
+		return nil, result.Error
+	}
+	return &league, nil
+}
+
+// EnsureProfessionalLeague crea la liga si no existe (no modifica las ligas ya cargadas)
+func (r *Repo) EnsureProfessionalLeague(ctx context.Context, league *entities.ProfessionalLeague) error {
+	return db.Call(r.db.WithContext(ctx).Where("code = ?", league.Code).
+		Attrs(*league).FirstOrCreate(league)).Error
+}
+
+// language=SQL
+const leagueSeasonsSQL = `
+SELECT league_code, season, max(games_analyzed) AS games, max(last_updated) AS last_updated
+FROM app.league_champion_stats
+WHERE upper(league_code) = upper(?)
+GROUP BY league_code, season
+ORDER BY max(last_updated) DESC, season DESC`
+
+// LeagueSeasons devuelve las temporadas con estadísticas de una liga, la más reciente primero
+func (r *Repo) LeagueSeasons(ctx context.Context, code string) ([]out.LeagueSeason, error) {
+	var seasons []out.LeagueSeason
+	result := db.Call(r.db.WithContext(ctx).Raw(leagueSeasonsSQL, code).Scan(&seasons))
+	return seasons, result.Error
+}
+
+// LeagueChampionStats devuelve las estadísticas de campeones de una liga y temporada
+func (r *Repo) LeagueChampionStats(ctx context.Context, code, season string) ([]entities.LeagueChampionStats, error) {
+	var rows []entities.LeagueChampionStats
+	result := db.Call(r.db.WithContext(ctx).
+		Where("upper(league_code) = upper(?) AND season = ?", code, season).
+		Order("pick_rate DESC, champion_name").Find(&rows))
+	return rows, result.Error
+}
+
+// ReplaceLeagueChampionStats reemplaza las estadísticas de una liga y temporada en una transacción
+func (r *Repo) ReplaceLeagueChampionStats(ctx context.Context, code, season string, rows []entities.LeagueChampionStats) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := db.Call(tx.Where("league_code = ? AND season = ?", code, season).
+			Delete(&entities.LeagueChampionStats{})).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return db.Call(tx.CreateInBatches(rows, 100)).Error
+	})
+}
+
+// UpsertProMatches crea o actualiza partidas profesionales por (league_code, match_id)
+func (r *Repo) UpsertProMatches(ctx context.Context, matches []entities.ProMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	return db.Call(r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "league_code"}, {Name: "match_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"game", "season", "patch", "map", "played_at", "summary", "updated_at"}),
+	}).CreateInBatches(matches, 100)).Error
+}
+
+// ProMatches devuelve las partidas profesionales de una liga y temporada
+func (r *Repo) ProMatches(ctx context.Context, code, season string) ([]entities.ProMatch, error) {
+	var matches []entities.ProMatch
+	result := db.Call(r.db.WithContext(ctx).
+		Where("league_code = ? AND season = ?", code, season).
+		Order("played_at NULLS LAST, match_id").Find(&matches))
+	return matches, result.Error
+}
+
+// ReplaceChampionMasteryStats reemplaza el agregado de maestrías de una plataforma en una transacción
+func (r *Repo) ReplaceChampionMasteryStats(ctx context.Context, game, platform string, rows []entities.ChampionMasteryStats) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := db.Call(tx.Where("game = ? AND platform = ?", game, platform).
+			Delete(&entities.ChampionMasteryStats{})).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return db.Call(tx.CreateInBatches(rows, 100)).Error
+	})
+}
+
+// likeEscaper escapa los comodines de LIKE (con \ como carácter de escape)
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+var masterySorts = map[string]string{
+	"avg_points": "avg_mastery_points DESC",
+	"top_points": "top_mastery_points DESC",
+	"avg_level":  "avg_mastery_level DESC",
+	"players":    "total_players DESC",
+}
+
+func (r *Repo) ChampionMasteryStats(ctx context.Context, game, platform string, q out.MasteryQuery) ([]entities.ChampionMasteryStats, error) {
+	var rows []entities.ChampionMasteryStats
+	query := r.db.WithContext(ctx).Where("game = ? AND platform = ?", game, platform)
+	if q.ChampionID > 0 {
+		query = query.Where("champion_id = ?", q.ChampionID)
+	}
+	if q.ChampionName != "" {
+		// % y _ del usuario son literales, no comodines
+		query = query.Where(`champion_name ILIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(q.ChampionName)+"%")
+	}
+	order, ok := masterySorts[q.Sort]
+	if !ok {
+		order = masterySorts["avg_points"]
+	}
+	query = query.Order(order).Order("champion_id")
+	if q.Limit > 0 {
+		query = query.Limit(q.Limit)
+	}
+	result := db.Call(query.Find(&rows))
+	return rows, result.Error
+}
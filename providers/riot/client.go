@@ -1,147 +1,202 @@
 package riot
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
-	"strconv"
-	"sync"
+	"os"
+	"strings"
 	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/steven230500/hypeatlas-api/shared/metrics"
 )
 
-// RateLimiter maneja los límites de rate de Riot Games
-type RateLimiter struct {
-	mu           sync.Mutex
-	requests     []time.Time
-	maxPerSecond int
-	maxPerMinute int
-}
+// maxRetries reintentos ante 429/503 antes de devolver la respuesta al caller
+const maxRetries = 3
 
-// NewRateLimiter crea un nuevo rate limiter
-func NewRateLimiter(maxPerSecond, maxPerMinute int) *RateLimiter {
-	return &RateLimiter{
-		requests:     make([]time.Time, 0),
-		maxPerSecond: maxPerSecond,
-		maxPerMinute: maxPerMinute,
-	}
+// Base URLs por defecto; {route} se reemplaza por la plataforma (euw1) o la región (europe)
+const (
+	DefaultDataDragonURL = "https://ddragon.leagueoflegends.com"
+	DefaultPlatformURL   = "https://{route}.api.riotgames.com"
+	DefaultRegionalURL   = "https://{route}.api.riotgames.com"
+)
+
+// Client para Riot Games API
+type Client struct {
+	apiKey      string
+	client      *http.Client
+	rateLimiter *RateLimiter
+	maxRetries  int
+	ddragonURL  string
+	platformURL string
+	regionalURL string
 }
 
-// Wait bloquea hasta que sea seguro hacer una petición
-func (rl *RateLimiter) Wait() {
-	rl.mu.Lock()
-	defer rl.mu.Unlock()
+// ClientOption configura un Client
+type ClientOption func(*Client)
 
-	now := time.Now()
+// WithRateLimiter reemplaza el limiter (p.ej. uno con reloj falso, o compartido entre clientes)
+func WithRateLimiter(rl *RateLimiter) ClientOption {
+	return func(c *Client) { c.rateLimiter = rl }
+}
 
-	// Limpiar requests antiguos (más de 1 minuto)
-	cutoff := now.Add(-time.Minute)
-	validRequests := make([]time.Time, 0)
-	for _, req := range rl.requests {
-		if req.After(cutoff) {
-			validRequests = append(validRequests, req)
-		}
-	}
-	rl.requests = validRequests
+// WithHTTPClient reemplaza el http.Client usado para las peticiones
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) { c.client = hc }
+}
 
-	// Verificar límites
-	if len(rl.requests) >= rl.maxPerMinute {
-		// Esperar hasta que pase 1 minuto desde la primera request
-		waitTime := time.Minute - now.Sub(rl.requests[0])
-		if waitTime > 0 {
-			time.Sleep(waitTime)
-			now = time.Now()
-		}
-	}
+// WithMaxRetries cambia el número máximo de reintentos ante 429/503
+func WithMaxRetries(n int) ClientOption {
+	return func(c *Client) { c.maxRetries = n }
+}
 
-	// Verificar límite por segundo
-	if len(rl.requests) > 0 {
-		lastRequest := rl.requests[len(rl.requests)-1]
-		if now.Sub(lastRequest) < time.Second {
-			time.Sleep(time.Second - now.Sub(lastRequest))
-		}
-	}
+// WithDataDragonURL cambia la base de Data Dragon (sin barra final)
+func WithDataDragonURL(base string) ClientOption {
+	return func(c *Client) { c.ddragonURL = strings.TrimRight(base, "/") }
+}
 
-	rl.requests = append(rl.requests, now)
+// WithPlatformURL cambia la plantilla de host de plataforma (p.ej. "http://127.0.0.1:9000/{route}")
+func WithPlatformURL(template string) ClientOption {
+	return func(c *Client) { c.platformURL = strings.TrimRight(template, "/") }
 }
 
-// Client para Riot Games API
-type Client struct {
-	apiKey      string
-	client      *http.Client
-	rateLimiter *RateLimiter
+// WithRegionalURL cambia la plantilla de host regional (americas, europe, asia, sea)
+func WithRegionalURL(template string) ClientOption {
+	return func(c *Client) { c.regionalURL = strings.TrimRight(template, "/") }
+}
+
+// OptionsFromEnv lee RIOT_DDRAGON_URL, RIOT_PLATFORM_URL y RIOT_REGIONAL_URL
+func OptionsFromEnv() []ClientOption {
+	var opts []ClientOption
+	if v := os.Getenv("RIOT_DDRAGON_URL"); v != "" {
+		opts = append(opts, WithDataDragonURL(v))
+	}
+	if v := os.Getenv("RIOT_PLATFORM_URL"); v != "" {
+		opts = append(opts, WithPlatformURL(v))
+	}
+	if v := os.Getenv("RIOT_REGIONAL_URL"); v != "" {
+		opts = append(opts, WithRegionalURL(v))
+	}
+	return opts
 }
 
 // NewClient crea un nuevo cliente de Riot Games API
-func NewClient(apiKey string) *Client {
-	return &Client{
+func NewClient(apiKey string, opts ...ClientOption) *Client {
+	c := &Client{
 		apiKey: apiKey,
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
-		rateLimiter: NewRateLimiter(18, 95), // 18 req/s, 95 req/min (margen de seguridad)
+		rateLimiter: NewRateLimiter(DefaultAppLimits, nil),
+		maxRetries:  maxRetries,
+		ddragonURL:  DefaultDataDragonURL,
+		platformURL: DefaultPlatformURL,
+		regionalURL: DefaultRegionalURL,
+	}
+	for _, opt := range opts {
+		opt(c)
 	}
+	return c
 }
 
-// makeRequest hace una petición HTTP con el token de Riot y rate limiting
-func (c *Client) makeRequest(method, url string, body io.Reader) (*http.Response, error) {
-	return c.makeRequestWithAuth(method, url, body, true)
+// regionalRouteValues routing values regionales (el resto son plataformas)
+var regionalRouteValues = map[string]bool{"americas": true, "europe": true, "asia": true, "sea": true}
+
+// apiURL arma la URL de un endpoint de Riot para una plataforma o región
+func (c *Client) apiURL(route, path string) string {
+	template := c.platformURL
+	if regionalRouteValues[route] {
+		template = c.regionalURL
+	}
+	return strings.ReplaceAll(template, "{route}", route) + path
 }
 
-// makeRequestWithoutAuth hace una petición HTTP sin autenticación (para Data Dragon)
-func (c *Client) makeRequestWithoutAuth(method, url string, body io.Reader) (*http.Response, error) {
-	return c.makeRequestWithAuth(method, url, body, false)
+// DataDragonURL arma la URL de un recurso de Data Dragon
+func (c *Client) DataDragonURL(path string) string {
+	return c.ddragonURL + path
 }
 
-// makeRequestWithAuth hace una petición HTTP con control de autenticación
-func (c *Client) makeRequestWithAuth(method, url string, body io.Reader, withAuth bool) (*http.Response, error) {
-	// Aplicar rate limiting solo para requests autenticados
-	if withAuth {
-		c.rateLimiter.Wait()
-	}
+// makeRequest hace un GET autenticado a la API de Riot para una plataforma o región
+func (c *Client) makeRequest(ctx context.Context, route, path string) (*http.Response, error) {
+	return c.makeRequestWithAuth(ctx, "GET", c.apiURL(route, path), route, endpointTemplate(path), true)
+}
 
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
-	}
+// makeDDragonRequest hace un GET sin autenticación a Data Dragon
+func (c *Client) makeDDragonRequest(ctx context.Context, path string) (*http.Response, error) {
+	return c.makeRequestWithAuth(ctx, "GET", c.DataDragonURL(path), "", "", false)
+}
 
-	// Agregar header de autenticación solo si se requiere
-	if withAuth {
-		req.Header.Set("X-Riot-Token", c.apiKey)
-		fmt.Printf("[RIOT-DEBUG] Making authenticated request to: %s\n", url)
-		fmt.Printf("[RIOT-DEBUG] X-Riot-Token: %s\n", c.apiKey)
-	} else {
-		fmt.Printf("[RIOT-DEBUG] Making unauthenticated request to: %s\n", url)
-	}
-	req.Header.Set("User-Agent", "HypeAtlas-API/1.0")
+// makeRequestWithAuth hace una petición HTTP con control de autenticación.
+// Las peticiones autenticadas pasan por el rate limiter por (región, método) y
+// reintentan 429/503 hasta maxRetries veces respetando Retry-After.
+func (c *Client) makeRequestWithAuth(ctx context.Context, method, url, region, endpoint string, withAuth bool) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		// Aplicar rate limiting solo para requests autenticados; endpoint ya es una
+		// plantilla (endpointTemplate), apta como etiqueta de métricas
+		metricEndpoint := "ddragon"
+		if withAuth {
+			metricEndpoint = endpoint
+			waitStarted := time.Now()
+			if err := c.rateLimiter.Wait(ctx, region, endpoint); err != nil {
+				return nil, err
+			}
+			metrics.ObserveRateLimitWait("riot", endpoint, time.Since(waitStarted))
+		}
 
-	resp, err := c.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("error making request: %w", err)
-	}
+		req, err := http.NewRequestWithContext(ctx, method, url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error creating request: %w", err)
+		}
 
-	fmt.Printf("[RIOT-DEBUG] Response status: %d for URL: %s\n", resp.StatusCode, url)
+		// Agregar header de autenticación solo si se requiere
+		if withAuth {
+			req.Header.Set("X-Riot-Token", c.apiKey)
+		}
+		req.Header.Set("User-Agent", "HypeAtlas-API/1.0")
+
+		sent := time.Now()
+		resp, err := c.client.Do(req)
+		if err != nil {
+			metrics.ObserveProviderCall("riot", metricEndpoint, 0, time.Since(sent))
+			return nil, fmt.Errorf("error making request: %s", c.redact(err.Error()))
+		}
+		metrics.ObserveProviderCall("riot", metricEndpoint, resp.StatusCode, time.Since(sent))
+
+		log.Ctx(ctx).Debug().Str("url", url).Int("status", resp.StatusCode).Int("attempt", attempt).Msg("riot request")
+
+		if !withAuth {
+			return resp, nil
+		}
+		c.rateLimiter.Update(region, endpoint, resp)
 
-	// Manejar rate limiting (429) solo para requests autenticados
-	if withAuth && resp.StatusCode == 429 {
+		retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+		if !retryable || attempt >= c.maxRetries {
+			return resp, nil
+		}
 		resp.Body.Close()
 
-		// Obtener tiempo de espera del header Retry-After
-		retryAfter := resp.Header.Get("Retry-After")
-		if retryAfter != "" {
-			if seconds, err := strconv.Atoi(retryAfter); err == nil {
-				time.Sleep(time.Duration(seconds) * time.Second)
-				// Reintentar la petición
-				return c.makeRequestWithAuth(method, url, body, withAuth)
+		// En 429 el limiter ya quedó bloqueado hasta Retry-After; en 503 esperamos aquí
+		if resp.StatusCode == http.StatusServiceUnavailable {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-c.rateLimiter.clock.After(RetryAfter(resp)):
 			}
 		}
-
-		// Si no hay Retry-After, esperar 1 segundo por defecto
-		time.Sleep(time.Second)
-		return c.makeRequestWithAuth(method, url, body, withAuth)
+		log.Ctx(ctx).Warn().Str("url", url).Int("status", resp.StatusCode).Int("attempt", attempt+1).Msg("riot request retry")
 	}
+}
 
-	return resp, nil
+// redact elimina la API key de textos que pueden terminar en logs o errores
+func (c *Client) redact(s string) string {
+	if c.apiKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, c.apiKey, "RGAPI-***")
 }
 
 // VersionResponse respuesta de la API de versiones
@@ -149,10 +204,11 @@ type VersionResponse []string
 
 // ChampionData datos de un campeón
 type ChampionData struct {
-	ID    string `json:"id"`
-	Key   string `json:"key"`
-	Name  string `json:"name"`
-	Title string `json:"title"`
+	ID    string   `json:"id"`
+	Key   string   `json:"key"`
+	Name  string   `json:"name"`
+	Title string   `json:"title"`
+	Tags  []string `json:"tags"` // Fighter, Tank, Mage, Assassin, Marksman, Support
 }
 
 // ChampionsResponse respuesta de la API de campeones
@@ -161,10 +217,10 @@ type ChampionsResponse struct {
 }
 
 // GetLatestVersion obtiene la versión más reciente del juego desde Data Dragon
-func (c *Client) GetLatestVersion() (string, error) {
-	url := "https://ddragon.leagueoflegends.com/api/versions.json"
+func (c *Client) GetLatestVersion(ctx context.Context) (string, error) {
+	path := "/api/versions.json"
 
-	resp, err := c.makeRequestWithoutAuth("GET", url, nil)
+	resp, err := c.makeDDragonRequest(ctx, path)
 	if err != nil {
 		return "", fmt.Errorf("error making request: %w", err)
 	}
@@ -192,10 +248,10 @@ func (c *Client) GetLatestVersion() (string, error) {
 }
 
 // GetChampions obtiene la lista de campeones para una versión específica desde Data Dragon
-func (c *Client) GetChampions(version string) (*ChampionsResponse, error) {
-	url := fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/data/en_US/champion.json", version)
+func (c *Client) GetChampions(ctx context.Context, version string) (*ChampionsResponse, error) {
+	path := fmt.Sprintf("/cdn/%s/data/en_US/champion.json", version)
 
-	resp, err := c.makeRequestWithoutAuth("GET", url, nil)
+	resp, err := c.makeDDragonRequest(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -219,10 +275,10 @@ func (c *Client) GetChampions(version string) (*ChampionsResponse, error) {
 }
 
 // GetChampion obtiene datos específicos de un campeón desde Data Dragon
-func (c *Client) GetChampion(version, championID string) (*ChampionData, error) {
-	url := fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/data/en_US/champion/%s.json", version, championID)
+func (c *Client) GetChampion(ctx context.Context, version, championID string) (*ChampionData, error) {
+	path := fmt.Sprintf("/cdn/%s/data/en_US/champion/%s.json", version, championID)
 
-	resp, err := c.makeRequestWithoutAuth("GET", url, nil)
+	resp, err := c.makeDDragonRequest(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -264,10 +320,10 @@ type SummonerData struct {
 }
 
 // GetSummonerByPUUID obtiene información de un summoner por PUUID
-func (c *Client) GetSummonerByPUUID(platform, puuid string) (*SummonerData, error) {
-	url := fmt.Sprintf("https://%s.api.riotgames.com/lol/summoner/v4/summoners/by-puuid/%s", platform, puuid)
+func (c *Client) GetSummonerByPUUID(ctx context.Context, platform, puuid string) (*SummonerData, error) {
+	path := fmt.Sprintf("/lol/summoner/v4/summoners/by-puuid/%s", puuid)
 
-	resp, err := c.makeRequest("GET", url, nil)
+	resp, err := c.makeRequest(ctx, platform, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -293,15 +349,11 @@ func (c *Client) GetSummonerByPUUID(platform, puuid string) (*SummonerData, erro
 // MatchIDsResponse lista de IDs de partidas
 type MatchIDsResponse []string
 
-// GetMatchIDsByPUUID obtiene lista de IDs de partidas recientes de un jugador
-func (c *Client) GetMatchIDsByPUUID(region, puuid string, count int) (MatchIDsResponse, error) {
-	if count <= 0 || count > 100 {
-		count = 20
-	}
+// GetSummonerBySummonerID obtiene información de un summoner por su summonerId encriptado
+func (c *Client) GetSummonerBySummonerID(ctx context.Context, platform, summonerID string) (*SummonerData, error) {
+	path := fmt.Sprintf("/lol/summoner/v4/summoners/%s", summonerID)
 
-	url := fmt.Sprintf("https://%s.api.riotgames.com/lol/match/v5/matches/by-puuid/%s/ids?count=%d", region, puuid, count)
-
-	resp, err := c.makeRequest("GET", url, nil)
+	resp, err := c.makeRequest(ctx, platform, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -316,25 +368,27 @@ func (c *Client) GetMatchIDsByPUUID(region, puuid string, count int) (MatchIDsRe
 		return nil, fmt.Errorf("error reading response: %w", err)
 	}
 
-	var matchIDs MatchIDsResponse
-	if err := json.Unmarshal(body, &matchIDs); err != nil {
+	var summoner SummonerData
+	if err := json.Unmarshal(body, &summoner); err != nil {
 		return nil, fmt.Errorf("error parsing response: %w", err)
 	}
 
-	return matchIDs, nil
+	return &summoner, nil
 }
 
-// VALContentResponse respuesta de la API de contenido de Valorant
-type VALContentResponse struct {
-	Version string `json:"version"`
-	// Aquí irían más campos según la documentación
-}
+// GetMatchIDsByPUUID obtiene lista de IDs de partidas recientes de un jugador.
+// queue = 0 no filtra por cola.
+func (c *Client) GetMatchIDsByPUUID(ctx context.Context, region, puuid string, queue, count int) (MatchIDsResponse, error) {
+	if count <= 0 || count > 100 {
+		count = 20
+	}
 
-// GetVALContent obtiene el contenido de Valorant (acts, seasons, etc.)
-func (c *Client) GetVALContent() (*VALContentResponse, error) {
-	url := "https://na.api.riotgames.com/val/content/v1/contents"
+	path := fmt.Sprintf("/lol/match/v5/matches/by-puuid/%s/ids?count=%d", puuid, count)
+	if queue > 0 {
+		path += fmt.Sprintf("&queue=%d", queue)
+	}
 
-	resp, err := c.makeRequest("GET", url, nil)
+	resp, err := c.makeRequest(ctx, region, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -349,12 +403,12 @@ func (c *Client) GetVALContent() (*VALContentResponse, error) {
 		return nil, fmt.Errorf("error reading response: %w", err)
 	}
 
-	var content VALContentResponse
-	if err := json.Unmarshal(body, &content); err != nil {
+	var matchIDs MatchIDsResponse
+	if err := json.Unmarshal(body, &matchIDs); err != nil {
 		return nil, fmt.Errorf("error parsing response: %w", err)
 	}
 
-	return &content, nil
+	return matchIDs, nil
 }
 
 // ChampionRotationResponse respuesta de la API de rotación de campeones
@@ -365,10 +419,10 @@ type ChampionRotationResponse struct {
 }
 
 // GetChampionRotation obtiene la rotación semanal de campeones gratuitos
-func (c *Client) GetChampionRotation(platform string) (*ChampionRotationResponse, error) {
-	url := fmt.Sprintf("https://%s.api.riotgames.com/lol/platform/v3/champion-rotations", platform)
+func (c *Client) GetChampionRotation(ctx context.Context, platform string) (*ChampionRotationResponse, error) {
+	path := "/lol/platform/v3/champion-rotations"
 
-	resp, err := c.makeRequest("GET", url, nil)
+	resp, err := c.makeRequest(ctx, platform, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -393,6 +447,7 @@ func (c *Client) GetChampionRotation(platform string) (*ChampionRotationResponse
 
 // LeagueEntry respuesta para entradas de liga
 type LeagueEntry struct {
+	PUUID        string `json:"puuid"`
 	SummonerID   string `json:"summonerId"`
 	SummonerName string `json:"summonerName"`
 	LeaguePoints int    `json:"leaguePoints"`
@@ -409,11 +464,10 @@ type LeagueEntry struct {
 type LeagueEntriesResponse []LeagueEntry
 
 // GetLeagueEntries obtiene entradas de liga por división y tier
-func (c *Client) GetLeagueEntries(platform, queue, tier, division string, page int) (LeagueEntriesResponse, error) {
-	url := fmt.Sprintf("https://%s.api.riotgames.com/lol/league/v4/entries/%s/%s/%s?page=%d",
-		platform, queue, tier, division, page)
+func (c *Client) GetLeagueEntries(ctx context.Context, platform, queue, tier, division string, page int) (LeagueEntriesResponse, error) {
+	path := fmt.Sprintf("/lol/league/v4/entries/%s/%s/%s?page=%d", queue, tier, division, page)
 
-	resp, err := c.makeRequest("GET", url, nil)
+	resp, err := c.makeRequest(ctx, platform, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -437,10 +491,64 @@ func (c *Client) GetLeagueEntries(platform, queue, tier, division string, page i
 }
 
 // GetChallengerLeague obtiene la liga Challenger para una cola específica
-func (c *Client) GetChallengerLeague(platform, queue string) (*LeagueData, error) {
-	url := fmt.Sprintf("https://%s.api.riotgames.com/lol/league/v4/challengerleagues/by-queue/%s", platform, queue)
+func (c *Client) GetChallengerLeague(ctx context.Context, platform, queue string) (*LeagueData, error) {
+	path := fmt.Sprintf("/lol/league/v4/challengerleagues/by-queue/%s", queue)
+
+	resp, err := c.makeRequest(ctx, platform, path)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	var league LeagueData
+	if err := json.Unmarshal(body, &league); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return &league, nil
+}
 
-	resp, err := c.makeRequest("GET", url, nil)
+// GetGrandmasterLeague obtiene la liga Grandmaster para una cola específica
+func (c *Client) GetGrandmasterLeague(ctx context.Context, platform, queue string) (*LeagueData, error) {
+	path := fmt.Sprintf("/lol/league/v4/grandmasterleagues/by-queue/%s", queue)
+
+	resp, err := c.makeRequest(ctx, platform, path)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	var league LeagueData
+	if err := json.Unmarshal(body, &league); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return &league, nil
+}
+
+// GetMasterLeague obtiene la liga Master para una cola específica
+func (c *Client) GetMasterLeague(ctx context.Context, platform, queue string) (*LeagueData, error) {
+	path := fmt.Sprintf("/lol/league/v4/masterleagues/by-queue/%s", queue)
+
+	resp, err := c.makeRequest(ctx, platform, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -489,10 +597,10 @@ type ChampionMastery struct {
 type ChampionMasteriesResponse []ChampionMastery
 
 // GetChampionMasteries obtiene todas las maestrías de campeón de un summoner
-func (c *Client) GetChampionMasteries(platform, puuid string) (ChampionMasteriesResponse, error) {
-	url := fmt.Sprintf("https://%s.api.riotgames.com/lol/champion-mastery/v4/champion-masteries/by-puuid/%s", platform, puuid)
+func (c *Client) GetChampionMasteries(ctx context.Context, platform, puuid string) (ChampionMasteriesResponse, error) {
+	path := fmt.Sprintf("/lol/champion-mastery/v4/champion-masteries/by-puuid/%s", puuid)
 
-	resp, err := c.makeRequest("GET", url, nil)
+	resp, err := c.makeRequest(ctx, platform, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -516,17 +624,17 @@ func (c *Client) GetChampionMasteries(platform, puuid string) (ChampionMasteries
 }
 
 // GetAllLeagues obtiene todas las ligas disponibles para una plataforma
-func (c *Client) GetAllLeagues(platform string) ([]string, error) {
+func (c *Client) GetAllLeagues(ctx context.Context, platform string) ([]string, error) {
 	// Riot Games no tiene un endpoint directo para obtener todas las ligas
 	// Pero podemos obtener las ligas Challenger para las colas principales
 	queues := []string{"RANKED_SOLO_5x5", "RANKED_FLEX_SR", "RANKED_FLEX_TT"}
 
 	var leagues []string
 	for _, queue := range queues {
-		league, err := c.GetChallengerLeague(platform, queue)
+		league, err := c.GetChallengerLeague(ctx, platform, queue)
 		if err != nil {
 			// Log error but continue with other queues
-			fmt.Printf("[RIOT-DEBUG] Error getting league for queue %s: %v\n", queue, err)
+			log.Ctx(ctx).Warn().Err(err).Str("queue", queue).Msg("riot get league failed")
 			continue
 		}
 
@@ -547,7 +655,7 @@ func (c *Client) GetAllLeagues(platform string) ([]string, error) {
 }
 
 // GetGames obtiene la lista de juegos disponibles de Riot Games
-func (c *Client) GetGames() ([]string, error) {
+func (c *Client) GetGames(ctx context.Context) ([]string, error) {
 	// Lista de juegos principales de Riot Games
 	// Esta información podría venir de una API o ser hardcodeada
 	games := []string{
@@ -562,7 +670,7 @@ func (c *Client) GetGames() ([]string, error) {
 }
 
 // GetRegions obtiene la lista de regiones disponibles para League of Legends
-func (c *Client) GetRegions() ([]string, error) {
+func (c *Client) GetRegions(ctx context.Context) ([]string, error) {
 	// Lista de regiones oficiales de League of Legends
 	regions := []string{
 		"BR1", "EUN1", "EUW1", "JP1", "KR", "LA1", "LA2",
@@ -583,18 +691,18 @@ func NewChampionStatsService(client *Client) *ChampionStatsService {
 }
 
 // GetChampionStats obtiene estadísticas completas de campeones
-func (s *ChampionStatsService) GetChampionStats(version string) (map[string]interface{}, error) {
+func (s *ChampionStatsService) GetChampionStats(ctx context.Context, version string) (map[string]interface{}, error) {
 	// Obtener datos básicos de campeones
-	champions, err := s.client.GetChampions(version)
+	champions, err := s.client.GetChampions(ctx, version)
 	if err != nil {
 		return nil, fmt.Errorf("error getting champions data: %w", err)
 	}
 
 	// Obtener rotación gratuita
-	rotation, err := s.getChampionRotation()
+	rotation, err := s.getChampionRotation(ctx)
 	if err != nil {
 		// Log but continue
-		fmt.Printf("[RIOT-DEBUG] Error getting champion rotation: %v\n", err)
+		log.Ctx(ctx).Warn().Err(err).Msg("riot get champion rotation failed")
 	}
 
 	// Construir respuesta estructurada
@@ -623,8 +731,8 @@ func (s *ChampionStatsService) buildChampionSummary(champions *ChampionsResponse
 }
 
 // getChampionRotation obtiene la rotación gratuita de forma segura
-func (s *ChampionStatsService) getChampionRotation() (*ChampionRotationResponse, error) {
-	return s.client.GetChampionRotation("na1")
+func (s *ChampionStatsService) getChampionRotation(ctx context.Context) (*ChampionRotationResponse, error) {
+	return s.client.GetChampionRotation(ctx, "na1")
 }
 
 // buildFreeRotationData construye los datos de rotación gratuita
@@ -687,20 +795,20 @@ func (s *ChampionStatsService) getTopChampions(champions *ChampionsResponse, lim
 }
 
 // GetChampionStats mantiene compatibilidad hacia atrás
-func (c *Client) GetChampionStats(version string) (map[string]interface{}, error) {
+func (c *Client) GetChampionStats(ctx context.Context, version string) (map[string]interface{}, error) {
 	service := NewChampionStatsService(c)
-	return service.GetChampionStats(version)
+	return service.GetChampionStats(ctx, version)
 }
 
 // GetPatchChanges obtiene cambios de campeones entre parches desde Data Dragon
-func (c *Client) GetPatchChanges(fromVersion, toVersion string) (map[string]interface{}, error) {
+func (c *Client) GetPatchChanges(ctx context.Context, fromVersion, toVersion string) (map[string]interface{}, error) {
 	// Obtener datos de campeones para ambas versiones
-	fromChampions, err := c.GetChampions(fromVersion)
+	fromChampions, err := c.GetChampions(ctx, fromVersion)
 	if err != nil {
 		return nil, fmt.Errorf("error getting champions for version %s: %w", fromVersion, err)
 	}
 
-	toChampions, err := c.GetChampions(toVersion)
+	toChampions, err := c.GetChampions(ctx, toVersion)
 	if err != nil {
 		return nil, fmt.Errorf("error getting champions for version %s: %w", toVersion, err)
 	}
@@ -770,10 +878,10 @@ func (c *Client) GetPatchChanges(fromVersion, toVersion string) (map[string]inte
 }
 
 // GetItems obtiene datos de items para una versión específica desde Data Dragon
-func (c *Client) GetItems(version string) (map[string]interface{}, error) {
-	url := fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/data/en_US/item.json", version)
+func (c *Client) GetItems(ctx context.Context, version string) (map[string]interface{}, error) {
+	path := fmt.Sprintf("/cdn/%s/data/en_US/item.json", version)
 
-	resp, err := c.makeRequestWithoutAuth("GET", url, nil)
+	resp, err := c.makeDDragonRequest(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -797,10 +905,10 @@ func (c *Client) GetItems(version string) (map[string]interface{}, error) {
 }
 
 // GetRunes obtiene datos de runas para una versión específica desde Data Dragon
-func (c *Client) GetRunes(version string) (map[string]interface{}, error) {
-	url := fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/data/en_US/runesReforged.json", version)
+func (c *Client) GetRunes(ctx context.Context, version string) (map[string]interface{}, error) {
+	path := fmt.Sprintf("/cdn/%s/data/en_US/runesReforged.json", version)
 
-	resp, err := c.makeRequestWithoutAuth("GET", url, nil)
+	resp, err := c.makeDDragonRequest(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -827,10 +935,10 @@ func (c *Client) GetRunes(version string) (map[string]interface{}, error) {
 }
 
 // GetSummonerSpells obtiene datos de summoner spells para una versión específica desde Data Dragon
-func (c *Client) GetSummonerSpells(version string) (map[string]interface{}, error) {
-	url := fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/data/en_US/summoner.json", version)
+func (c *Client) GetSummonerSpells(ctx context.Context, version string) (map[string]interface{}, error) {
+	path := fmt.Sprintf("/cdn/%s/data/en_US/summoner.json", version)
 
-	resp, err := c.makeRequestWithoutAuth("GET", url, nil)
+	resp, err := c.makeDDragonRequest(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -854,10 +962,10 @@ func (c *Client) GetSummonerSpells(version string) (map[string]interface{}, erro
 }
 
 // GetChampionDetails obtiene detalles completos de un campeón específico desde Data Dragon
-func (c *Client) GetChampionDetails(version, championID string) (map[string]interface{}, error) {
-	url := fmt.Sprintf("https://ddragon.leagueoflegends.com/cdn/%s/data/en_US/champion/%s.json", version, championID)
+func (c *Client) GetChampionDetails(ctx context.Context, version, championID string) (map[string]interface{}, error) {
+	path := fmt.Sprintf("/cdn/%s/data/en_US/champion/%s.json", version, championID)
 
-	resp, err := c.makeRequestWithoutAuth("GET", url, nil)
+	resp, err := c.makeDDragonRequest(ctx, path)
 	if err != nil {
 		return nil, fmt.Errorf("error making request: %w", err)
 	}
@@ -881,14 +989,14 @@ func (c *Client) GetChampionDetails(version, championID string) (map[string]inte
 }
 
 // GetPatchNotes obtiene información de cambios entre parches desde Data Dragon
-func (c *Client) GetPatchNotes(fromVersion, toVersion string) (map[string]interface{}, error) {
+func (c *Client) GetPatchNotes(ctx context.Context, fromVersion, toVersion string) (map[string]interface{}, error) {
 	// Obtener campeones de ambas versiones
-	fromChampions, err := c.GetChampions(fromVersion)
+	fromChampions, err := c.GetChampions(ctx, fromVersion)
 	if err != nil {
 		return nil, fmt.Errorf("error getting champions for version %s: %w", fromVersion, err)
 	}
 
-	toChampions, err := c.GetChampions(toVersion)
+	toChampions, err := c.GetChampions(ctx, toVersion)
 	if err != nil {
 		return nil, fmt.Errorf("error getting champions for version %s: %w", toVersion, err)
 	}
@@ -949,136 +1057,3 @@ func (c *Client) GetPatchNotes(fromVersion, toVersion string) (map[string]interf
 
 	return changes, nil
 }
-
-// GetProfessionalLeagues obtiene información sobre ligas profesionales de League of Legends
-func (c *Client) GetProfessionalLeagues() (map[string]interface{}, error) {
-	// Lista de ligas profesionales principales
-	leagues := map[string]interface{}{
-		"LEC": map[string]interface{}{
-			"name":           "League of Legends European Championship",
-			"region":         "Europe",
-			"platform":       "EUW1",
-			"seasons":        []string{"Spring", "Summer"},
-			"current_season": "Summer 2024",
-			"teams":          10,
-			"description":    "La liga europea más prestigiosa de League of Legends",
-		},
-		"LCK": map[string]interface{}{
-			"name":           "League of Legends Champions Korea",
-			"region":         "Korea",
-			"platform":       "KR",
-			"seasons":        []string{"Spring", "Summer"},
-			"current_season": "Summer 2024",
-			"teams":          10,
-			"description":    "La liga coreana, considerada la más competitiva del mundo",
-		},
-		"LPL": map[string]interface{}{
-			"name":           "League of Legends Pro League",
-			"region":         "China",
-			"platform":       "CN1",
-			"seasons":        []string{"Spring", "Summer"},
-			"current_season": "Summer 2024",
-			"teams":          17,
-			"description":    "La liga china con el mayor número de equipos",
-		},
-		"LTA": map[string]interface{}{
-			"name":           "Liga Latinoamérica",
-			"region":         "Latin America",
-			"platform":       "LA1/LA2",
-			"seasons":        []string{"Opening", "Closing"},
-			"current_season": "Closing 2024",
-			"teams":          8,
-			"description":    "La liga latinoamericana de League of Legends",
-		},
-		"LCS": map[string]interface{}{
-			"name":           "League Championship Series",
-			"region":         "North America",
-			"platform":       "NA1",
-			"seasons":        []string{"Spring", "Summer"},
-			"current_season": "Summer 2024",
-			"teams":          10,
-			"description":    "La liga norteamericana de League of Legends",
-		},
-		"VCS": map[string]interface{}{
-			"name":           "Vietnam Championship Series",
-			"region":         "Vietnam",
-			"platform":       "VN2",
-			"seasons":        []string{"Spring", "Summer"},
-			"current_season": "Summer 2024",
-			"teams":          8,
-			"description":    "La liga vietnamita de League of Legends",
-		},
-		"PCS": map[string]interface{}{
-			"name":           "Pacific Championship Series",
-			"region":         "Pacific",
-			"platform":       "TW2/SG2/PH2",
-			"seasons":        []string{"Spring", "Summer"},
-			"current_season": "Summer 2024",
-			"teams":          8,
-			"description":    "La liga del Pacífico Asiático",
-		},
-	}
-
-	return map[string]interface{}{
-		"total_leagues": len(leagues),
-		"leagues":       leagues,
-		"note":          "Professional league data is curated and updated manually",
-		"last_updated":  "2024-09-14",
-	}, nil
-}
-
-// GetLeagueChampions obtiene estadísticas de campeones más usados en una liga específica
-func (c *Client) GetLeagueChampions(league string) (map[string]interface{}, error) {
-	// Datos de ejemplo basados en estadísticas reales aproximadas
-	// En una implementación real, esto vendría de análisis de partidos
-	leagueStats := map[string]interface{}{
-		"league":               league,
-		"season":               "Summer 2024",
-		"total_games_analyzed": 1250,
-		"most_picked_champions": []map[string]interface{}{
-			{
-				"name":      "Yuumi",
-				"pick_rate": 15.2,
-				"win_rate":  52.1,
-				"ban_rate":  8.5,
-				"positions": []string{"Support"},
-			},
-			{
-				"name":      "Jax",
-				"pick_rate": 12.8,
-				"win_rate":  48.9,
-				"ban_rate":  25.3,
-				"positions": []string{"Top"},
-			},
-			{
-				"name":      "Ahri",
-				"pick_rate": 11.5,
-				"win_rate":  51.2,
-				"ban_rate":  12.1,
-				"positions": []string{"Mid"},
-			},
-		},
-		"most_banned_champions": []map[string]interface{}{
-			{
-				"name":      "Jax",
-				"ban_rate":  25.3,
-				"pick_rate": 12.8,
-			},
-			{
-				"name":      "Yuumi",
-				"ban_rate":  18.7,
-				"pick_rate": 15.2,
-			},
-		},
-		"meta_compositions": []map[string]interface{}{
-			{
-				"name":      "Control Comp",
-				"pick_rate": 23.5,
-				"win_rate":  54.2,
-				"champions": []string{"Yuumi", "Leona", "Jax", "Ahri", "Jinx"},
-			},
-		},
-	}
-
-	return leagueStats, nil
-}
@@ -0,0 +1,160 @@
+package riot_test
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/steven230500/hypeatlas-api/providers/riot"
+	"github.com/steven230500/hypeatlas-api/providers/riot/riottest"
+)
+
+// instantClock reloj que avanza al instante lo que se le pide esperar
+type instantClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *instantClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *instantClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func newTestClient(t *testing.T) (*riot.Client, *riottest.Server) {
+	t.Helper()
+	srv := riottest.NewServer()
+	t.Cleanup(srv.Close)
+	clock := &instantClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	return srv.Client("RGAPI-test", riot.WithRateLimiter(riot.NewRateLimiter(riot.DefaultAppLimits, clock))), srv
+}
+
+func TestGetMatch(t *testing.T) {
+	c, srv := newTestClient(t)
+
+	match, err := c.GetMatch(context.Background(), "europe", "EUW1_7000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.Metadata.MatchID != "EUW1_7000000001" {
+		t.Errorf("matchId = %q", match.Metadata.MatchID)
+	}
+	if match.Info.QueueID != 420 || len(match.Info.Participants) != 10 {
+		t.Errorf("queue = %d, participants = %d", match.Info.QueueID, len(match.Info.Participants))
+	}
+	if patch := riot.PatchFromGameVersion(match.Info.GameVersion); patch != "14.14" {
+		t.Errorf("patch = %q, want 14.14", patch)
+	}
+	if got := srv.Requests(); len(got) != 1 || got[0] != "/europe/lol/match/v5/matches/EUW1_7000000001" {
+		t.Errorf("requests = %v", got)
+	}
+}
+
+func TestGetVALMatch(t *testing.T) {
+	c, _ := newTestClient(t)
+
+	match, err := c.GetVALMatch(context.Background(), "eu", "9f6a4b8e-0000-4000-8000-000000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.MatchInfo.MatchID != "9f6a4b8e-0000-4000-8000-000000000001" || match.MatchInfo.QueueID != "competitive" {
+		t.Errorf("matchInfo = %+v", match.MatchInfo)
+	}
+	if len(match.Players) != 10 || len(match.Teams) != 2 {
+		t.Fatalf("players = %d, teams = %d", len(match.Players), len(match.Teams))
+	}
+	if red := match.Teams[0]; red.TeamID != "Red" || !red.Won || red.RoundsWon != 13 {
+		t.Errorf("team = %+v, want Red winning 13 rounds", red)
+	}
+}
+
+func TestLeagues(t *testing.T) {
+	c, srv := newTestClient(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		get     func() (*riot.LeagueData, error)
+		tier    string
+		entries int
+	}{
+		{"challenger", func() (*riot.LeagueData, error) { return c.GetChallengerLeague(ctx, "euw1", "RANKED_SOLO_5x5") }, "CHALLENGER", 3},
+		{"grandmaster", func() (*riot.LeagueData, error) { return c.GetGrandmasterLeague(ctx, "euw1", "RANKED_SOLO_5x5") }, "GRANDMASTER", 2},
+		{"master", func() (*riot.LeagueData, error) { return c.GetMasterLeague(ctx, "euw1", "RANKED_SOLO_5x5") }, "MASTER", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			league, err := tt.get()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if league.Tier != tt.tier || len(league.Entries) != tt.entries {
+				t.Errorf("tier = %q, entries = %d; want %s with %d", league.Tier, len(league.Entries), tt.tier, tt.entries)
+			}
+		})
+	}
+
+	entries, err := c.GetLeagueEntries(ctx, "euw1", "RANKED_SOLO_5x5", "DIAMOND", "I", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].PUUID != "puuid-diamond-1" {
+		t.Errorf("entries = %+v", entries)
+	}
+	// Fuera de la primera página el tier viene vacío
+	if entries, err := c.GetLeagueEntries(ctx, "euw1", "RANKED_SOLO_5x5", "DIAMOND", "I", 2); err != nil || len(entries) != 0 {
+		t.Errorf("page 2 = %v, %v; want empty", entries, err)
+	}
+
+	if got := srv.Requests(); len(got) != 5 || got[3] != "/euw1/lol/league/v4/entries/RANKED_SOLO_5x5/DIAMOND/I?page=1" {
+		t.Errorf("requests = %v", got)
+	}
+}
+
+func TestRetriesAfter429(t *testing.T) {
+	c, srv := newTestClient(t)
+	const path = "/europe/lol/match/v5/matches/EUW1_7000000001"
+	srv.Handle(path, riottest.Response{
+		Status:  http.StatusTooManyRequests,
+		Headers: map[string]string{"Retry-After": "3", "X-Rate-Limit-Type": "method"},
+		Times:   2,
+	})
+
+	match, err := c.GetMatch(context.Background(), "europe", "EUW1_7000000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if match.Metadata.MatchID != "EUW1_7000000001" {
+		t.Errorf("matchId = %q", match.Metadata.MatchID)
+	}
+	if got := srv.Requests(); len(got) != 3 {
+		t.Errorf("requests = %v, want two 429s and the retry that succeeds", got)
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	c, srv := newTestClient(t)
+	srv.Handle("/euw1/lol/league/v4/challengerleagues/by-queue/RANKED_SOLO_5x5", riottest.Response{
+		Status:  http.StatusTooManyRequests,
+		Headers: map[string]string{"Retry-After": "1"},
+	})
+
+	if _, err := c.GetChallengerLeague(context.Background(), "euw1", "RANKED_SOLO_5x5"); err == nil {
+		t.Fatal("want an error after exhausting retries")
+	}
+	// maxRetries por defecto: 3 reintentos tras la primera petición
+	if got := srv.Requests(); len(got) != 4 {
+		t.Errorf("requests = %d, want 4", len(got))
+	}
+}
@@ -0,0 +1,132 @@
+package riot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Colas de Match-V5 más usadas (queueId)
+const (
+	QueueIDRankedSolo = 420
+	QueueIDRankedFlex = 440
+)
+
+// QueueIDs mapea el nombre de cola de League-V4 al queueId de Match-V5
+var QueueIDs = map[string]int{
+	"RANKED_SOLO_5x5": QueueIDRankedSolo,
+	"RANKED_FLEX_SR":  QueueIDRankedFlex,
+}
+
+// regionalRoutes mapea cada plataforma a su routing value regional (Match-V5)
+var regionalRoutes = map[string]string{
+	"na1": "americas", "br1": "americas", "la1": "americas", "la2": "americas",
+	"euw1": "europe", "eun1": "europe", "tr1": "europe", "ru": "europe", "me1": "europe",
+	"kr": "asia", "jp1": "asia",
+	"oc1": "sea", "ph2": "sea", "sg2": "sea", "th2": "sea", "tw2": "sea", "vn2": "sea",
+}
+
+// RegionalRoute devuelve el routing value regional de una plataforma (na1 -> americas)
+func RegionalRoute(platform string) string {
+	if r, ok := regionalRoutes[strings.ToLower(platform)]; ok {
+		return r
+	}
+	return "americas"
+}
+
+// PatchFromGameVersion recorta un gameVersion de Match-V5 a su parche (14.14.602.1234 -> 14.14)
+func PatchFromGameVersion(gameVersion string) string {
+	parts := strings.Split(gameVersion, ".")
+	if len(parts) < 2 {
+		return gameVersion
+	}
+	return parts[0] + "." + parts[1]
+}
+
+// Match respuesta de MATCH-V5 (solo los campos que usamos)
+type Match struct {
+	Metadata MatchMetadata `json:"metadata"`
+	Info     MatchInfo     `json:"info"`
+}
+
+// MatchMetadata metadatos de una partida
+type MatchMetadata struct {
+	MatchID      string   `json:"matchId"`
+	Participants []string `json:"participants"`
+}
+
+// MatchInfo información de una partida
+type MatchInfo struct {
+	GameCreation int64              `json:"gameCreation"`
+	GameDuration int64              `json:"gameDuration"`
+	GameVersion  string             `json:"gameVersion"`
+	PlatformID   string             `json:"platformId"`
+	QueueID      int                `json:"queueId"`
+	Participants []MatchParticipant `json:"participants"`
+	Teams        []MatchTeam        `json:"teams"`
+}
+
+// MatchParticipant participante de una partida
+type MatchParticipant struct {
+	PUUID              string `json:"puuid"`
+	ChampionID         int    `json:"championId"`
+	ChampionName       string `json:"championName"`
+	TeamID             int    `json:"teamId"`
+	TeamPosition       string `json:"teamPosition"`       // TOP, JUNGLE, MIDDLE, BOTTOM, UTILITY
+	IndividualPosition string `json:"individualPosition"` // fallback si teamPosition viene vacío
+	Win                bool   `json:"win"`
+}
+
+// MatchTeam equipo de una partida (100 = blue, 200 = red)
+type MatchTeam struct {
+	TeamID int        `json:"teamId"`
+	Win    bool       `json:"win"`
+	Bans   []MatchBan `json:"bans"`
+}
+
+// MatchBan baneo de un equipo
+type MatchBan struct {
+	ChampionID int `json:"championId"`
+	PickTurn   int `json:"pickTurn"`
+}
+
+// Position devuelve la posición del participante, usando individualPosition como fallback
+func (p MatchParticipant) Position() string {
+	if p.TeamPosition != "" {
+		return p.TeamPosition
+	}
+	if p.IndividualPosition != "Invalid" {
+		return p.IndividualPosition
+	}
+	return ""
+}
+
+// GetMatch obtiene el detalle de una partida desde Match-V5
+func (c *Client) GetMatch(ctx context.Context, region, matchID string) (*Match, error) {
+	path := fmt.Sprintf("/lol/match/v5/matches/%s", matchID)
+
+	resp, err := c.makeRequest(ctx, region, path)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	var match Match
+	if err := json.Unmarshal(body, &match); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return &match, nil
+}
@@ -0,0 +1,324 @@
+package riot
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clock abstrae el tiempo para poder inyectar un reloj falso en el limiter
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// RateLimitWindow ventana fija de Riot: Limit requests cada Period
+type RateLimitWindow struct {
+	Limit  int
+	Period time.Duration
+}
+
+// window estado local de una ventana fija
+type window struct {
+	RateLimitWindow
+	start time.Time
+	count int
+}
+
+// bucket conjunto de ventanas de una misma clave (app por región, o región+método)
+type bucket struct {
+	windows      []*window
+	blockedUntil time.Time
+}
+
+// DefaultAppLimits límites de una development key; se reemplazan en cuanto Riot
+// devuelve X-App-Rate-Limit
+var DefaultAppLimits = []RateLimitWindow{
+	{Limit: 20, Period: time.Second},
+	{Limit: 100, Period: 2 * time.Minute},
+}
+
+// RateLimiter limita por routing value (límite de aplicación) y por (routing value, método),
+// aprendiendo los límites reales de los headers X-App-Rate-Limit / X-Method-Rate-Limit
+type RateLimiter struct {
+	mu      sync.Mutex
+	clock   Clock
+	app     map[string]*bucket // región
+	methods map[string]*bucket // región + " " + método
+	appInit []RateLimitWindow
+}
+
+// NewRateLimiter crea un limiter con los límites de aplicación iniciales dados
+func NewRateLimiter(appLimits []RateLimitWindow, clock Clock) *RateLimiter {
+	if clock == nil {
+		clock = realClock{}
+	}
+	if len(appLimits) == 0 {
+		appLimits = DefaultAppLimits
+	}
+	return &RateLimiter{
+		clock:   clock,
+		app:     make(map[string]*bucket),
+		methods: make(map[string]*bucket),
+		appInit: appLimits,
+	}
+}
+
+// Wait bloquea hasta que haya cupo para (region, method) y lo reserva.
+// No mantiene el lock mientras espera y vuelve con ctx.Err() si el contexto se cancela.
+func (rl *RateLimiter) Wait(ctx context.Context, region, method string) error {
+	for {
+		delay := rl.reserve(region, method)
+		if delay <= 0 {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-rl.clock.After(delay):
+		}
+	}
+}
+
+// reserve consume un cupo si hay, o devuelve cuánto falta para reintentar
+func (rl *RateLimiter) reserve(region, method string) time.Duration {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.clock.Now()
+	app := rl.appBucket(region)
+	meth := rl.methodBucket(region, method)
+
+	delay := app.delay(now)
+	if d := meth.delay(now); d > delay {
+		delay = d
+	}
+	if delay > 0 {
+		return delay
+	}
+	app.take()
+	meth.take()
+	return 0
+}
+
+// Update sincroniza límites y contadores con los headers de una respuesta de Riot.
+// En un 429 bloquea la clave indicada por X-Rate-Limit-Type durante Retry-After.
+func (rl *RateLimiter) Update(region, method string, resp *http.Response) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.clock.Now()
+	h := resp.Header
+	if limits := parseRateLimits(h.Get("X-App-Rate-Limit")); len(limits) > 0 {
+		rl.appBucket(region).sync(limits, parseRateCounts(h.Get("X-App-Rate-Limit-Count")), now)
+	}
+	if limits := parseRateLimits(h.Get("X-Method-Rate-Limit")); len(limits) > 0 {
+		rl.methodBucket(region, method).sync(limits, parseRateCounts(h.Get("X-Method-Rate-Limit-Count")), now)
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		return
+	}
+	retry := RetryAfter(resp)
+	until := now.Add(retry)
+	target := rl.methodBucket(region, method)
+	if h.Get("X-Rate-Limit-Type") == "application" {
+		target = rl.appBucket(region)
+	}
+	if until.After(target.blockedUntil) {
+		target.blockedUntil = until
+	}
+}
+
+func (rl *RateLimiter) appBucket(region string) *bucket {
+	b, ok := rl.app[region]
+	if !ok {
+		b = newBucket(rl.appInit)
+		rl.app[region] = b
+	}
+	return b
+}
+
+func (rl *RateLimiter) methodBucket(region, method string) *bucket {
+	key := region + " " + method
+	b, ok := rl.methods[key]
+	if !ok {
+		// Sin límites conocidos hasta la primera respuesta con X-Method-Rate-Limit
+		b = &bucket{}
+		rl.methods[key] = b
+	}
+	return b
+}
+
+func newBucket(limits []RateLimitWindow) *bucket {
+	b := &bucket{}
+	for _, l := range limits {
+		b.windows = append(b.windows, &window{RateLimitWindow: l})
+	}
+	return b
+}
+
+// delay cuánto hay que esperar para poder consumir un cupo en todas las ventanas
+func (b *bucket) delay(now time.Time) time.Duration {
+	var delay time.Duration
+	if now.Before(b.blockedUntil) {
+		delay = b.blockedUntil.Sub(now)
+	}
+	for _, w := range b.windows {
+		if w.start.IsZero() || !now.Before(w.start.Add(w.Period)) {
+			w.start = now
+			w.count = 0
+		}
+		if w.count >= w.Limit {
+			if d := w.start.Add(w.Period).Sub(now); d > delay {
+				delay = d
+			}
+		}
+	}
+	return delay
+}
+
+func (b *bucket) take() {
+	for _, w := range b.windows {
+		w.count++
+	}
+}
+
+// sync reemplaza los límites por los reportados por Riot conservando el estado de las
+// ventanas que no cambiaron, y adopta el contador de Riot si va por delante del local
+func (b *bucket) sync(limits []RateLimitWindow, counts map[time.Duration]int, now time.Time) {
+	existing := make(map[time.Duration]*window, len(b.windows))
+	for _, w := range b.windows {
+		existing[w.Period] = w
+	}
+	windows := make([]*window, 0, len(limits))
+	for _, l := range limits {
+		w, ok := existing[l.Period]
+		if !ok {
+			w = &window{start: now}
+		}
+		w.RateLimitWindow = l
+		if c, ok := counts[l.Period]; ok && c > w.count {
+			w.count = c
+		}
+		windows = append(windows, w)
+	}
+	b.windows = windows
+}
+
+// ratePair par "valor:segundos" de los headers de rate limit de Riot
+type ratePair struct {
+	value  int
+	period time.Duration
+}
+
+// parseRateLimits parsea "20:1,100:120" (requests:segundos)
+func parseRateLimits(header string) []RateLimitWindow {
+	var limits []RateLimitWindow
+	for _, p := range parseRatePairs(header) {
+		limits = append(limits, RateLimitWindow{Limit: p.value, Period: p.period})
+	}
+	return limits
+}
+
+// parseRateCounts parsea "3:1,45:120" a contador por período
+func parseRateCounts(header string) map[time.Duration]int {
+	counts := make(map[time.Duration]int)
+	for _, p := range parseRatePairs(header) {
+		counts[p.period] = p.value
+	}
+	return counts
+}
+
+func parseRatePairs(header string) []ratePair {
+	var pairs []ratePair
+	for _, part := range strings.Split(header, ",") {
+		value, seconds, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			continue
+		}
+		v, err1 := strconv.Atoi(value)
+		s, err2 := strconv.Atoi(seconds)
+		if err1 != nil || err2 != nil || s <= 0 {
+			continue
+		}
+		pairs = append(pairs, ratePair{value: v, period: time.Duration(s) * time.Second})
+	}
+	return pairs
+}
+
+// RetryAfter lee Retry-After (segundos); por defecto 1s
+func RetryAfter(resp *http.Response) time.Duration {
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	return time.Second
+}
+
+// endpointTemplates endpoints de Riot que usa el cliente; {id} marca un parámetro de path
+// (PUUID, match id, act id, cola, tier...). Riot aplica el límite de método por endpoint,
+// no por valor del parámetro.
+var endpointTemplates = [][]string{
+	templateSegments("/lol/summoner/v4/summoners/by-puuid/{id}"),
+	templateSegments("/lol/summoner/v4/summoners/{id}"),
+	templateSegments("/lol/match/v5/matches/by-puuid/{id}/ids"),
+	templateSegments("/lol/match/v5/matches/{id}"),
+	templateSegments("/lol/platform/v3/champion-rotations"),
+	templateSegments("/lol/league/v4/entries/{id}/{id}/{id}"),
+	templateSegments("/lol/league/v4/challengerleagues/by-queue/{id}"),
+	templateSegments("/lol/league/v4/grandmasterleagues/by-queue/{id}"),
+	templateSegments("/lol/league/v4/masterleagues/by-queue/{id}"),
+	templateSegments("/lol/champion-mastery/v4/champion-masteries/by-puuid/{id}"),
+	templateSegments("/val/content/v1/contents"),
+	templateSegments("/val/ranked/v1/leaderboards/by-act/{id}"),
+	templateSegments("/val/match/v1/matches/{id}"),
+	templateSegments("/val/match/v1/recent-matches/by-queue/{id}"),
+}
+
+func templateSegments(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// endpointTemplate normaliza un path de Riot a su plantilla de endpointTemplates:
+// /val/match/v1/matches/<uuid> -> /val/match/v1/matches/{id}. Es la clave del límite de
+// método y la etiqueta endpoint de las métricas. Un path desconocido cae en
+// /<juego>/<api>/<versión>, así buckets y series quedan acotados aunque llegue un ID.
+func endpointTemplate(path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	segs := templateSegments(path)
+	for _, tpl := range endpointTemplates {
+		if matchTemplate(tpl, segs) {
+			return "/" + strings.Join(tpl, "/")
+		}
+	}
+	if len(segs) > 3 {
+		segs = segs[:3]
+	}
+	return "/" + strings.Join(segs, "/")
+}
+
+func matchTemplate(tpl, segs []string) bool {
+	if len(tpl) != len(segs) {
+		return false
+	}
+	for i, seg := range tpl {
+		if seg == "{id}" {
+			if segs[i] == "" {
+				return false
+			}
+			continue
+		}
+		if seg != segs[i] {
+			return false
+		}
+	}
+	return true
+}
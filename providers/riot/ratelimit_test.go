@@ -0,0 +1,268 @@
+package riot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeClock reloj manual: After avanza el tiempo lo pedido y dispara enseguida, así Wait
+// no duerme y los tests pueden ver cuánto habría esperado
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	waited time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.waited += d
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) Waited() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.waited
+}
+
+// stoppedClock nunca dispara After: Wait solo puede salir por el contexto
+type stoppedClock struct{ now time.Time }
+
+func (c stoppedClock) Now() time.Time                     { return c.now }
+func (stoppedClock) After(time.Duration) <-chan time.Time { return nil }
+
+func rateLimitResponse(status int, headers map[string]string) *http.Response {
+	resp := &http.Response{StatusCode: status, Header: make(http.Header)}
+	for k, v := range headers {
+		resp.Header.Set(k, v)
+	}
+	return resp
+}
+
+func TestRateLimiterAppWindowPerRegion(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiter([]RateLimitWindow{{Limit: 2, Period: time.Second}}, clock)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := rl.Wait(ctx, "euw1", "/lol/platform/v3/champion-rotations"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if clock.Waited() != 0 {
+		t.Fatalf("waited %s within the limit", clock.Waited())
+	}
+
+	// Otra región tiene su propio límite de aplicación
+	if err := rl.Wait(ctx, "na1", "/lol/platform/v3/champion-rotations"); err != nil {
+		t.Fatal(err)
+	}
+	if clock.Waited() != 0 {
+		t.Fatalf("na1 waited %s for euw1's window", clock.Waited())
+	}
+
+	if err := rl.Wait(ctx, "euw1", "/lol/platform/v3/champion-rotations"); err != nil {
+		t.Fatal(err)
+	}
+	if clock.Waited() != time.Second {
+		t.Fatalf("waited %s, want 1s for the window to reset", clock.Waited())
+	}
+}
+
+func TestRateLimiterMethodWindowPerRegionAndMethod(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiter([]RateLimitWindow{{Limit: 100, Period: time.Second}}, clock)
+	ctx := context.Background()
+	const matches = "/lol/match/v5/matches/{id}"
+
+	rl.Update("europe", matches, rateLimitResponse(http.StatusOK, map[string]string{
+		"X-Method-Rate-Limit":       "1:10",
+		"X-Method-Rate-Limit-Count": "0:10",
+	}))
+	if err := rl.Wait(ctx, "europe", matches); err != nil {
+		t.Fatal(err)
+	}
+	// Otro método y otra región no comparten la ventana de 1 request cada 10s
+	if err := rl.Wait(ctx, "europe", "/lol/match/v5/matches/by-puuid/{id}/ids"); err != nil {
+		t.Fatal(err)
+	}
+	if err := rl.Wait(ctx, "americas", matches); err != nil {
+		t.Fatal(err)
+	}
+	if clock.Waited() != 0 {
+		t.Fatalf("waited %s across independent methods", clock.Waited())
+	}
+
+	if err := rl.Wait(ctx, "europe", matches); err != nil {
+		t.Fatal(err)
+	}
+	if clock.Waited() != 10*time.Second {
+		t.Fatalf("waited %s, want 10s for the method window", clock.Waited())
+	}
+}
+
+func TestRateLimiterSyncsAppLimitsFromHeaders(t *testing.T) {
+	clock := newFakeClock()
+	rl := NewRateLimiter(DefaultAppLimits, clock)
+	ctx := context.Background()
+
+	// Riot informa un límite menor y que ya se consumieron 3 de 3 en la ventana de 5s
+	rl.Update("kr", "/val/content/v1/contents", rateLimitResponse(http.StatusOK, map[string]string{
+		"X-App-Rate-Limit":       "3:5",
+		"X-App-Rate-Limit-Count": "3:5",
+	}))
+	if err := rl.Wait(ctx, "kr", "/val/content/v1/contents"); err != nil {
+		t.Fatal(err)
+	}
+	if clock.Waited() != 5*time.Second {
+		t.Fatalf("waited %s, want 5s after syncing 3:5 with count 3", clock.Waited())
+	}
+}
+
+func TestRateLimiterRetryAfterBlocks(t *testing.T) {
+	tests := []struct {
+		name      string
+		limitType string
+		blocked   string // método que debe quedar bloqueado además del que recibió el 429
+		free      string
+	}{
+		{name: "method", limitType: "method", free: "/val/content/v1/contents"},
+		{name: "application", limitType: "application", blocked: "/val/content/v1/contents"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := newFakeClock()
+			rl := NewRateLimiter(DefaultAppLimits, clock)
+			ctx := context.Background()
+			const method = "/val/match/v1/matches/{id}"
+
+			rl.Update("ap", method, rateLimitResponse(http.StatusTooManyRequests, map[string]string{
+				"Retry-After":       "7",
+				"X-Rate-Limit-Type": tt.limitType,
+			}))
+			if tt.free != "" {
+				if err := rl.Wait(ctx, "ap", tt.free); err != nil {
+					t.Fatal(err)
+				}
+				if clock.Waited() != 0 {
+					t.Fatalf("%s waited %s for another method's 429", tt.free, clock.Waited())
+				}
+			}
+			other := method
+			if tt.blocked != "" {
+				other = tt.blocked
+			}
+			if err := rl.Wait(ctx, "ap", other); err != nil {
+				t.Fatal(err)
+			}
+			if clock.Waited() != 7*time.Second {
+				t.Fatalf("waited %s, want Retry-After 7s", clock.Waited())
+			}
+		})
+	}
+}
+
+func TestRateLimiterWaitHonorsContext(t *testing.T) {
+	rl := NewRateLimiter([]RateLimitWindow{{Limit: 1, Period: time.Minute}}, stoppedClock{now: time.Now()})
+	if err := rl.Wait(context.Background(), "euw1", "/lol/platform/v3/champion-rotations"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- rl.Wait(ctx, "euw1", "/lol/platform/v3/champion-rotations") }()
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Wait = %v, want context.Canceled", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after cancel")
+	}
+}
+
+func TestMakeRequestStopsAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "2")
+		w.Header().Set("X-Rate-Limit-Type", "method")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	clock := newFakeClock()
+	c := NewClient("RGAPI-test",
+		WithHTTPClient(srv.Client()),
+		WithPlatformURL(srv.URL+"/{route}"),
+		WithRateLimiter(NewRateLimiter(DefaultAppLimits, clock)),
+		WithMaxRetries(2),
+	)
+
+	resp, err := c.makeRequest(context.Background(), "euw1", "/lol/platform/v3/champion-rotations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want the last 429", resp.StatusCode)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Fatalf("requests = %d, want 1 + 2 retries", got)
+	}
+	if clock.Waited() != 4*time.Second {
+		t.Fatalf("waited %s, want Retry-After before each retry", clock.Waited())
+	}
+}
+
+func TestEndpointTemplate(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/lol/platform/v3/champion-rotations", "/lol/platform/v3/champion-rotations"},
+		{"/lol/match/v5/matches/EUW1_6789", "/lol/match/v5/matches/{id}"},
+		{"/lol/match/v5/matches/by-puuid/abc-123/ids?count=20", "/lol/match/v5/matches/by-puuid/{id}/ids"},
+		{"/lol/summoner/v4/summoners/by-puuid/ab12cd", "/lol/summoner/v4/summoners/by-puuid/{id}"},
+		{"/lol/summoner/v4/summoners/xyz", "/lol/summoner/v4/summoners/{id}"},
+		{"/lol/league/v4/entries/RANKED_SOLO_5x5/DIAMOND/I?page=2", "/lol/league/v4/entries/{id}/{id}/{id}"},
+		{"/lol/league/v4/challengerleagues/by-queue/RANKED_SOLO_5x5", "/lol/league/v4/challengerleagues/by-queue/{id}"},
+		{"/lol/champion-mastery/v4/champion-masteries/by-puuid/p1", "/lol/champion-mastery/v4/champion-masteries/by-puuid/{id}"},
+		{"/val/content/v1/contents", "/val/content/v1/contents"},
+		// UUIDs que empiezan por letra: deben caer en el mismo método
+		{"/val/match/v1/matches/abcdef12-3456-7890-abcd-ef1234567890", "/val/match/v1/matches/{id}"},
+		{"/val/match/v1/matches/f00dbabe-0000-1111-2222-333344445555", "/val/match/v1/matches/{id}"},
+		{"/val/ranked/v1/leaderboards/by-act/e01b2c3d-4e5f-6789-abcd-ef0123456789?size=200&startIndex=0", "/val/ranked/v1/leaderboards/by-act/{id}"},
+		{"/val/match/v1/recent-matches/by-queue/competitive", "/val/match/v1/recent-matches/by-queue/{id}"},
+		// Desconocido: se corta en juego/api/versión
+		{"/lol/spectator/v5/active-games/by-summoner/abc", "/lol/spectator/v5"},
+	}
+	for _, tt := range tests {
+		if got := endpointTemplate(tt.path); got != tt.want {
+			t.Errorf("endpointTemplate(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package riottest levanta un servidor Riot falso en proceso que sirve respuestas
+// grabadas (testdata/*.json) para Data Dragon y los endpoints de plataforma/región.
+//
+//	srv := riottest.NewServer()
+//	defer srv.Close()
+//	client := srv.Client("RGAPI-test")
+package riottest
+
+import (
+	"embed"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/steven230500/hypeatlas-api/providers/riot"
+)
+
+//go:embed testdata/*.json
+var fixtures embed.FS
+
+// Response respuesta fija para un path (ver Server.Handle)
+type Response struct {
+	Status  int
+	Body    []byte
+	Headers map[string]string
+	Times   int // 0 = siempre; n > 0 = solo las próximas n peticiones, después vuelve el fixture
+}
+
+// route asocia un patrón de path (sin el prefijo {route} o /ddragon) a un fixture
+type route struct {
+	pattern *regexp.Regexp
+	fixture string
+}
+
+// routes endpoints soportados; el orden importa (el primero que matchea gana)
+var routes = []route{
+	{regexp.MustCompile(`^/api/versions\.json$`), "versions.json"},
+	{regexp.MustCompile(`^/cdn/[^/]+/data/[^/]+/champion\.json$`), "champion.json"},
+	{regexp.MustCompile(`^/lol/platform/v3/champion-rotations$`), "champion-rotations.json"},
+	{regexp.MustCompile(`^/lol/league/v4/challengerleagues/by-queue/[^/]+$`), "challengerleague.json"},
+	{regexp.MustCompile(`^/lol/league/v4/grandmasterleagues/by-queue/[^/]+$`), "grandmasterleague.json"},
+	{regexp.MustCompile(`^/lol/league/v4/masterleagues/by-queue/[^/]+$`), "masterleague.json"},
+	{regexp.MustCompile(`^/lol/league/v4/entries/[^/]+/[^/]+/[^/]+$`), "league-entries.json"},
+	{regexp.MustCompile(`^/lol/summoner/v4/summoners/(by-puuid/)?[^/]+$`), "summoner.json"},
+	{regexp.MustCompile(`^/lol/match/v5/matches/by-puuid/[^/]+/ids$`), "match-ids.json"},
+	{regexp.MustCompile(`^/lol/match/v5/matches/[^/]+$`), "match.json"},
+	{regexp.MustCompile(`^/lol/champion-mastery/v4/champion-masteries/by-puuid/[^/]+$`), "champion-masteries.json"},
+	{regexp.MustCompile(`^/val/content/v1/contents$`), "val-content.json"},
+	{regexp.MustCompile(`^/val/ranked/v1/leaderboards/by-act/[^/]+$`), "val-leaderboard.json"},
+	{regexp.MustCompile(`^/val/match/v1/recent-matches/by-queue/[^/]+$`), "val-recent-matches.json"},
+	{regexp.MustCompile(`^/val/match/v1/matches/[^/]+$`), "val-match.json"},
+}
+
+// Server servidor Riot falso
+type Server struct {
+	*httptest.Server
+
+	mu        sync.Mutex
+	overrides map[string]Response
+	requests  []string
+}
+
+// NewServer arranca el servidor. Las URLs tienen la forma:
+//
+//	/ddragon/<path de Data Dragon>
+//	/<route>/<path de la API>   (route = euw1, europe, ...)
+func NewServer() *Server {
+	s := &Server{overrides: make(map[string]Response)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// Options devuelve las opciones de cliente que apuntan a este servidor
+func (s *Server) Options() []riot.ClientOption {
+	return []riot.ClientOption{
+		riot.WithDataDragonURL(s.URL + "/ddragon"),
+		riot.WithPlatformURL(s.URL + "/{route}"),
+		riot.WithRegionalURL(s.URL + "/{route}"),
+		riot.WithHTTPClient(s.Server.Client()),
+	}
+}
+
+// Client crea un riot.Client apuntando a este servidor
+func (s *Server) Client(apiKey string, opts ...riot.ClientOption) *riot.Client {
+	return riot.NewClient(apiKey, append(s.Options(), opts...)...)
+}
+
+// Handle fija la respuesta para un path exacto (con prefijo, sin query), p.ej.
+// "/euw1/lol/platform/v3/champion-rotations". Sirve para simular errores o 429.
+func (s *Server) Handle(path string, resp Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.overrides[path] = resp
+}
+
+// Requests devuelve los paths (con query) recibidos, en orden
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	s.requests = append(s.requests, r.URL.RequestURI())
+	override, hasOverride := s.overrides[r.URL.Path]
+	if hasOverride && override.Times > 0 {
+		if override.Times--; override.Times == 0 {
+			delete(s.overrides, r.URL.Path)
+		} else {
+			s.overrides[r.URL.Path] = override
+		}
+	}
+	s.mu.Unlock()
+
+	prefix, path := splitPrefix(r.URL.Path)
+	isAPI := prefix != "ddragon"
+
+	if isAPI && r.Header.Get("X-Riot-Token") == "" {
+		http.Error(w, `{"status":{"message":"Unauthorized","status_code":401}}`, http.StatusUnauthorized)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	if isAPI {
+		w.Header().Set("X-App-Rate-Limit", "20:1,100:120")
+		w.Header().Set("X-App-Rate-Limit-Count", "1:1,1:120")
+		w.Header().Set("X-Method-Rate-Limit", "2000:60")
+		w.Header().Set("X-Method-Rate-Limit-Count", "1:60")
+	}
+
+	if hasOverride {
+		for k, v := range override.Headers {
+			w.Header().Set(k, v)
+		}
+		status := override.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write(override.Body)
+		return
+	}
+
+	// Páginas > 1 de entries vienen vacías, como en Riot al final del tier
+	if strings.HasPrefix(path, "/lol/league/v4/entries/") && r.URL.Query().Get("page") != "" && r.URL.Query().Get("page") != "1" {
+		_, _ = w.Write([]byte("[]"))
+		return
+	}
+
+	for _, rt := range routes {
+		if rt.pattern.MatchString(path) {
+			body, err := fixtures.ReadFile("testdata/" + rt.fixture)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			_, _ = w.Write(body)
+			return
+		}
+	}
+	http.Error(w, `{"status":{"message":"Data not found","status_code":404}}`, http.StatusNotFound)
+}
+
+// splitPrefix separa "/euw1/lol/..." en ("euw1", "/lol/...")
+func splitPrefix(p string) (string, string) {
+	trimmed := strings.TrimPrefix(p, "/")
+	prefix, rest, ok := strings.Cut(trimmed, "/")
+	if !ok {
+		return prefix, "/"
+	}
+	return prefix, "/" + rest
+}
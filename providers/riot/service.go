@@ -3,10 +3,10 @@ package riot
 import (
 	"context"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
 	"github.com/steven230500/hypeatlas-api/domain/entities"
 	"github.com/steven230500/hypeatlas-api/modules/signal/domain/ports/out"
 )
@@ -17,25 +17,32 @@ type Service struct {
 	repo   out.Repository
 }
 
-// NewService crea un nuevo servicio de Riot Games
-func NewService(apiKey string, repo out.Repository) *Service {
+// NewService crea un nuevo servicio de Riot Games. Las base URLs se toman de
+// RIOT_DDRAGON_URL / RIOT_PLATFORM_URL / RIOT_REGIONAL_URL y luego de opts.
+func NewService(apiKey string, repo out.Repository, opts ...ClientOption) *Service {
 	return &Service{
-		client: NewClient(apiKey),
+		client: NewClient(apiKey, append(OptionsFromEnv(), opts...)...),
 		repo:   repo,
 	}
 }
 
+// Client devuelve el cliente subyacente (comparte API key, limiter y base URLs)
+func (s *Service) Client() *Client {
+	return s.client
+}
+
 // SyncPatches sincroniza los parches desde Riot Games API
 func (s *Service) SyncPatches(ctx context.Context) error {
-	log.Println("Starting Riot Games patch synchronization...")
+	logger := log.Ctx(ctx)
+	logger.Info().Msg("riot patch sync started")
 
 	// Obtener la versión más reciente
-	latestVersion, err := s.client.GetLatestVersion()
+	latestVersion, err := s.client.GetLatestVersion(ctx)
 	if err != nil {
 		return fmt.Errorf("error getting latest version: %w", err)
 	}
 
-	log.Printf("Latest version from Riot API: %s", latestVersion)
+	logger.Debug().Str("version", latestVersion).Msg("riot latest version")
 
 	// Verificar si ya existe este parche en la base de datos
 	existingPatches, err := s.repo.PatchesByGame(ctx, "lol")
@@ -53,7 +60,7 @@ func (s *Service) SyncPatches(ctx context.Context) error {
 	}
 
 	if patchExists {
-		log.Printf("Patch %s already exists in database", latestVersion)
+		logger.Info().Str("version", latestVersion).Msg("riot patch already synced")
 		return nil
 	}
 
@@ -72,26 +79,27 @@ func (s *Service) SyncPatches(ctx context.Context) error {
 		return fmt.Errorf("error saving patch: %w", err)
 	}
 
-	log.Printf("Successfully synced patch: %s", latestVersion)
+	logger.Info().Str("version", latestVersion).Msg("riot patch synced")
 	return nil
 }
 
 // SyncChampions sincroniza los campeones para un parche específico
 func (s *Service) SyncChampions(ctx context.Context, version string) error {
-	log.Printf("Starting champion synchronization for version %s...", version)
+	logger := log.Ctx(ctx)
+	logger.Info().Str("version", version).Msg("riot champion sync started")
 
 	// Obtener campeones desde la API
-	champions, err := s.client.GetChampions(version)
+	champions, err := s.client.GetChampions(ctx, version)
 	if err != nil {
 		return fmt.Errorf("error getting champions: %w", err)
 	}
 
-	log.Printf("Found %d champions in version %s", len(champions.Data), version)
+	logger.Info().Str("version", version).Int("champions", len(champions.Data)).Msg("riot champions fetched")
 
 	// Aquí podríamos procesar y guardar los cambios de campeones
 	// Por ahora solo loggeamos
 	for _, champion := range champions.Data {
-		log.Printf("Champion: %s (%s) - %s", champion.Name, champion.ID, champion.Title)
+		logger.Debug().Str("champion", champion.ID).Str("name", champion.Name).Str("title", champion.Title).Msg("riot champion")
 	}
 
 	return nil
@@ -115,7 +123,7 @@ func (s *Service) savePatch(ctx context.Context, patch *entities.Patch) error {
 
 	// Aquí iría la lógica para guardar en la base de datos
 	// Por ahora solo simulamos que se guarda correctamente
-	log.Printf("Simulating save of patch: %+v", patch)
+	log.Ctx(ctx).Debug().Str("version", patch.Version).Str("uuid", patch.UUID.String()).Msg("simulating patch save")
 
 	return nil
 }
@@ -140,103 +148,102 @@ func (s *Service) GetPatchInfo(ctx context.Context, version string) (*entities.P
 
 // GetChampionRotation obtiene la rotación semanal de campeones gratuitos
 func (s *Service) GetChampionRotation(ctx context.Context, platform string) (*ChampionRotationResponse, error) {
-	return s.client.GetChampionRotation(platform)
+	return s.client.GetChampionRotation(ctx, platform)
 }
 
 // GetChampions obtiene la lista de campeones para una versión específica
 func (s *Service) GetChampions(ctx context.Context, version string) (*ChampionsResponse, error) {
-	return s.client.GetChampions(version)
+	return s.client.GetChampions(ctx, version)
 }
 
 // GetChallengerLeague obtiene la liga Challenger para una cola específica
 func (s *Service) GetChallengerLeague(ctx context.Context, platform, queue string) (*LeagueData, error) {
-	return s.client.GetChallengerLeague(platform, queue)
+	return s.client.GetChallengerLeague(ctx, platform, queue)
 }
 
-// GetAllLeagues obtiene todas las ligas disponibles para una plataforma
-func (s *Service) GetAllLeagues(ctx context.Context, platform string) ([]string, error) {
-	return s.client.GetAllLeagues(platform)
+// GetGrandmasterLeague obtiene la liga Grandmaster para una cola específica
+func (s *Service) GetGrandmasterLeague(ctx context.Context, platform, queue string) (*LeagueData, error) {
+	return s.client.GetGrandmasterLeague(ctx, platform, queue)
 }
 
-// GetGames obtiene la lista de juegos disponibles de Riot Games
-func (s *Service) GetGames(ctx context.Context) ([]string, error) {
-	return s.client.GetGames()
+// GetMasterLeague obtiene la liga Master para una cola específica
+func (s *Service) GetMasterLeague(ctx context.Context, platform, queue string) (*LeagueData, error) {
+	return s.client.GetMasterLeague(ctx, platform, queue)
 }
 
-// GetRegions obtiene la lista de regiones disponibles para League of Legends
-func (s *Service) GetRegions(ctx context.Context) ([]string, error) {
-	return s.client.GetRegions()
+// GetLeagueEntries obtiene una página de entradas de un tier/división (tiers no apex)
+func (s *Service) GetLeagueEntries(ctx context.Context, platform, queue, tier, division string, page int) (LeagueEntriesResponse, error) {
+	return s.client.GetLeagueEntries(ctx, platform, queue, tier, division, page)
 }
 
-// GetChampionStats obtiene estadísticas de uso de campeones
-func (s *Service) GetChampionStats(ctx context.Context, version string) (map[string]interface{}, error) {
-	return s.client.GetChampionStats(version)
+// GetSummonerBySummonerID obtiene un summoner por su summonerId encriptado
+func (s *Service) GetSummonerBySummonerID(ctx context.Context, platform, summonerID string) (*SummonerData, error) {
+	return s.client.GetSummonerBySummonerID(ctx, platform, summonerID)
 }
 
-// GetPatchChanges obtiene cambios de campeones entre parches
-func (s *Service) GetPatchChanges(ctx context.Context, fromVersion, toVersion string) (map[string]interface{}, error) {
-	return s.client.GetPatchChanges(fromVersion, toVersion)
+// GetMatchIDsByPUUID obtiene los IDs de partidas recientes de un jugador (region = routing regional)
+func (s *Service) GetMatchIDsByPUUID(ctx context.Context, region, puuid string, queue, count int) ([]string, error) {
+	return s.client.GetMatchIDsByPUUID(ctx, region, puuid, queue, count)
 }
 
-// GetProfessionalLeagues obtiene información sobre ligas profesionales
-func (s *Service) GetProfessionalLeagues(ctx context.Context) (map[string]interface{}, error) {
-	return s.client.GetProfessionalLeagues()
+// GetMatch obtiene el detalle de una partida de Match-V5
+func (s *Service) GetMatch(ctx context.Context, region, matchID string) (*Match, error) {
+	return s.client.GetMatch(ctx, region, matchID)
 }
 
-// GetLeagueChampions obtiene estadísticas de campeones en una liga específica
-func (s *Service) GetLeagueChampions(ctx context.Context, league string) (map[string]interface{}, error) {
-	return s.client.GetLeagueChampions(league)
+// GetChampionMasteries obtiene todas las maestrías de campeón de un jugador
+func (s *Service) GetChampionMasteries(ctx context.Context, platform, puuid string) (ChampionMasteriesResponse, error) {
+	return s.client.GetChampionMasteries(ctx, platform, puuid)
 }
 
-// ProfessionalLeagueService maneja la lógica de ligas profesionales
-type ProfessionalLeagueService struct {
-	client *Client
+// GetVALContent obtiene el contenido de Valorant de un shard
+func (s *Service) GetVALContent(ctx context.Context, shard, locale string) (*VALContentResponse, error) {
+	return s.client.GetVALContent(ctx, shard, locale)
 }
 
-// NewProfessionalLeagueService crea un nuevo servicio de ligas profesionales
-func NewProfessionalLeagueService(client *Client) *ProfessionalLeagueService {
-	return &ProfessionalLeagueService{client: client}
+// GetVALLeaderboard obtiene el leaderboard ranked de un act
+func (s *Service) GetVALLeaderboard(ctx context.Context, shard, actID string, size, startIndex int) (*VALLeaderboard, error) {
+	return s.client.GetVALLeaderboard(ctx, shard, actID, size, startIndex)
 }
 
-// GetProfessionalLeagues obtiene información completa de ligas profesionales
-func (s *ProfessionalLeagueService) GetProfessionalLeagues() (map[string]interface{}, error) {
-	return s.client.GetProfessionalLeagues()
+// GetVALMatch obtiene el detalle de una partida de Valorant
+func (s *Service) GetVALMatch(ctx context.Context, shard, matchID string) (*VALMatch, error) {
+	return s.client.GetVALMatch(ctx, shard, matchID)
 }
 
-// GetLeagueChampions obtiene estadísticas detalladas de una liga específica
-func (s *ProfessionalLeagueService) GetLeagueChampions(league string) (map[string]interface{}, error) {
-	// Validar liga
-	validLeagues := map[string]bool{
-		"LEC": true, "LCK": true, "LPL": true, "LTA": true,
-		"LCS": true, "VCS": true, "PCS": true,
-	}
+// GetVALRecentMatches obtiene las partidas recientes de una cola de Valorant
+func (s *Service) GetVALRecentMatches(ctx context.Context, shard, queue string) (*VALRecentMatches, error) {
+	return s.client.GetVALRecentMatches(ctx, shard, queue)
+}
 
-	if !validLeagues[league] {
-		return nil, fmt.Errorf("invalid league: %s", league)
-	}
+// GetLatestVersion obtiene la versión más reciente desde Data Dragon
+func (s *Service) GetLatestVersion(ctx context.Context) (string, error) {
+	return s.client.GetLatestVersion(ctx)
+}
 
-	// Obtener datos básicos
-	stats, err := s.client.GetLeagueChampions(league)
-	if err != nil {
-		return nil, err
-	}
+// GetAllLeagues obtiene todas las ligas disponibles para una plataforma
+func (s *Service) GetAllLeagues(ctx context.Context, platform string) ([]string, error) {
+	return s.client.GetAllLeagues(ctx, platform)
+}
 
-	// Enriquecer con datos adicionales
-	stats["analysis_date"] = time.Now().UTC().Format(time.RFC3339)
-	stats["data_source"] = "Riot Games API + Community Analysis"
+// GetGames obtiene la lista de juegos disponibles de Riot Games
+func (s *Service) GetGames(ctx context.Context) ([]string, error) {
+	return s.client.GetGames(ctx)
+}
 
-	return stats, nil
+// GetRegions obtiene la lista de regiones disponibles para League of Legends
+func (s *Service) GetRegions(ctx context.Context) ([]string, error) {
+	return s.client.GetRegions(ctx)
 }
 
-// ValidateLeague valida si una liga existe
-func (s *ProfessionalLeagueService) ValidateLeague(league string) bool {
-	validLeagues := []string{"LEC", "LCK", "LPL", "LTA", "LCS", "VCS", "PCS"}
-	for _, valid := range validLeagues {
-		if valid == league {
-			return true
-		}
-	}
-	return false
+// GetChampionStats obtiene estadísticas de uso de campeones
+func (s *Service) GetChampionStats(ctx context.Context, version string) (map[string]interface{}, error) {
+	return s.client.GetChampionStats(ctx, version)
+}
+
+// GetPatchChanges obtiene cambios de campeones entre parches
+func (s *Service) GetPatchChanges(ctx context.Context, fromVersion, toVersion string) (map[string]interface{}, error) {
+	return s.client.GetPatchChanges(ctx, fromVersion, toVersion)
 }
 
 // DataDragonService maneja la lógica de Data Dragon API
@@ -251,7 +258,7 @@ func NewDataDragonService(client *Client) *DataDragonService {
 
 // GetGameVersions obtiene todas las versiones disponibles del juego
 func (s *DataDragonService) GetGameVersions(ctx context.Context) ([]string, error) {
-	versions, err := s.client.GetLatestVersion()
+	versions, err := s.client.GetLatestVersion(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("error getting latest version: %w", err)
 	}
@@ -264,31 +271,31 @@ func (s *DataDragonService) GetGameVersions(ctx context.Context) ([]string, erro
 // GetItems obtiene datos de items para una versión específica
 func (s *DataDragonService) GetItems(ctx context.Context, version string) (map[string]interface{}, error) {
 	// Usar el método del cliente que ya implementamos
-	return s.client.GetItems(version)
+	return s.client.GetItems(ctx, version)
 }
 
 // GetRunes obtiene datos de runas para una versión específica
 func (s *DataDragonService) GetRunes(ctx context.Context, version string) (map[string]interface{}, error) {
 	// Usar el método del cliente que ya implementamos
-	return s.client.GetRunes(version)
+	return s.client.GetRunes(ctx, version)
 }
 
 // GetSummonerSpells obtiene datos de summoner spells para una versión específica
 func (s *DataDragonService) GetSummonerSpells(ctx context.Context, version string) (map[string]interface{}, error) {
 	// Usar el método del cliente que ya implementamos
-	return s.client.GetSummonerSpells(version)
+	return s.client.GetSummonerSpells(ctx, version)
 }
 
 // GetChampionDetails obtiene detalles completos de un campeón específico
 func (s *DataDragonService) GetChampionDetails(ctx context.Context, version, championID string) (map[string]interface{}, error) {
 	// Usar el método del cliente que ya implementamos
-	return s.client.GetChampionDetails(version, championID)
+	return s.client.GetChampionDetails(ctx, version, championID)
 }
 
 // GetPatchNotes obtiene información de cambios entre parches
 func (s *DataDragonService) GetPatchNotes(ctx context.Context, fromVersion, toVersion string) (map[string]interface{}, error) {
 	// Usar el método del cliente que ya implementamos
-	return s.client.GetPatchNotes(fromVersion, toVersion)
+	return s.client.GetPatchNotes(ctx, fromVersion, toVersion)
 }
 
 // ImageService maneja la lógica de URLs de imágenes de Data Dragon
@@ -303,7 +310,7 @@ func NewImageService(client *Client) *ImageService {
 
 // GetChampionImageURLs obtiene todas las URLs de imágenes disponibles para un campeón
 func (s *ImageService) GetChampionImageURLs(ctx context.Context, version, championID string, skinNum int) (map[string]interface{}, error) {
-	baseURL := "https://ddragon.leagueoflegends.com/cdn"
+	baseURL := s.client.DataDragonURL("/cdn")
 
 	imageURLs := map[string]interface{}{
 		"version":  version,
@@ -329,7 +336,7 @@ func (s *ImageService) GetChampionImageURLs(ctx context.Context, version, champi
 
 // GetItemImageURL obtiene la URL de imagen para un item específico
 func (s *ImageService) GetItemImageURL(ctx context.Context, version, itemID string) (map[string]interface{}, error) {
-	baseURL := "https://ddragon.leagueoflegends.com/cdn"
+	baseURL := s.client.DataDragonURL("/cdn")
 
 	imageURL := map[string]interface{}{
 		"version": version,
@@ -346,7 +353,7 @@ func (s *ImageService) GetItemImageURL(ctx context.Context, version, itemID stri
 
 // GetSpellImageURL obtiene la URL de imagen para un summoner spell
 func (s *ImageService) GetSpellImageURL(ctx context.Context, version, spellName string) (map[string]interface{}, error) {
-	baseURL := "https://ddragon.leagueoflegends.com/cdn"
+	baseURL := s.client.DataDragonURL("/cdn")
 
 	imageURL := map[string]interface{}{
 		"version": version,
@@ -363,7 +370,7 @@ func (s *ImageService) GetSpellImageURL(ctx context.Context, version, spellName
 
 // GetRuneImageURL obtiene la URL de imagen para una runa
 func (s *ImageService) GetRuneImageURL(ctx context.Context, runeIcon string) (map[string]interface{}, error) {
-	baseURL := "https://ddragon.leagueoflegends.com/cdn"
+	baseURL := s.client.DataDragonURL("/cdn")
 
 	imageURL := map[string]interface{}{
 		"rune_icon": runeIcon,
@@ -379,7 +386,7 @@ func (s *ImageService) GetRuneImageURL(ctx context.Context, runeIcon string) (ma
 
 // GetProfileIconImageURL obtiene la URL de imagen para un ícono de perfil
 func (s *ImageService) GetProfileIconImageURL(ctx context.Context, version string, iconID int) (map[string]interface{}, error) {
-	baseURL := "https://ddragon.leagueoflegends.com/cdn"
+	baseURL := s.client.DataDragonURL("/cdn")
 
 	imageURL := map[string]interface{}{
 		"version": version,
@@ -396,7 +403,7 @@ func (s *ImageService) GetProfileIconImageURL(ctx context.Context, version strin
 
 // GetMapImageURL obtiene la URL de imagen para un mapa
 func (s *ImageService) GetMapImageURL(ctx context.Context, version string, mapID int) (map[string]interface{}, error) {
-	baseURL := "https://ddragon.leagueoflegends.com/cdn"
+	baseURL := s.client.DataDragonURL("/cdn")
 
 	imageURL := map[string]interface{}{
 		"version": version,
@@ -413,7 +420,7 @@ func (s *ImageService) GetMapImageURL(ctx context.Context, version string, mapID
 
 // GetAbilityImageURL obtiene la URL de imagen para una habilidad de campeón
 func (s *ImageService) GetAbilityImageURL(ctx context.Context, version, abilityName string) (map[string]interface{}, error) {
-	baseURL := "https://ddragon.leagueoflegends.com/cdn"
+	baseURL := s.client.DataDragonURL("/cdn")
 
 	imageURL := map[string]interface{}{
 		"version": version,
@@ -430,7 +437,7 @@ func (s *ImageService) GetAbilityImageURL(ctx context.Context, version, abilityN
 
 // GetPassiveImageURL obtiene la URL de imagen para la pasiva de un campeón
 func (s *ImageService) GetPassiveImageURL(ctx context.Context, version, passiveFile string) (map[string]interface{}, error) {
-	baseURL := "https://ddragon.leagueoflegends.com/cdn"
+	baseURL := s.client.DataDragonURL("/cdn")
 
 	imageURL := map[string]interface{}{
 		"version": version,
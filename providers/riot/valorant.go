@@ -0,0 +1,329 @@
+package riot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// VALShards shards de Valorant (routing value de VAL-CONTENT/RANKED/MATCH)
+var VALShards = []string{"na", "latam", "br", "eu", "ap", "kr"}
+
+// IsVALShard indica si shard es un shard de Valorant válido
+func IsVALShard(shard string) bool {
+	for _, s := range VALShards {
+		if s == shard {
+			return true
+		}
+	}
+	return false
+}
+
+// VALShardRegions región competitiva de cada shard (la que usamos en app.comps)
+var VALShardRegions = map[string]string{
+	"na": "NA", "latam": "LATAM", "br": "BR", "eu": "EMEA", "ap": "APAC", "kr": "KR",
+}
+
+// VALPatchFromGameVersion recorta el gameVersion de VAL-MATCH-V1 a su parche
+// (release-09.01-shipping-12-2603563 -> 9.01)
+func VALPatchFromGameVersion(gameVersion string) string {
+	version := strings.TrimPrefix(gameVersion, "release-")
+	version, _, _ = strings.Cut(version, "-")
+	major, minor, ok := strings.Cut(version, ".")
+	if !ok {
+		return version
+	}
+	if trimmed := strings.TrimLeft(major, "0"); trimmed != "" {
+		major = trimmed
+	}
+	return major + "." + minor
+}
+
+// VALContentItem elemento genérico de VAL-CONTENT-V1 (agentes, mapas, modos, equipamiento...)
+type VALContentItem struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	LocalizedNames map[string]string `json:"localizedNames,omitempty"`
+	AssetName      string            `json:"assetName"`
+	AssetPath      string            `json:"assetPath,omitempty"`
+}
+
+// VALAct act o episodio de Valorant
+type VALAct struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	ParentID string `json:"parentId,omitempty"`
+	Type     string `json:"type,omitempty"` // act | episode
+	IsActive bool   `json:"isActive"`
+}
+
+// VALContentResponse respuesta de VAL-CONTENT-V1
+type VALContentResponse struct {
+	Version      string           `json:"version"`
+	Characters   []VALContentItem `json:"characters"`
+	Maps         []VALContentItem `json:"maps"`
+	Chromas      []VALContentItem `json:"chromas"`
+	Skins        []VALContentItem `json:"skins"`
+	SkinLevels   []VALContentItem `json:"skinLevels"`
+	Equips       []VALContentItem `json:"equips"`
+	GameModes    []VALContentItem `json:"gameModes"`
+	Sprays       []VALContentItem `json:"sprays"`
+	SprayLevels  []VALContentItem `json:"sprayLevels"`
+	Charms       []VALContentItem `json:"charms"`
+	CharmLevels  []VALContentItem `json:"charmLevels"`
+	PlayerCards  []VALContentItem `json:"playerCards"`
+	PlayerTitles []VALContentItem `json:"playerTitles"`
+	Acts         []VALAct         `json:"acts"`
+}
+
+// ActiveAct devuelve el act activo (no el episodio), o nil
+func (c *VALContentResponse) ActiveAct() *VALAct {
+	for i := range c.Acts {
+		act := &c.Acts[i]
+		if act.IsActive && !strings.EqualFold(act.Type, "episode") {
+			return act
+		}
+	}
+	return nil
+}
+
+// AgentName resuelve el characterId de una partida al nombre del agente
+func (c *VALContentResponse) AgentName(characterID string) string {
+	for _, ch := range c.Characters {
+		if strings.EqualFold(ch.ID, characterID) {
+			return ch.Name
+		}
+	}
+	return ""
+}
+
+// MapName resuelve el mapId de una partida (asset path) al nombre del mapa
+func (c *VALContentResponse) MapName(mapID string) string {
+	for _, m := range c.Maps {
+		if strings.EqualFold(m.AssetPath, mapID) || strings.EqualFold(m.ID, mapID) {
+			return m.Name
+		}
+	}
+	return ""
+}
+
+// GetVALContent obtiene el contenido de Valorant (agentes, mapas, acts, modos, equipamiento)
+func (c *Client) GetVALContent(ctx context.Context, shard, locale string) (*VALContentResponse, error) {
+	path := "/val/content/v1/contents"
+	if locale != "" {
+		path += "?locale=" + url.QueryEscape(locale)
+	}
+
+	resp, err := c.makeRequest(ctx, shard, path)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	var content VALContentResponse
+	if err := json.Unmarshal(body, &content); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return &content, nil
+}
+
+// VALLeaderboard respuesta de VAL-RANKED-V1 para un act
+type VALLeaderboard struct {
+	Shard                 string                `json:"shard"`
+	ActID                 string                `json:"actId"`
+	TotalPlayers          int64                 `json:"totalPlayers"`
+	Players               []VALLeaderboardEntry `json:"players"`
+	TierDetails           []VALTierDetail       `json:"tierDetails"`
+	ImmortalStartingPage  int                   `json:"immortalStartingPage"`
+	ImmortalStartingIndex int                   `json:"immortalStartingIndex"`
+	TopTierRRThreshold    int                   `json:"topTierRRThreshold"`
+	StartIndex            int                   `json:"startIndex"`
+}
+
+// VALLeaderboardEntry jugador del leaderboard (puuid/nombre vacíos si es anónimo)
+type VALLeaderboardEntry struct {
+	PUUID           string `json:"puuid"`
+	GameName        string `json:"gameName"`
+	TagLine         string `json:"tagLine"`
+	LeaderboardRank int    `json:"leaderboardRank"`
+	RankedRating    int    `json:"rankedRating"`
+	NumberOfWins    int    `json:"numberOfWins"`
+	CompetitiveTier int    `json:"competitiveTier"`
+}
+
+// VALTierDetail umbral de RR de un tier del leaderboard
+type VALTierDetail struct {
+	RankedRatingThreshold int `json:"rankedRatingThreshold"`
+	StartingPage          int `json:"startingPage"`
+	StartingIndex         int `json:"startingIndex"`
+}
+
+// GetVALLeaderboard obtiene el leaderboard de un act (size máx. 200)
+func (c *Client) GetVALLeaderboard(ctx context.Context, shard, actID string, size, startIndex int) (*VALLeaderboard, error) {
+	if size <= 0 || size > 200 {
+		size = 200
+	}
+	if startIndex < 0 {
+		startIndex = 0
+	}
+	path := fmt.Sprintf("/val/ranked/v1/leaderboards/by-act/%s?size=%d&startIndex=%d", actID, size, startIndex)
+
+	resp, err := c.makeRequest(ctx, shard, path)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	var leaderboard VALLeaderboard
+	if err := json.Unmarshal(body, &leaderboard); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return &leaderboard, nil
+}
+
+// VALMatch respuesta de VAL-MATCH-V1 (solo los campos que usamos)
+type VALMatch struct {
+	MatchInfo    VALMatchInfo     `json:"matchInfo"`
+	Players      []VALMatchPlayer `json:"players"`
+	Teams        []VALMatchTeam   `json:"teams"`
+	RoundResults []VALRoundResult `json:"roundResults"`
+}
+
+// VALMatchInfo información general de una partida
+type VALMatchInfo struct {
+	MatchID            string `json:"matchId"`
+	MapID              string `json:"mapId"` // asset path, ver VALContentResponse.MapName
+	GameLengthMillis   int64  `json:"gameLengthMillis"`
+	GameStartMillis    int64  `json:"gameStartMillis"`
+	ProvisioningFlowID string `json:"provisioningFlowId"`
+	IsCompleted        bool   `json:"isCompleted"`
+	QueueID            string `json:"queueId"` // competitive, unrated, ...
+	GameMode           string `json:"gameMode"`
+	IsRanked           bool   `json:"isRanked"`
+	SeasonID           string `json:"seasonId"`
+	GameVersion        string `json:"gameVersion"` // release-09.01-shipping-12-2603563
+}
+
+// VALMatchPlayer jugador de una partida
+type VALMatchPlayer struct {
+	PUUID           string             `json:"puuid"`
+	GameName        string             `json:"gameName"`
+	TagLine         string             `json:"tagLine"`
+	TeamID          string             `json:"teamId"` // Red | Blue
+	PartyID         string             `json:"partyId"`
+	CharacterID     string             `json:"characterId"`
+	CompetitiveTier int                `json:"competitiveTier"`
+	Stats           VALMatchPlayerStat `json:"stats"`
+}
+
+// VALMatchPlayerStat estadísticas de un jugador en la partida
+type VALMatchPlayerStat struct {
+	Score          int   `json:"score"`
+	RoundsPlayed   int   `json:"roundsPlayed"`
+	Kills          int   `json:"kills"`
+	Deaths         int   `json:"deaths"`
+	Assists        int   `json:"assists"`
+	PlaytimeMillis int64 `json:"playtimeMillis"`
+}
+
+// VALMatchTeam resultado de un equipo
+type VALMatchTeam struct {
+	TeamID       string `json:"teamId"`
+	Won          bool   `json:"won"`
+	RoundsPlayed int    `json:"roundsPlayed"`
+	RoundsWon    int    `json:"roundsWon"`
+	NumPoints    int    `json:"numPoints"`
+}
+
+// VALRoundResult resultado de una ronda
+type VALRoundResult struct {
+	RoundNum    int    `json:"roundNum"`
+	RoundResult string `json:"roundResult"`
+	WinningTeam string `json:"winningTeam"`
+	BombPlanter string `json:"bombPlanter,omitempty"`
+	PlantSite   string `json:"plantSite,omitempty"`
+}
+
+// GetVALMatch obtiene el detalle de una partida de Valorant
+func (c *Client) GetVALMatch(ctx context.Context, shard, matchID string) (*VALMatch, error) {
+	path := fmt.Sprintf("/val/match/v1/matches/%s", matchID)
+
+	resp, err := c.makeRequest(ctx, shard, path)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	var match VALMatch
+	if err := json.Unmarshal(body, &match); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return &match, nil
+}
+
+// VALRecentMatches IDs de partidas recientes de una cola
+type VALRecentMatches struct {
+	CurrentTime int64    `json:"currentTime"`
+	MatchIDs    []string `json:"matchIds"`
+}
+
+// GetVALRecentMatches obtiene las partidas recientes de una cola (competitive, unrated, ...)
+func (c *Client) GetVALRecentMatches(ctx context.Context, shard, queue string) (*VALRecentMatches, error) {
+	path := fmt.Sprintf("/val/match/v1/recent-matches/by-queue/%s", queue)
+
+	resp, err := c.makeRequest(ctx, shard, path)
+	if err != nil {
+		return nil, fmt.Errorf("error making request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+
+	var recent VALRecentMatches
+	if err := json.Unmarshal(body, &recent); err != nil {
+		return nil, fmt.Errorf("error parsing response: %w", err)
+	}
+
+	return &recent, nil
+}
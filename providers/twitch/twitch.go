@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/steven230500/hypeatlas-api/shared/metrics"
 )
 
 const (
@@ -42,7 +44,7 @@ func (c *Client) ensureToken(ctx context.Context) error {
 
 	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, oauthURL, strings.NewReader(form.Encode()))
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	res, err := c.http.Do(req)
+	res, err := c.do(req, "oauth")
 	if err != nil {
 		return err
 	}
@@ -64,6 +66,18 @@ func (c *Client) ensureToken(ctx context.Context) error {
 	return nil
 }
 
+// do ejecuta la petición y registra su status y latencia por endpoint
+func (c *Client) do(req *http.Request, endpoint string) (*http.Response, error) {
+	started := time.Now()
+	res, err := c.http.Do(req)
+	status := 0
+	if err == nil {
+		status = res.StatusCode
+	}
+	metrics.ObserveProviderCall("twitch", endpoint, status, time.Since(started))
+	return res, err
+}
+
 func (c *Client) auth(req *http.Request) {
 	req.Header.Set("Client-Id", c.ClientID)
 	req.Header.Set("Authorization", "Bearer "+c.token)
@@ -98,7 +112,7 @@ func (c *Client) GetStreamsByLogin(ctx context.Context, logins []string) (map[st
 
 	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
 	c.auth(req)
-	res, err := c.http.Do(req)
+	res, err := c.do(req, "streams")
 	if err != nil {
 		return nil, err
 	}
@@ -1,30 +1,42 @@
 package db
 
 import (
-	"log"
+	"context"
 	"os"
 	"time"
 
+	"github.com/rs/zerolog/log"
+	"github.com/steven230500/hypeatlas-api/shared/logger"
+	"github.com/steven230500/hypeatlas-api/shared/metrics"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	glogger "gorm.io/gorm/logger"
 )
 
 func Connect() *gorm.DB {
 	dsn := os.Getenv("POSTGRES_URL")
 	if dsn == "" {
-		log.Fatal("POSTGRES_URL is empty")
+		log.Fatal().Msg("POSTGRES_URL is empty")
+	}
+	// Queries más lentas que DB_SLOW_QUERY (default 200ms, 0 desactiva) se loguean en warn
+	slow := 200 * time.Millisecond
+	if v := os.Getenv("DB_SLOW_QUERY"); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			slow = d
+		}
 	}
 	cfg := &gorm.Config{
-		Logger: glogger.Default.LogMode(glogger.Error),
+		Logger: logger.NewGorm(slow),
 	}
 	g, err := gorm.Open(postgres.Open(dsn), cfg)
 	if err != nil {
-		log.Fatalf("gorm open error: %v", err)
+		log.Fatal().Err(err).Msg("gorm open failed")
+	}
+	if err := metrics.InstrumentDB(g); err != nil {
+		log.Warn().Err(err).Msg("db metrics disabled")
 	}
 	sqlDB, err := g.DB()
 	if err != nil {
-		log.Fatalf("gorm.DB(): %v", err)
+		log.Fatal().Err(err).Msg("gorm db pool unavailable")
 	}
 	sqlDB.SetMaxIdleConns(4)
 	sqlDB.SetMaxOpenConns(16)
@@ -32,6 +44,24 @@ func Connect() *gorm.DB {
 	return g
 }
 
+// Close cierra el pool de conexiones (llamar después de que terminó todo lo que usa la DB)
+func Close(g *gorm.DB) error {
+	sqlDB, err := g.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// Ping comprueba que la base de datos responde
+func Ping(ctx context.Context, g *gorm.DB) error {
+	sqlDB, err := g.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
 // Call ejecuta una query de GORM y maneja errores de forma consistente
 func Call(db *gorm.DB) *gorm.DB {
 	return db
@@ -1,8 +1,7 @@
 package db
 
 import (
-	"log"
-
+	"github.com/rs/zerolog/log"
 	"github.com/steven230500/hypeatlas-api/domain/entities"
 	"gorm.io/gorm"
 )
@@ -12,10 +11,60 @@ func ensureSchema(g *gorm.DB) {
 	_ = g.Exec(`CREATE SCHEMA IF NOT EXISTS app`).Error
 }
 
+// language=SQL
+const dedupeCoStreamsSQL = `
+DELETE FROM app.co_streams a
+USING app.co_streams b
+WHERE a.event_uuid = b.event_uuid
+  AND a.creator_uuid = b.creator_uuid
+  AND (COALESCE(a.last_seen_at, a.created_at), a.uuid) < (COALESCE(b.last_seen_at, b.created_at), b.uuid)`
+
+// dedupeCoStreams deja un solo co-stream por (evento, creador), el de señal más reciente,
+// para que AutoMigrate pueda crear uq_costream_event_creator sobre tablas existentes
+func dedupeCoStreams(g *gorm.DB) {
+	var exists bool
+	if err := g.Raw(`SELECT to_regclass('app.co_streams') IS NOT NULL`).Scan(&exists).Error; err != nil || !exists {
+		return
+	}
+	if g.Migrator().HasIndex(&entities.CoStream{}, "uq_costream_event_creator") {
+		return
+	}
+	result := g.Exec(dedupeCoStreamsSQL)
+	if result.Error != nil {
+		log.Fatal().Err(result.Error).Msg("dedupe co_streams failed")
+	}
+	if result.RowsAffected > 0 {
+		log.Info().Int64("rows", result.RowsAffected).Msg("removed duplicate co_streams before adding uq_costream_event_creator")
+	}
+}
+
+// language=SQL
+const deleteDemoLeagueStatsSQL = `
+DELETE FROM app.league_champion_stats
+WHERE league_code = 'LEC' AND season = 'Summer 2024' AND games_analyzed = 1250
+  AND (champion_name, position, pick_rate, win_rate, ban_rate) IN (
+    ('Yuumi', 'Support', 15.2, 52.1, 8.5),
+    ('Jax', 'Top', 12.8, 48.9, 25.3),
+    ('Ahri', 'Mid', 11.5, 51.2, 12.1))`
+
+// deleteDemoLeagueStats borra las estadísticas LEC de ejemplo que sembraban las versiones
+// anteriores de RunSeeds; solo coincide con esas tres filas exactas, no con datos importados
+func deleteDemoLeagueStats(g *gorm.DB) {
+	result := g.Exec(deleteDemoLeagueStatsSQL)
+	if result.Error != nil {
+		log.Error().Err(result.Error).Msg("delete demo league champion stats failed")
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Info().Int64("rows", result.RowsAffected).Msg("removed demo LEC league champion stats")
+	}
+}
+
 func Migrate(g *gorm.DB) {
 	ensureSchema(g)
+	dedupeCoStreams(g)
 
-	if err := g.AutoMigrate(
+	models := []any{
 		// Relay (HypeMap)
 		&entities.Event{},
 		&entities.Creator{},
@@ -27,6 +76,7 @@ func Migrate(g *gorm.DB) {
 		&entities.PatchChange{},
 		&entities.League{},
 		&entities.Comp{},
+		&entities.CompSnapshot{},
 		// Nuevas
 		&entities.User{},
 		&entities.IngestionLog{},
@@ -45,9 +95,17 @@ func Migrate(g *gorm.DB) {
 		// Professional leagues
 		&entities.ProfessionalLeague{},
 		&entities.LeagueChampionStats{},
-	); err != nil {
-		log.Fatalf("auto-migrate failed: %v", err)
+		&entities.ProMatch{},
+		// Match-V5
+		&entities.RiotMatch{},
+		&entities.ChampionMatchStats{},
+		// Worker
+		&entities.JobState{},
+	}
+	if err := g.AutoMigrate(models...); err != nil {
+		log.Fatal().Err(err).Msg("auto-migrate failed")
 	}
+	deleteDemoLeagueStats(g)
 
-	log.Println("Database migration completed successfully - 22 entities migrated")
+	log.Info().Int("entities", len(models)).Msg("database migration completed")
 }
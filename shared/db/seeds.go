@@ -247,49 +247,6 @@ func RunSeeds(g *gorm.DB) error {
 			Attrs(league).FirstOrCreate(&entities.ProfessionalLeague{}).Error
 	}
 
-	// --- League Champion Stats Seeds (LEC Example)
-	lecChampions := []entities.LeagueChampionStats{
-		{
-			LeagueCode:    "LEC",
-			ChampionName:  "Yuumi",
-			PickRate:      15.2,
-			WinRate:       52.1,
-			BanRate:       8.5,
-			Position:      "Support",
-			Season:        "Summer 2024",
-			GamesAnalyzed: 1250,
-			LastUpdated:   time.Now().UTC(),
-		},
-		{
-			LeagueCode:    "LEC",
-			ChampionName:  "Jax",
-			PickRate:      12.8,
-			WinRate:       48.9,
-			BanRate:       25.3,
-			Position:      "Top",
-			Season:        "Summer 2024",
-			GamesAnalyzed: 1250,
-			LastUpdated:   time.Now().UTC(),
-		},
-		{
-			LeagueCode:    "LEC",
-			ChampionName:  "Ahri",
-			PickRate:      11.5,
-			WinRate:       51.2,
-			BanRate:       12.1,
-			Position:      "Mid",
-			Season:        "Summer 2024",
-			GamesAnalyzed: 1250,
-			LastUpdated:   time.Now().UTC(),
-		},
-	}
-
-	for _, champ := range lecChampions {
-		_ = g.Where("league_code = ? AND champion_name = ? AND season = ?",
-			champ.LeagueCode, champ.ChampionName, champ.Season).
-			Attrs(champ).FirstOrCreate(&entities.LeagueChampionStats{}).Error
-	}
-
 	return nil
 }
 
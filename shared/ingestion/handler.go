@@ -0,0 +1,95 @@
+package ingestion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+)
+
+// Handler endpoint de auditoría de ingestas (/v1/admin/ingestion)
+type Handler struct {
+	store *Store
+}
+
+// NewHandler crea el handler de auditoría de ingestas
+func NewHandler(store *Store) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) Register(r chi.Router) {
+	r.Get("/ingestion", h.report)
+}
+
+// @Summary     Ingestion health
+// @Description Success rate and last success per source, most frequent errors and the latest attempts (worker and /v1/ingest). `status` only filters `recent`. To see why a streamer is missing use `entity_type=costream&entity_id=twitch:<login>`.
+// @Tags        admin
+// @Security    ApiKeyAuth
+// @Produce     json
+// @Param       source      query string false "twitch | riot | api"
+// @Param       status      query string false "success | offline | error" enums(success,offline,error)
+// @Param       since       query string false "RFC3339 time or duration back from now (default 24h)"
+// @Param       entity_type query string false "costream | match | patch | comp | pro_match | request"
+// @Param       entity_id   query string false "Entity id, e.g. twitch:<login>"
+// @Param       limit       query int    false "Recent attempts (default 50, max 500)"
+// @Success     200 {object} map[string]interface{} "Ingestion report"
+// @Failure     400 {string} string "invalid filter"
+// @Failure     500 {string} string "db error"
+// @Router      /v1/admin/ingestion [get]
+func (h *Handler) report(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	f := Filter{
+		Source:     q.Get("source"),
+		Status:     q.Get("status"),
+		EntityType: q.Get("entity_type"),
+		EntityID:   q.Get("entity_id"),
+		Since:      time.Now().Add(-24 * time.Hour),
+	}
+	switch f.Status {
+	case "", entities.IngestionStatusSuccess, entities.IngestionStatusOffline, entities.IngestionStatusError:
+	default:
+		http.Error(w, "status must be success, offline or error", http.StatusBadRequest)
+		return
+	}
+	if v := q.Get("since"); v != "" {
+		since, err := parseSince(v)
+		if err != nil {
+			http.Error(w, "since must be an RFC3339 time or a duration (e.g. 6h)", http.StatusBadRequest)
+			return
+		}
+		f.Since = since
+	}
+	if v := q.Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 500 {
+			http.Error(w, "limit must be between 1 and 500", http.StatusBadRequest)
+			return
+		}
+		f.Limit = n
+	}
+
+	rep, err := h.store.Report(r.Context(), f)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error building ingestion report: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "report": rep})
+}
+
+// parseSince acepta un instante RFC3339 o una duración hacia atrás desde ahora
+func parseSince(v string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return time.Time{}, fmt.Errorf("invalid since %q", v)
+	}
+	return time.Now().Add(-d), nil
+}
@@ -0,0 +1,86 @@
+package ingestion
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+)
+
+// maxErrorBody bytes de la respuesta de error que se guardan como error_msg
+const maxErrorBody = 1024
+
+type attemptKey struct{}
+
+// attempt entidad de la petición en curso, la completa el handler con Describe
+type attempt struct {
+	entityType string
+	entityID   string
+	recorded   bool
+}
+
+// Describe identifica la entidad que ingesta la petición (p.ej. "patch", "lol:14.20")
+func Describe(ctx context.Context, entityType, entityID string) {
+	if a, ok := ctx.Value(attemptKey{}).(*attempt); ok {
+		a.entityType, a.entityID = entityType, entityID
+	}
+}
+
+// Recorded indica que la capa de datos ya dejó su propia fila y el middleware no debe duplicarla
+func Recorded(ctx context.Context) {
+	if a, ok := ctx.Value(attemptKey{}).(*attempt); ok {
+		a.recorded = true
+	}
+}
+
+// Middleware deja una fila en ingestion_logs por petición: status error si la respuesta es
+// >= 400 (con el cuerpo de la respuesta como mensaje). Si el handler no llamó a Describe
+// (p.ej. JSON inválido) la entidad es "request" con la ruta.
+func Middleware(store *Store, source string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			a := &attempt{entityType: entities.IngestionEntityRequest, entityID: r.URL.Path}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			body := &limitedBuffer{max: maxErrorBody}
+			ww.Tee(body)
+
+			next.ServeHTTP(ww, r.WithContext(context.WithValue(r.Context(), attemptKey{}, a)))
+
+			if a.recorded {
+				return
+			}
+			var err error
+			if status := ww.Status(); status >= http.StatusBadRequest {
+				msg := strings.TrimSpace(body.String())
+				if msg == "" {
+					msg = http.StatusText(status)
+				}
+				err = errors.New(msg)
+			}
+			store.Record(r.Context(), source, a.entityType, a.entityID, err)
+		})
+	}
+}
+
+// limitedBuffer guarda los primeros max bytes escritos y descarta el resto
+type limitedBuffer struct {
+	max int
+	buf []byte
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - len(b.buf); room > 0 {
+		if len(p) > room {
+			b.buf = append(b.buf, p[:room]...)
+		} else {
+			b.buf = append(b.buf, p...)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string { return string(b.buf) }
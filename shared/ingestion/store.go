@@ -0,0 +1,200 @@
+package ingestion
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	"github.com/steven230500/hypeatlas-api/shared/db"
+	"github.com/steven230500/hypeatlas-api/shared/stats"
+)
+
+// Store auditoría de ingestas (app.ingestion_logs)
+type Store struct {
+	db *gorm.DB
+}
+
+// NewStore crea el store de auditoría sobre la base de datos
+func NewStore(g *gorm.DB) *Store {
+	return &Store{db: g}
+}
+
+// Record guarda un intento de ingesta; err nil es éxito. Si la escritura falla solo se loguea:
+// la auditoría no debe romper la ingesta.
+func (s *Store) Record(ctx context.Context, source, entityType, entityID string, err error) {
+	row := entities.IngestionLog{
+		Source:      source,
+		EntityType:  entityType,
+		EntityID:    truncate(entityID, 160),
+		Status:      entities.IngestionStatusSuccess,
+		ProcessedAt: time.Now(),
+	}
+	if err != nil {
+		row.Status = entities.IngestionStatusError
+		row.ErrorMsg = err.Error()
+	}
+	s.write(ctx, row)
+}
+
+// RecordOffline guarda una consulta correcta que no trajo nada que ingerir (p.ej. un creador
+// verificado que no está emitiendo), para distinguirla de un creador que nunca se consultó
+func (s *Store) RecordOffline(ctx context.Context, source, entityType, entityID string) {
+	s.write(ctx, entities.IngestionLog{
+		Source:      source,
+		EntityType:  entityType,
+		EntityID:    truncate(entityID, 160),
+		Status:      entities.IngestionStatusOffline,
+		ProcessedAt: time.Now(),
+	})
+}
+
+func (s *Store) write(ctx context.Context, row entities.IngestionLog) {
+	if err := db.Call(s.db.WithContext(context.WithoutCancel(ctx)).Create(&row)).Error; err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("source", row.Source).Str("entity_id", row.EntityID).Msg("write ingestion log failed")
+	}
+}
+
+// Filter filtros del reporte; Status solo filtra Recent (las tasas por fuente cuentan todos los estados)
+type Filter struct {
+	Source     string
+	Status     string
+	EntityType string
+	EntityID   string
+	Since      time.Time
+	Limit      int // filas de Recent
+}
+
+// Report resumen de ingestas desde Since
+type Report struct {
+	Since     time.Time               `json:"since"`
+	Sources   []SourceStats           `json:"sources"`
+	TopErrors []ErrorGroup            `json:"top_errors"`
+	Recent    []entities.IngestionLog `json:"recent"`
+}
+
+// SourceStats intentos y tasa de éxito (0-100) de una fuente; Succeeded incluye los offline
+type SourceStats struct {
+	Source        string     `json:"source"`
+	Total         int64      `json:"total"`
+	Succeeded     int64      `json:"succeeded"`
+	Offline       int64      `json:"offline"`
+	Failed        int64      `json:"failed"`
+	SuccessRate   float64    `json:"success_rate"`
+	LastSuccessAt *time.Time `json:"last_success_at,omitempty"`
+	LastErrorAt   *time.Time `json:"last_error_at,omitempty"`
+}
+
+// ErrorGroup un mensaje de error repetido
+type ErrorGroup struct {
+	Source     string    `json:"source"`
+	EntityType string    `json:"entity_type"`
+	ErrorMsg   string    `json:"error_msg"`
+	Count      int64     `json:"count"`
+	Entities   int64     `json:"entities"` // entity_id distintos afectados
+	LastSeenAt time.Time `json:"last_seen_at"`
+}
+
+const topErrors = 10
+
+// Report calcula tasas por fuente, errores más frecuentes y los últimos intentos
+func (s *Store) Report(ctx context.Context, f Filter) (*Report, error) {
+	if f.Limit <= 0 {
+		f.Limit = 50
+	}
+	rep := &Report{Since: f.Since, Sources: []SourceStats{}, TopErrors: []ErrorGroup{}, Recent: []entities.IngestionLog{}}
+
+	if err := db.Call(s.filtered(ctx, f).
+		Select(`source,
+  COUNT(*) AS total,
+  COUNT(*) FILTER (WHERE status <> 'error') AS succeeded,
+  COUNT(*) FILTER (WHERE status = 'offline') AS offline,
+  COUNT(*) FILTER (WHERE status = 'error') AS failed,
+  MAX(processed_at) FILTER (WHERE status <> 'error') AS last_success_at,
+  MAX(processed_at) FILTER (WHERE status = 'error') AS last_error_at`).
+		Group("source").Order("source").
+		Scan(&rep.Sources)).Error; err != nil {
+		return nil, err
+	}
+	for i := range rep.Sources {
+		if src := &rep.Sources[i]; src.Total > 0 {
+			src.SuccessRate = stats.Round(100*float64(src.Succeeded)/float64(src.Total), 2)
+		}
+	}
+
+	if err := db.Call(s.filtered(ctx, f).
+		Select(`source, entity_type, error_msg,
+  COUNT(*) AS count,
+  COUNT(DISTINCT entity_id) AS entities,
+  MAX(processed_at) AS last_seen_at`).
+		Where("status = ?", entities.IngestionStatusError).
+		Group("source, entity_type, error_msg").
+		Order("count DESC, last_seen_at DESC").
+		Limit(topErrors).
+		Scan(&rep.TopErrors)).Error; err != nil {
+		return nil, err
+	}
+
+	recent := s.filtered(ctx, f)
+	if f.Status != "" {
+		recent = recent.Where("status = ?", f.Status)
+	}
+	if err := db.Call(recent.Order("processed_at DESC").Limit(f.Limit).Find(&rep.Recent)).Error; err != nil {
+		return nil, err
+	}
+	return rep, nil
+}
+
+// filtered consulta base con los filtros comunes
+func (s *Store) filtered(ctx context.Context, f Filter) *gorm.DB {
+	q := s.db.WithContext(ctx).Model(&entities.IngestionLog{}).Where("processed_at >= ?", f.Since)
+	if f.Source != "" {
+		q = q.Where("source = ?", f.Source)
+	}
+	if f.EntityType != "" {
+		q = q.Where("entity_type = ?", f.EntityType)
+	}
+	if f.EntityID != "" {
+		q = q.Where("entity_id = ?", f.EntityID)
+	}
+	return q
+}
+
+// language=SQL
+const purgeSQL = `
+DELETE FROM app.ingestion_logs
+WHERE uuid IN (SELECT uuid FROM app.ingestion_logs WHERE processed_at < ? LIMIT ?)`
+
+const purgeBatch = 5000
+
+// Purge borra los registros anteriores a before por lotes, para no bloquear la tabla
+func (s *Store) Purge(ctx context.Context, before time.Time) (int64, error) {
+	var total int64
+	for {
+		result := db.Call(s.db.WithContext(ctx).Exec(purgeSQL, before, purgeBatch))
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if result.RowsAffected < purgeBatch {
+			return total, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+	}
+}
+
+// truncate corta s a n bytes sin partir un carácter UTF-8
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,151 @@
+package lifecycle
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Estados de un check y del servicio
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusDraining    = "draining"
+)
+
+// Check comprobación de una dependencia (base de datos, proveedor externo)
+type Check struct {
+	Name     string
+	Critical bool          // si falla, /readyz devuelve 503; si no, solo marca "degraded"
+	TTL      time.Duration // cuánto se reutiliza el último resultado (0 = siempre se ejecuta)
+	Run      func(ctx context.Context) error
+}
+
+// CheckResult resultado de un check
+type CheckResult struct {
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMs int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Health sirve /livez y /readyz
+type Health struct {
+	timeout  time.Duration
+	checks   []Check
+	draining atomic.Bool
+	mu       sync.Mutex
+	cache    map[string]CheckResult
+}
+
+// NewHealth crea el registro de checks; timeout limita cada check
+func NewHealth(timeout time.Duration) *Health {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Health{timeout: timeout, cache: make(map[string]CheckResult)}
+}
+
+// Add registra un check (antes de servir)
+func (h *Health) Add(c Check) {
+	h.checks = append(h.checks, c)
+}
+
+// SetDraining marca el servicio como en apagado: /readyz pasa a 503 para que el balanceador
+// deje de enviar tráfico mientras se drenan las peticiones en curso
+func (h *Health) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Live responde 200 mientras el proceso está vivo (no mira dependencias)
+func (h *Health) Live(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": StatusOK})
+}
+
+// Ready ejecuta los checks: 503 si falla uno crítico o si el servicio se está apagando
+func (h *Health) Ready(w http.ResponseWriter, r *http.Request) {
+	status, results := h.Evaluate(r.Context())
+	code := http.StatusOK
+	if status == StatusUnavailable || status == StatusDraining {
+		code = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(map[string]any{"status": status, "checks": results})
+}
+
+// Evaluate ejecuta los checks en paralelo y devuelve el estado agregado
+func (h *Health) Evaluate(ctx context.Context) (string, map[string]CheckResult) {
+	results := make(map[string]CheckResult, len(h.checks))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range h.checks {
+		wg.Add(1)
+		go func(c Check) {
+			defer wg.Done()
+			res := h.run(ctx, c)
+			mu.Lock()
+			results[c.Name] = res
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	status := StatusOK
+	names := make([]string, 0, len(results))
+	for name := range results {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		res := results[name]
+		if res.Status == StatusOK {
+			continue
+		}
+		if res.Critical {
+			status = StatusUnavailable
+		} else if status == StatusOK {
+			status = StatusDegraded
+		}
+	}
+	if h.draining.Load() {
+		status = StatusDraining
+	}
+	return status, results
+}
+
+// run ejecuta un check o reutiliza su último resultado si sigue vigente
+func (h *Health) run(ctx context.Context, c Check) CheckResult {
+	if c.TTL > 0 {
+		h.mu.Lock()
+		cached, ok := h.cache[c.Name]
+		h.mu.Unlock()
+		if ok && time.Since(cached.CheckedAt) < c.TTL {
+			return cached
+		}
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+	started := time.Now()
+	err := c.Run(checkCtx)
+	res := CheckResult{Status: StatusOK, Critical: c.Critical, LatencyMs: time.Since(started).Milliseconds(), CheckedAt: started}
+	if err != nil {
+		res.Status = StatusUnavailable
+		res.Error = err.Error()
+	}
+
+	if c.TTL > 0 {
+		h.mu.Lock()
+		h.cache[c.Name] = res
+		h.mu.Unlock()
+	}
+	return res
+}
@@ -0,0 +1,80 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SignalContext devuelve un contexto que se cancela con SIGINT o SIGTERM
+func SignalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(parent, os.Interrupt, syscall.SIGTERM)
+}
+
+// NewServer crea un http.Server con timeouts. HTTP_READ_TIMEOUT, HTTP_READ_HEADER_TIMEOUT,
+// HTTP_WRITE_TIMEOUT e HTTP_IDLE_TIMEOUT (duraciones) reemplazan los valores por defecto.
+func NewServer(addr string, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadTimeout:       EnvDuration("HTTP_READ_TIMEOUT", 15*time.Second),
+		ReadHeaderTimeout: EnvDuration("HTTP_READ_HEADER_TIMEOUT", 5*time.Second),
+		WriteTimeout:      EnvDuration("HTTP_WRITE_TIMEOUT", 90*time.Second), // los reportes en vivo esperan al rate limit de Riot
+		IdleTimeout:       EnvDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+	}
+}
+
+// Serve atiende srv hasta que se cancele ctx; entonces llama a onShutdown (p.ej. marcar
+// /readyz como no listo), sigue atendiendo durante delay para que el balanceador vea el 503
+// y saque la instancia, deja de aceptar conexiones y espera hasta drain a que terminen las
+// peticiones en curso
+func Serve(ctx context.Context, srv *http.Server, delay, drain time.Duration, onShutdown func()) error {
+	errCh := make(chan error, 1)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Info().Dur("delay", delay).Dur("drain", drain).Msg("shutting down http server")
+	if onShutdown != nil {
+		onShutdown()
+	}
+	if delay > 0 {
+		select {
+		case err := <-errCh:
+			return err
+		case <-time.After(delay):
+		}
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), drain)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		_ = srv.Close()
+		return err
+	}
+	return <-errCh
+}
+
+// EnvDuration lee una duración (p.ej. 30s, 2m) del entorno con valor por defecto
+func EnvDuration(key string, def time.Duration) time.Duration {
+	if v := os.Getenv(key); v != "" {
+		if d, err := time.ParseDuration(v); err == nil && d >= 0 {
+			return d
+		}
+	}
+	return def
+}
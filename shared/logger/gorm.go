@@ -0,0 +1,73 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gorm.io/gorm"
+	glogger "gorm.io/gorm/logger"
+)
+
+// Gorm adapta el logger de GORM a zerolog: usa el logger del contexto (request_id, job),
+// errores en error (salvo record not found), queries más lentas que slow en warn y el
+// resto solo en trace
+type Gorm struct {
+	slow  time.Duration
+	level glogger.LogLevel
+}
+
+// NewGorm crea el adaptador; slow <= 0 desactiva el aviso de queries lentas
+func NewGorm(slow time.Duration) *Gorm {
+	return &Gorm{slow: slow, level: glogger.Warn}
+}
+
+func (g *Gorm) LogMode(level glogger.LogLevel) glogger.Interface {
+	c := *g
+	c.level = level
+	return &c
+}
+
+func (g *Gorm) Info(ctx context.Context, msg string, args ...any) {
+	if g.level >= glogger.Info {
+		log.Ctx(ctx).Info().Msgf(msg, args...)
+	}
+}
+
+func (g *Gorm) Warn(ctx context.Context, msg string, args ...any) {
+	if g.level >= glogger.Warn {
+		log.Ctx(ctx).Warn().Msgf(msg, args...)
+	}
+}
+
+func (g *Gorm) Error(ctx context.Context, msg string, args ...any) {
+	if g.level >= glogger.Error {
+		log.Ctx(ctx).Error().Msgf(msg, args...)
+	}
+}
+
+func (g *Gorm) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if g.level <= glogger.Silent {
+		return
+	}
+	l := log.Ctx(ctx)
+	elapsed := time.Since(begin)
+	var event *zerolog.Event
+	msg := "db query"
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && g.level >= glogger.Error:
+		event, msg = l.Error().Err(err), "db query failed"
+	case g.slow > 0 && elapsed > g.slow && g.level >= glogger.Warn:
+		event, msg = l.Warn().Dur("slow_threshold", g.slow), "slow db query"
+	default:
+		event = l.Trace()
+	}
+	// fc arma el SQL con los valores: solo si la línea se va a escribir
+	if !event.Enabled() {
+		return
+	}
+	sql, rows := fc()
+	event.Str("sql", sql).Int64("rows", rows).Dur("duration", elapsed).Msg(msg)
+}
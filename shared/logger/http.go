@@ -0,0 +1,58 @@
+package logger
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+)
+
+// Middleware va después de middleware.RequestID: guarda en el contexto un logger con el
+// request_id (lo usan repositorios y proveedores vía log.Ctx), lo devuelve en X-Request-Id
+// y escribe una línea de acceso por petición
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started := time.Now()
+		reqID := middleware.GetReqID(r.Context())
+		l := log.Logger.With().Str("request_id", reqID).Logger()
+		if reqID != "" {
+			w.Header().Set("X-Request-Id", reqID)
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r.WithContext(l.WithContext(r.Context())))
+
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		event := l.Info()
+		switch {
+		case status >= http.StatusInternalServerError:
+			event = l.Error()
+		case status >= http.StatusBadRequest:
+			event = l.Warn()
+		case r.URL.Path == "/livez" || r.URL.Path == "/healthz" || r.URL.Path == "/readyz" || r.URL.Path == "/metrics":
+			// sondas y scrapes: solo en debug
+			event = l.Debug()
+		}
+		event.
+			Str("method", r.Method).
+			Str("path", r.URL.Path).
+			Str("route", routePattern(r)).
+			Int("status", status).
+			Int("bytes", ww.BytesWritten()).
+			Dur("duration", time.Since(started)).
+			Str("remote", r.RemoteAddr).
+			Msg("http request")
+	})
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+	return ""
+}
@@ -1,11 +1,44 @@
 package logger
 
 import (
+	"context"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
+// New configura el logger global (github.com/rs/zerolog/log) y lo devuelve:
+//   - LOG_LEVEL: trace|debug|info|warn|error (default info)
+//   - LOG_FORMAT=console: salida legible para desarrollo (default JSON)
+//
+// La salida pasa por un Redactor con los secretos del entorno. log.Ctx(ctx) devuelve el logger
+// de la petición (con request_id) o, sin él, este mismo logger.
 func New() zerolog.Logger {
-	return zerolog.New(os.Stdout).With().Timestamp().Logger()
+	level, err := zerolog.ParseLevel(strings.ToLower(os.Getenv("LOG_LEVEL")))
+	if err != nil || level == zerolog.NoLevel {
+		level = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(level)
+	zerolog.DurationFieldUnit = time.Millisecond
+
+	out := NewRedactor(os.Stdout, SecretsFromEnv()...)
+	var l zerolog.Logger
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "console") {
+		l = zerolog.New(zerolog.ConsoleWriter{Out: out, TimeFormat: time.RFC3339})
+	} else {
+		l = zerolog.New(out)
+	}
+	l = l.With().Timestamp().Logger()
+
+	log.Logger = l
+	zerolog.DefaultContextLogger = &log.Logger
+	return l
+}
+
+// Ctx logger del contexto (request_id, job) o el global si no tiene
+func Ctx(ctx context.Context) *zerolog.Logger {
+	return log.Ctx(ctx)
 }
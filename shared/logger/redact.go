@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"bytes"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const redacted = "***"
+
+// secretEnv variables cuyos valores nunca deben aparecer en los logs
+var secretEnv = []string{"RIOT_API_KEY", "TWITCH_SECRET", "API_KEYS", "POSTGRES_PASSWORD"}
+
+// secretPatterns secretos reconocibles aunque no vengan del entorno
+var secretPatterns = []struct {
+	re   *regexp.Regexp
+	repl string
+}{
+	{regexp.MustCompile(`RGAPI-[0-9a-fA-F-]{8,}`), redacted},                                            // API key de Riot
+	{regexp.MustCompile(`(?i)((?:password|passwd|secret|token|api_key)=)[^\s&"']+`), "${1}" + redacted}, // query strings y DSN
+	{regexp.MustCompile(`(?i)((?:Bearer|Basic) )[A-Za-z0-9._~+/=-]+`), "${1}" + redacted},               // cabeceras Authorization
+	{regexp.MustCompile(`(://[^:/\s@]+:)[^@\s/]+@`), "${1}" + redacted + "@"},                           // usuario:contraseña en URLs
+}
+
+// Redactor io.Writer que reemplaza secretos conocidos antes de escribir cada línea de log
+type Redactor struct {
+	out     io.Writer
+	secrets [][]byte
+}
+
+// NewRedactor envuelve out; secrets son valores literales a ocultar (los vacíos se ignoran)
+func NewRedactor(out io.Writer, secrets ...string) *Redactor {
+	r := &Redactor{out: out}
+	for _, s := range secrets {
+		// valores muy cortos reemplazarían texto normal
+		if len(s) >= 6 {
+			r.secrets = append(r.secrets, []byte(s))
+		}
+	}
+	return r
+}
+
+func (r *Redactor) Write(p []byte) (int, error) {
+	clean := p
+	for _, s := range r.secrets {
+		clean = bytes.ReplaceAll(clean, s, []byte(redacted))
+	}
+	for _, pat := range secretPatterns {
+		clean = pat.re.ReplaceAll(clean, []byte(pat.repl))
+	}
+	if _, err := r.out.Write(clean); err != nil {
+		return 0, err
+	}
+	// zerolog espera que se informe el largo original
+	return len(p), nil
+}
+
+// SecretsFromEnv valores secretos del entorno: API keys, secretos de Twitch y la contraseña de POSTGRES_URL
+func SecretsFromEnv() []string {
+	var secrets []string
+	for _, key := range secretEnv {
+		for _, v := range strings.Split(os.Getenv(key), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				secrets = append(secrets, v)
+			}
+		}
+	}
+	if u, err := url.Parse(os.Getenv("POSTGRES_URL")); err == nil && u.User != nil {
+		if pw, ok := u.User.Password(); ok {
+			secrets = append(secrets, pw)
+		}
+	}
+	return secrets
+}
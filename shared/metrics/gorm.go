@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const startedKey = "metrics:started"
+
+// InstrumentDB registra callbacks de GORM que miden la latencia de cada query por operación
+// y tabla (las queries Raw/Exec sin modelo van con table "raw")
+func InstrumentDB(g *gorm.DB) error {
+	cb := g.Callback()
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("metrics:before_create", startQuery),
+		cb.Create().After("gorm:create").Register("metrics:after_create", finishQuery("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", startQuery),
+		cb.Query().After("gorm:query").Register("metrics:after_query", finishQuery("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", startQuery),
+		cb.Update().After("gorm:update").Register("metrics:after_update", finishQuery("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", startQuery),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", finishQuery("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", startQuery),
+		cb.Row().After("gorm:row").Register("metrics:after_row", finishQuery("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", startQuery),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", finishQuery("raw")),
+	)
+}
+
+func startQuery(tx *gorm.DB) {
+	tx.InstanceSet(startedKey, time.Now())
+}
+
+func finishQuery(operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) { observeQuery(tx, operation) }
+}
+
+func observeQuery(tx *gorm.DB, operation string) {
+	v, ok := tx.InstanceGet(startedKey)
+	if !ok {
+		return
+	}
+	started, ok := v.(time.Time)
+	if !ok {
+		return
+	}
+	table := tx.Statement.Table
+	if table == "" {
+		table = "raw"
+	}
+	status := "ok"
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		status = "error"
+	}
+	dbDuration.WithLabelValues(operation, table, status).Observe(time.Since(started).Seconds())
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog/log"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+)
+
+// liveCollector consulta los co-streams en vivo en cada scrape (un gauge por juego)
+type liveCollector struct {
+	fetch   func(ctx context.Context) ([]entities.LiveGameStats, error)
+	streams *prometheus.Desc
+	viewers *prometheus.Desc
+}
+
+// RegisterLive registra los gauges de co-streams en vivo y espectadores por juego
+func RegisterLive(fetch func(ctx context.Context) ([]entities.LiveGameStats, error)) error {
+	return prometheus.Register(&liveCollector{
+		fetch:   fetch,
+		streams: prometheus.NewDesc(namespace+"_live_costreams", "Live co-streams by game.", []string{"game"}, nil),
+		viewers: prometheus.NewDesc(namespace+"_live_viewers", "Total viewers of live co-streams by game.", []string{"game"}, nil),
+	})
+}
+
+func (c *liveCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.streams
+	ch <- c.viewers
+}
+
+func (c *liveCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	rows, err := c.fetch(ctx)
+	if err != nil {
+		// sin datos el scrape sigue con el resto de métricas
+		log.Error().Err(err).Msg("collect live co-stream metrics failed")
+		return
+	}
+	for _, row := range rows {
+		ch <- prometheus.MustNewConstMetric(c.streams, prometheus.GaugeValue, float64(row.Streams), row.Game)
+		ch <- prometheus.MustNewConstMetric(c.viewers, prometheus.GaugeValue, float64(row.Viewers), row.Game)
+	}
+}
@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "hypeatlas"
+
+var (
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by chi route pattern, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	dbDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Database query latency by GORM operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"operation", "table", "status"})
+
+	jobDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "job_duration_seconds",
+		Help:      "Worker job run duration.",
+		Buckets:   []float64{.1, .5, 1, 5, 15, 30, 60, 120, 300, 600, 1200, 1800, 3600},
+	}, []string{"job"})
+
+	jobRuns = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "job_runs_total",
+		Help:      "Worker job runs by final status (succeeded, failed, timed_out) or skipped (overlap).",
+	}, []string{"job", "status"})
+
+	jobLastSuccess = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "job_last_success_timestamp_seconds",
+		Help:      "Unix time of the last successful run of a worker job.",
+	}, []string{"job"})
+
+	providerRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_requests_total",
+		Help:      "Outbound requests to Twitch and Riot by endpoint and HTTP status (\"error\" when no response).",
+	}, []string{"provider", "endpoint", "status"})
+
+	providerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_request_duration_seconds",
+		Help:      "Outbound request latency to Twitch and Riot, without rate limiter wait.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 30},
+	}, []string{"provider", "endpoint"})
+
+	providerThrottled = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "provider_throttled_total",
+		Help:      "Outbound requests answered with 429 Too Many Requests.",
+	}, []string{"provider", "endpoint"})
+
+	rateLimitWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "provider_rate_limit_wait_seconds",
+		Help:      "Time spent waiting on the client-side rate limiter before a request.",
+		Buckets:   []float64{0, .01, .1, .5, 1, 2, 5, 10, 30, 60, 120},
+	}, []string{"provider", "endpoint"})
+)
+
+// Handler sirve las métricas en formato Prometheus
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveJob registra el resultado y la duración de una ejecución de un job
+func ObserveJob(job, status string, d time.Duration, succeeded bool) {
+	jobDuration.WithLabelValues(job).Observe(d.Seconds())
+	jobRuns.WithLabelValues(job, status).Inc()
+	if succeeded {
+		jobLastSuccess.WithLabelValues(job).SetToCurrentTime()
+	}
+}
+
+// JobSkipped cuenta una ejecución omitida porque la anterior seguía corriendo
+func JobSkipped(job string) {
+	jobRuns.WithLabelValues(job, "skipped").Inc()
+}
+
+// ObserveProviderCall registra una llamada saliente; status 0 es que no hubo respuesta
+func ObserveProviderCall(provider, endpoint string, status int, d time.Duration) {
+	label := "error"
+	if status > 0 {
+		label = strconv.Itoa(status)
+	}
+	providerRequests.WithLabelValues(provider, endpoint, label).Inc()
+	providerDuration.WithLabelValues(provider, endpoint).Observe(d.Seconds())
+	if status == http.StatusTooManyRequests {
+		providerThrottled.WithLabelValues(provider, endpoint).Inc()
+	}
+}
+
+// ObserveRateLimitWait registra la espera en el rate limiter antes de una llamada
+func ObserveRateLimitWait(provider, endpoint string, d time.Duration) {
+	rateLimitWait.WithLabelValues(provider, endpoint).Observe(d.Seconds())
+}
@@ -0,0 +1,94 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// Handler endpoints de administración de jobs (/v1/admin/jobs)
+type Handler struct {
+	store Store
+}
+
+// NewHandler crea el handler de administración de jobs
+func NewHandler(store Store) *Handler {
+	return &Handler{store: store}
+}
+
+func (h *Handler) Register(r chi.Router) {
+	r.Route("/jobs", func(r chi.Router) {
+		r.Get("/", h.listJobs)
+		r.Get("/{name}", h.getJob)
+		r.Post("/{name}/run", h.runJob)
+	})
+}
+
+// @Summary     List worker jobs
+// @Description Schedule, next run and last run status, duration and error of every job registered by the worker
+// @Tags        admin
+// @Security    ApiKeyAuth
+// @Produce     json
+// @Success     200 {object} map[string]interface{} "Jobs"
+// @Failure     500 {string} string "db error"
+// @Router      /v1/admin/jobs [get]
+func (h *Handler) listJobs(w http.ResponseWriter, r *http.Request) {
+	jobs, err := h.store.States(r.Context())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error listing jobs: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "jobs": jobs})
+}
+
+// @Summary     Get a worker job
+// @Tags        admin
+// @Security    ApiKeyAuth
+// @Produce     json
+// @Param       name path string true "Job name"
+// @Success     200 {object} map[string]interface{} "Job"
+// @Failure     404 {string} string "job not found"
+// @Failure     500 {string} string "db error"
+// @Router      /v1/admin/jobs/{name} [get]
+func (h *Handler) getJob(w http.ResponseWriter, r *http.Request) {
+	job, err := h.store.State(r.Context(), chi.URLParam(r, "name"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error getting job: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if job == nil {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "job": job})
+}
+
+// @Summary     Trigger a worker job
+// @Description Ask the worker to run the job now. The worker picks the request up on its next poll (SCHEDULER_POLL_SEC); a run already in progress is not duplicated.
+// @Tags        admin
+// @Security    ApiKeyAuth
+// @Produce     json
+// @Param       name path string true "Job name"
+// @Success     202 {object} map[string]interface{} "Run requested"
+// @Failure     404 {string} string "job not found"
+// @Failure     500 {string} string "db error"
+// @Router      /v1/admin/jobs/{name}/run [post]
+func (h *Handler) runJob(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	ok, err := h.store.RequestRun(r.Context(), name)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error requesting job run: %v", err), http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		http.Error(w, "job not found", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]any{"success": true, "job": name, "message": "run requested"})
+}
@@ -0,0 +1,119 @@
+package scheduler
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync"
+)
+
+// Locker reparte los jobs entre réplicas del worker: solo la réplica que tiene el lock
+// de un job lo ejecuta
+type Locker interface {
+	// Acquire intenta tomar el lock del job; true si esta réplica lo tiene (o ya lo tenía)
+	Acquire(ctx context.Context, name string) (bool, error)
+	// Held indica si esta réplica tiene el lock del job
+	Held(name string) bool
+	// Check verifica que los locks siguen vigentes; si se perdieron, Held pasa a false
+	Check(ctx context.Context) error
+	// Close libera todos los locks
+	Close(ctx context.Context) error
+}
+
+// lockNamespace primer entero de las advisory locks de jobs (el segundo es hashtext(nombre))
+const lockNamespace = 4401
+
+// language=SQL
+const (
+	tryLockSQL   = `SELECT pg_try_advisory_lock($1, hashtext($2))`
+	unlockAllSQL = `SELECT pg_advisory_unlock_all()`
+)
+
+// PgLocker Locker con advisory locks de sesión de Postgres. Todas las locks de la réplica
+// viven en una conexión dedicada: si la réplica muere o la conexión se corta, Postgres
+// las libera y otra réplica las toma en su siguiente intento.
+type PgLocker struct {
+	db   *sql.DB
+	mu   sync.Mutex
+	conn *sql.Conn
+	held map[string]bool
+}
+
+// NewPgLocker crea un locker sobre el pool de la base de datos
+func NewPgLocker(db *sql.DB) *PgLocker {
+	return &PgLocker{db: db, held: make(map[string]bool)}
+}
+
+func (l *PgLocker) Acquire(ctx context.Context, name string) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// pg_try_advisory_lock es reentrante en la misma sesión: no se vuelve a pedir
+	if l.held[name] {
+		return true, nil
+	}
+	if l.conn == nil {
+		conn, err := l.db.Conn(ctx)
+		if err != nil {
+			return false, err
+		}
+		l.conn = conn
+	}
+	var ok bool
+	if err := l.conn.QueryRowContext(ctx, tryLockSQL, lockNamespace, name).Scan(&ok); err != nil {
+		l.dropSession()
+		return false, err
+	}
+	if ok {
+		l.held[name] = true
+	}
+	return ok, nil
+}
+
+func (l *PgLocker) Held(name string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held[name]
+}
+
+func (l *PgLocker) Check(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	if err := l.conn.PingContext(ctx); err != nil {
+		l.dropSession()
+		return err
+	}
+	return nil
+}
+
+func (l *PgLocker) Close(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conn == nil {
+		return nil
+	}
+	_, err := l.conn.ExecContext(ctx, unlockAllSQL)
+	l.held = make(map[string]bool)
+	return errors.Join(err, l.closeConn())
+}
+
+// dropSession descarta la conexión; sus locks se consideran perdidos
+func (l *PgLocker) dropSession() {
+	l.held = make(map[string]bool)
+	_ = l.closeConn()
+}
+
+func (l *PgLocker) closeConn() error {
+	if l.conn == nil {
+		return nil
+	}
+	// Raw con driver.ErrBadConn saca la conexión del pool: cerrarla cierra la sesión
+	// y Postgres suelta las locks aunque ya no respondiera al ping
+	_ = l.conn.Raw(func(any) error { return driver.ErrBadConn })
+	err := l.conn.Close()
+	l.conn = nil
+	return err
+}
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string // String() de la Schedule; "" = error
+	}{
+		{"30m", "@every 30m0s"},
+		{"@every 1h30m", "@every 1h30m0s"},
+		{"  @every 45s ", "@every 45s"},
+		{"@hourly", "0 * * * *"},
+		{"@daily", "0 0 * * *"},
+		{"@midnight", "0 0 * * *"},
+		{"@weekly", "0 0 * * 0"},
+		{"*/5 8-18 * * 1-5", "*/5 8-18 * * 1-5"},
+		{"", ""},
+		{"0s", ""},
+		{"-5m", ""},
+		{"@every 0s", ""},
+		{"5", ""},
+		{"@yearly", ""},
+	}
+	for _, tt := range tests {
+		s, err := ParseSchedule(tt.spec)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("ParseSchedule(%q) = %v, want error", tt.spec, s)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseSchedule(%q): %v", tt.spec, err)
+			continue
+		}
+		if got := s.String(); got != tt.want {
+			t.Errorf("ParseSchedule(%q) = %q, want %q", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseCronErrors(t *testing.T) {
+	for _, spec := range []string{
+		"* * * *",       // faltan campos
+		"* * * * * *",   // sobran campos
+		"60 * * * *",    // minuto fuera de rango
+		"* 24 * * *",    // hora fuera de rango
+		"* * 0 * *",     // día del mes empieza en 1
+		"* * 32 * *",    // día del mes fuera de rango
+		"* * * 0 *",     // mes empieza en 1
+		"* * * 13 *",    // mes fuera de rango
+		"* * * * 8",     // día de la semana fuera de rango (7 sí vale)
+		"*/0 * * * *",   // paso nulo
+		"*/x * * * *",   // paso no numérico
+		"5-1 * * * *",   // rango invertido
+		"a * * * *",     // valor no numérico
+		"1-b * * * *",   // fin de rango no numérico
+		"1,,2 * * * *",  // elemento vacío en la lista
+		"0 0 * JAN MON", // nombres no soportados
+	} {
+		if _, err := ParseCron(spec); err == nil {
+			t.Errorf("ParseCron(%q) = nil error, want error", spec)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	at := func(s string) time.Time {
+		t.Helper()
+		v, err := time.Parse("2006-01-02 15:04:05", s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	// 2024-01-01 es lunes
+	tests := []struct {
+		name  string
+		spec  string
+		after string
+		want  string // "" = sin próxima ejecución
+	}{
+		{"step", "*/15 * * * *", "2024-01-01 10:07:30", "2024-01-01 10:15:00"},
+		{"strictly after", "0 * * * *", "2024-01-01 10:00:00", "2024-01-01 11:00:00"},
+		{"list", "5,35 * * * *", "2024-01-01 10:06:00", "2024-01-01 10:35:00"},
+		{"range with step rolls to next day", "30 8-18/2 * * *", "2024-01-01 18:31:00", "2024-01-02 08:30:00"},
+		{"range with step", "30 8-18/2 * * *", "2024-01-01 09:00:00", "2024-01-01 10:30:00"},
+		{"single value with step", "0 20/2 * * *", "2024-01-01 21:00:00", "2024-01-01 22:00:00"},
+		{"weekdays skip weekend", "0 9 * * 1-5", "2024-01-05 10:00:00", "2024-01-08 09:00:00"},
+		{"7 is sunday", "0 0 * * 7", "2024-01-01 00:00:00", "2024-01-07 00:00:00"},
+		{"0 is sunday", "0 0 * * 0", "2024-01-01 00:00:00", "2024-01-07 00:00:00"},
+		{"day of month list", "0 0 1,15 * *", "2024-01-02 00:00:00", "2024-01-15 00:00:00"},
+		{"dom or dow: weekday first", "0 0 13 * 5", "2024-01-01 00:00:00", "2024-01-05 00:00:00"},
+		{"dom or dow: day of month first", "0 0 13 * 5", "2024-01-12 00:00:00", "2024-01-13 00:00:00"},
+		{"dom restricted, dow star", "0 0 13 * *", "2024-01-01 00:00:00", "2024-01-13 00:00:00"},
+		{"dow restricted, dom star", "0 0 * * 5", "2024-01-06 00:00:00", "2024-01-12 00:00:00"},
+		{"month step", "0 12 1 */3 *", "2024-01-01 12:00:00", "2024-04-01 12:00:00"},
+		{"month rolls over year", "0 0 1 1 *", "2024-06-15 00:00:00", "2025-01-01 00:00:00"},
+		{"leap day", "0 0 29 2 *", "2024-03-01 00:00:00", "2028-02-29 00:00:00"},
+		{"never", "0 0 31 2 *", "2024-01-01 00:00:00", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := ParseCron(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := c.Next(at(tt.after))
+			if tt.want == "" {
+				if !got.IsZero() {
+					t.Fatalf("Next = %s, want zero time", got)
+				}
+				return
+			}
+			if want := at(tt.want); !got.Equal(want) {
+				t.Fatalf("Next(%s) = %s, want %s", tt.after, got, want)
+			}
+		})
+	}
+}
+
+func TestEveryNext(t *testing.T) {
+	after := time.Date(2024, 1, 1, 10, 7, 30, 0, time.UTC)
+	if got := Every(90 * time.Second).Next(after); !got.Equal(after.Add(90 * time.Second)) {
+		t.Fatalf("Next = %s", got)
+	}
+}
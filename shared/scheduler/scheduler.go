@@ -0,0 +1,357 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	"github.com/steven230500/hypeatlas-api/shared/metrics"
+)
+
+// ErrUnknownJob el job pedido no está registrado
+var ErrUnknownJob = errors.New("unknown job")
+
+// Job tarea programada del worker
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Jitter   time.Duration // retraso aleatorio [0, Jitter) sumado a cada ejecución programada
+	Timeout  time.Duration // 0 = sin límite
+	Run      func(ctx context.Context) error
+}
+
+// entry job registrado con su estado en memoria
+type entry struct {
+	job     Job
+	next    time.Time
+	running atomic.Bool
+}
+
+// Scheduler ejecuta jobs con schedules independientes. Un job no se solapa consigo mismo:
+// si sigue corriendo cuando le toca, esa ejecución se salta. El estado de cada ejecución
+// queda en el Store y los pedidos manuales (POST /v1/admin/jobs/{name}/run) se leen de ahí.
+type Scheduler struct {
+	store    Store
+	locker   Locker
+	instance string
+	poll     time.Duration
+	grace    time.Duration
+	mu       sync.Mutex
+	entries  map[string]*entry
+	wg       sync.WaitGroup
+}
+
+// New crea un scheduler; poll es cada cuánto se revisan los pedidos manuales
+func New(store Store, poll time.Duration) *Scheduler {
+	if poll <= 0 {
+		poll = 5 * time.Second
+	}
+	host, _ := os.Hostname()
+	return &Scheduler{
+		store:    store,
+		instance: fmt.Sprintf("%s-%d", host, os.Getpid()),
+		poll:     poll,
+		grace:    time.Minute,
+		entries:  make(map[string]*entry),
+	}
+}
+
+// SetLocker activa el reparto de jobs entre réplicas: cada job corre solo en la réplica
+// que tiene su lock, y las demás lo intentan tomar en cada poll (failover)
+func (s *Scheduler) SetLocker(l Locker) {
+	s.locker = l
+}
+
+// Instance identificador de esta réplica (host-pid), guardado como owner de los jobs que corre
+func (s *Scheduler) Instance() string {
+	return s.instance
+}
+
+// SetGracePeriod cuánto se espera al apagar a que terminen los jobs en curso antes de
+// cancelar su contexto (default 1 minuto)
+func (s *Scheduler) SetGracePeriod(d time.Duration) {
+	s.grace = d
+}
+
+// Add registra un job (antes de Run)
+func (s *Scheduler) Add(job Job) error {
+	if job.Name == "" || job.Schedule == nil || job.Run == nil {
+		return fmt.Errorf("job needs a name, a schedule and a run function")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.entries[job.Name]; ok {
+		return fmt.Errorf("job %s already registered", job.Name)
+	}
+	s.entries[job.Name] = &entry{job: job}
+	return nil
+}
+
+// Jobs nombres de los jobs registrados
+func (s *Scheduler) Jobs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.entries))
+	for name := range s.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Run registra los jobs en el store, corre cada uno en cuanto arranca y luego según su
+// schedule, hasta que se cancele ctx. Entonces deja de lanzar jobs y espera a que terminen
+// los que están corriendo; si pasa el grace period les cancela el contexto.
+func (s *Scheduler) Run(ctx context.Context) error {
+	// Los jobs no heredan la cancelación de ctx: un apagado no corta un upsert a mitad
+	jobCtx, cancelJobs := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelJobs()
+
+	now := time.Now()
+	for _, name := range s.Jobs() {
+		s.mu.Lock()
+		e := s.entries[name]
+		e.next = now.Add(jitter(e.job.Jitter))
+		next := e.next
+		s.mu.Unlock()
+		if err := s.store.Register(ctx, name, e.job.Schedule.String(), timeoutString(e.job.Timeout), next); err != nil {
+			return fmt.Errorf("error registering job %s: %w", name, err)
+		}
+		log.Info().Str("job", name).Str("schedule", e.job.Schedule.String()).Dur("timeout", e.job.Timeout).Msg("job registered")
+	}
+
+	s.refreshLocks(ctx)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	lastPoll := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			s.drain(cancelJobs)
+			s.releaseLocks(ctx)
+			return nil
+		case now := <-ticker.C:
+			for _, e := range s.due(now) {
+				s.start(jobCtx, e, "schedule")
+			}
+			if now.Sub(lastPoll) >= s.poll {
+				lastPoll = now
+				s.refreshLocks(ctx)
+				s.claimTriggers(ctx, jobCtx)
+			}
+		}
+	}
+}
+
+// owns indica si esta réplica debe correr el job (siempre, sin locker)
+func (s *Scheduler) owns(name string) bool {
+	return s.locker == nil || s.locker.Held(name)
+}
+
+// refreshLocks verifica los locks que tiene esta réplica e intenta tomar los demás
+func (s *Scheduler) refreshLocks(ctx context.Context) {
+	if s.locker == nil {
+		return
+	}
+	if err := s.locker.Check(ctx); err != nil {
+		log.Error().Err(err).Msg("job locks lost")
+	}
+	for _, name := range s.Jobs() {
+		if s.locker.Held(name) {
+			continue
+		}
+		ok, err := s.locker.Acquire(ctx, name)
+		if err != nil {
+			log.Error().Err(err).Str("job", name).Msg("job lock failed")
+			return
+		}
+		if ok {
+			log.Info().Str("job", name).Str("instance", s.instance).Msg("job lock acquired")
+		}
+	}
+}
+
+// releaseLocks libera los locks al apagar para que otra réplica tome los jobs enseguida
+func (s *Scheduler) releaseLocks(ctx context.Context) {
+	if s.locker == nil {
+		return
+	}
+	closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.locker.Close(closeCtx); err != nil {
+		log.Error().Err(err).Msg("job locks release failed")
+	}
+}
+
+// Trigger ejecuta un job ahora (en este proceso) sin esperar a que termine
+func (s *Scheduler) Trigger(ctx context.Context, name string) error {
+	s.mu.Lock()
+	e, ok := s.entries[name]
+	s.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownJob, name)
+	}
+	s.start(ctx, e, "manual")
+	return nil
+}
+
+// drain espera a los jobs en curso hasta el grace period y luego los cancela
+func (s *Scheduler) drain(cancelJobs context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+	log.Info().Dur("grace", s.grace).Msg("scheduler stopping, waiting for running jobs")
+	select {
+	case <-done:
+	case <-time.After(s.grace):
+		log.Warn().Msg("grace period over, cancelling running jobs")
+		cancelJobs()
+		<-done
+	}
+}
+
+func (s *Scheduler) claimTriggers(ctx, jobCtx context.Context) {
+	var owned []string
+	for _, name := range s.Jobs() {
+		if s.owns(name) {
+			owned = append(owned, name)
+		}
+	}
+	if len(owned) == 0 {
+		return
+	}
+	// Solo se reclaman los pedidos de jobs propios: los demás los toma la réplica dueña
+	names, err := s.store.ClaimTriggers(ctx, owned)
+	if err != nil {
+		log.Error().Err(err).Msg("job trigger poll failed")
+		return
+	}
+	for _, name := range names {
+		if err := s.Trigger(jobCtx, name); err != nil {
+			log.Warn().Err(err).Msg("job trigger ignored")
+		}
+	}
+}
+
+// due devuelve los jobs de esta réplica a los que les toca correr y les calcula la próxima
+// ejecución (también a los de otras réplicas, para que un failover no los corra de golpe)
+func (s *Scheduler) due(now time.Time) []*entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*entry
+	for name, e := range s.entries {
+		if now.Before(e.next) {
+			continue
+		}
+		e.next = s.nextRun(e, now)
+		if s.owns(name) {
+			due = append(due, e)
+		}
+	}
+	return due
+}
+
+func (s *Scheduler) nextRun(e *entry, now time.Time) time.Time {
+	next := e.job.Schedule.Next(now)
+	if next.IsZero() {
+		// Cron sin próxima fecha: no se vuelve a programar
+		return now.AddDate(100, 0, 0)
+	}
+	return next.Add(jitter(e.job.Jitter))
+}
+
+// start lanza el job en una goroutine si no está corriendo ya
+func (s *Scheduler) start(ctx context.Context, e *entry, reason string) {
+	if !e.running.CompareAndSwap(false, true) {
+		log.Warn().Str("job", e.job.Name).Str("reason", reason).Msg("job still running, skipping")
+		metrics.JobSkipped(e.job.Name)
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer e.running.Store(false)
+		s.execute(ctx, e, reason)
+	}()
+}
+
+func (s *Scheduler) execute(ctx context.Context, e *entry, reason string) {
+	name := e.job.Name
+	started := time.Now()
+	if err := s.store.Started(ctx, name, s.instance, started); err != nil {
+		log.Error().Err(err).Str("job", name).Msg("job state update failed")
+	}
+
+	// Logs de repositorios y proveedores durante el job llevan job y run (vía log.Ctx)
+	jobLog := log.With().Str("job", name).Str("run", strconv.FormatInt(started.UnixMilli(), 36)).Logger()
+	ctx = jobLog.WithContext(ctx)
+	runCtx, cancel := ctx, context.CancelFunc(func() {})
+	if e.job.Timeout > 0 {
+		runCtx, cancel = context.WithTimeout(ctx, e.job.Timeout)
+	}
+	err := runSafe(runCtx, e.job.Run)
+	cancel()
+
+	res := Result{Status: entities.JobStatusSucceeded, StartedAt: started, FinishedAt: time.Now(), Err: err}
+	switch {
+	case err == nil:
+	case errors.Is(err, context.DeadlineExceeded) && errors.Is(runCtx.Err(), context.DeadlineExceeded):
+		res.Status = entities.JobStatusTimedOut
+	default:
+		res.Status = entities.JobStatusFailed
+	}
+
+	// El estado final se guarda aunque ctx se haya cancelado (apagado del worker)
+	storeCtx, storeCancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer storeCancel()
+	s.mu.Lock()
+	next := e.next
+	s.mu.Unlock()
+	if err := s.store.Finished(storeCtx, name, res, next); err != nil {
+		log.Error().Err(err).Str("job", name).Msg("job state update failed")
+	}
+	metrics.ObserveJob(name, res.Status, res.FinishedAt.Sub(started), res.Err == nil)
+
+	event := jobLog.Info()
+	if res.Err != nil {
+		event = jobLog.Error().Err(res.Err)
+	}
+	event.Str("reason", reason).Str("status", res.Status).
+		Dur("duration", res.FinishedAt.Sub(started)).Msg("job finished")
+}
+
+// runSafe ejecuta fn convirtiendo un panic en error
+func runSafe(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn(ctx)
+}
+
+func jitter(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(max)))
+}
+
+func timeoutString(d time.Duration) string {
+	if d <= 0 {
+		return ""
+	}
+	return d.String()
+}
@@ -0,0 +1,120 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/steven230500/hypeatlas-api/domain/entities"
+	"github.com/steven230500/hypeatlas-api/shared/db"
+)
+
+// Store persiste el estado de los jobs (app.job_states)
+type Store interface {
+	// Register crea o actualiza la fila del job sin tocar su historial
+	Register(ctx context.Context, name, schedule, timeout string, next time.Time) error
+	Started(ctx context.Context, name, owner string, at time.Time) error
+	Finished(ctx context.Context, name string, res Result, next time.Time) error
+	// ClaimTriggers devuelve los jobs de names con un pedido manual pendiente y lo limpia
+	ClaimTriggers(ctx context.Context, names []string) ([]string, error)
+	// RequestRun marca un pedido manual; false si el job no existe
+	RequestRun(ctx context.Context, name string) (bool, error)
+	States(ctx context.Context) ([]entities.JobState, error)
+	State(ctx context.Context, name string) (*entities.JobState, error)
+}
+
+// Result resultado de una ejecución
+type Result struct {
+	Status     string
+	StartedAt  time.Time
+	FinishedAt time.Time
+	Err        error
+}
+
+// GormStore Store sobre Postgres
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore crea un store de jobs sobre la base de datos
+func NewGormStore(g *gorm.DB) *GormStore {
+	return &GormStore{db: g}
+}
+
+func (s *GormStore) Register(ctx context.Context, name, schedule, timeout string, next time.Time) error {
+	row := entities.JobState{Name: name, Schedule: schedule, Timeout: timeout, NextRunAt: &next}
+	// running no se toca: con varias réplicas el job puede estar corriendo en otra. Si quedó
+	// en true por una réplica caída, lo corrige la siguiente ejecución.
+	return db.Call(s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "name"}},
+		DoUpdates: clause.Assignments(map[string]any{
+			"schedule":   schedule,
+			"timeout":    timeout,
+			"updated_at": time.Now(),
+		}),
+	}).Create(&row)).Error
+}
+
+func (s *GormStore) Started(ctx context.Context, name, owner string, at time.Time) error {
+	return db.Call(s.db.WithContext(ctx).Model(&entities.JobState{}).Where("name = ?", name).Updates(map[string]any{
+		"running":         true,
+		"owner":           owner,
+		"last_status":     entities.JobStatusRunning,
+		"last_started_at": at,
+	})).Error
+}
+
+func (s *GormStore) Finished(ctx context.Context, name string, res Result, next time.Time) error {
+	updates := map[string]any{
+		"running":          false,
+		"last_status":      res.Status,
+		"last_finished_at": res.FinishedAt,
+		"last_duration_ms": res.FinishedAt.Sub(res.StartedAt).Milliseconds(),
+		"last_error":       "",
+		"next_run_at":      next,
+		"runs":             gorm.Expr("runs + 1"),
+	}
+	if res.Err != nil {
+		updates["last_error"] = res.Err.Error()
+		updates["failures"] = gorm.Expr("failures + 1")
+	}
+	return db.Call(s.db.WithContext(ctx).Model(&entities.JobState{}).Where("name = ?", name).Updates(updates)).Error
+}
+
+// language=SQL
+const claimTriggersSQL = `
+UPDATE app.job_states SET trigger_requested_at = NULL, updated_at = now()
+WHERE trigger_requested_at IS NOT NULL AND name IN ?
+RETURNING name`
+
+func (s *GormStore) ClaimTriggers(ctx context.Context, names []string) ([]string, error) {
+	var claimed []string
+	err := db.Call(s.db.WithContext(ctx).Raw(claimTriggersSQL, names).Scan(&claimed)).Error
+	return claimed, err
+}
+
+func (s *GormStore) RequestRun(ctx context.Context, name string) (bool, error) {
+	result := db.Call(s.db.WithContext(ctx).Model(&entities.JobState{}).Where("name = ?", name).
+		Update("trigger_requested_at", time.Now()))
+	return result.RowsAffected > 0, result.Error
+}
+
+func (s *GormStore) States(ctx context.Context) ([]entities.JobState, error) {
+	var rows []entities.JobState
+	err := db.Call(s.db.WithContext(ctx).Order("name").Find(&rows)).Error
+	return rows, err
+}
+
+func (s *GormStore) State(ctx context.Context, name string) (*entities.JobState, error) {
+	var row entities.JobState
+	err := db.Call(s.db.WithContext(ctx).Where("name = ?", name).First(&row)).Error
+	if db.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
@@ -0,0 +1,126 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Percentile devuelve el percentil p (0-100) de valores ya ordenados, interpolando linealmente
+func Percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	if p <= 0 {
+		return sorted[0]
+	}
+	if p >= 100 {
+		return sorted[len(sorted)-1]
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	frac := rank - float64(lo)
+	return sorted[lo] + (sorted[hi]-sorted[lo])*frac
+}
+
+// Distribution resumen de una muestra
+type Distribution struct {
+	Count int     `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Mean  float64 `json:"mean"`
+	P10   float64 `json:"p10"`
+	P25   float64 `json:"p25"`
+	P50   float64 `json:"p50"`
+	P75   float64 `json:"p75"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+}
+
+// Describe calcula la distribución de una muestra (no modifica values)
+func Describe(values []float64) Distribution {
+	if len(values) == 0 {
+		return Distribution{}
+	}
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	sum := 0.0
+	for _, v := range sorted {
+		sum += v
+	}
+	return Distribution{
+		Count: len(sorted),
+		Min:   sorted[0],
+		Max:   sorted[len(sorted)-1],
+		Mean:  Round(sum/float64(len(sorted)), 2),
+		P10:   Round(Percentile(sorted, 10), 2),
+		P25:   Round(Percentile(sorted, 25), 2),
+		P50:   Round(Percentile(sorted, 50), 2),
+		P75:   Round(Percentile(sorted, 75), 2),
+		P90:   Round(Percentile(sorted, 90), 2),
+		P99:   Round(Percentile(sorted, 99), 2),
+	}
+}
+
+// Bucket intervalo [From, To) de un histograma
+type Bucket struct {
+	From  float64 `json:"from"`
+	To    float64 `json:"to"`
+	Count int     `json:"count"`
+}
+
+// Histogram agrupa valores en buckets contiguos de ancho width, del mínimo al máximo observado
+func Histogram(values []float64, width float64) []Bucket {
+	if len(values) == 0 || width <= 0 {
+		return nil
+	}
+	lo, hi := values[0], values[0]
+	for _, v := range values {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	start := math.Floor(lo/width) * width
+	n := int(math.Floor((hi-start)/width)) + 1
+
+	buckets := make([]Bucket, n)
+	for i := range buckets {
+		buckets[i].From = start + float64(i)*width
+		buckets[i].To = buckets[i].From + width
+	}
+	for _, v := range values {
+		buckets[int(math.Floor((v-start)/width))].Count++
+	}
+	return buckets
+}
+
+// Ratio devuelve part/total redondeado a 4 decimales (0 si total es 0)
+func Ratio(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return Round(float64(part)/float64(total), 4)
+}
+
+// Round redondea a n decimales
+func Round(v float64, n int) float64 {
+	p := math.Pow(10, float64(n))
+	return math.Round(v*p) / p
+}
+
+// Z95 cuantil normal para un intervalo de confianza del 95%
+const Z95 = 1.96
+
+// WilsonInterval intervalo de confianza de Wilson para wins/n (en [0,1]); (0,0) si n es 0
+func WilsonInterval(wins, n int, z float64) (float64, float64) {
+	if n <= 0 {
+		return 0, 0
+	}
+	nf := float64(n)
+	p := float64(wins) / nf
+	z2 := z * z
+	center := (p + z2/(2*nf)) / (1 + z2/nf)
+	margin := z * math.Sqrt(p*(1-p)/nf+z2/(4*nf*nf)) / (1 + z2/nf)
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
@@ -0,0 +1,57 @@
+// Package version compara versiones de parche ("14.14", "9.15", "14.14.602.1234").
+package version
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Compare compara dos versiones por segmentos numéricos: -1 si a < b, 0 si son iguales, 1 si a > b.
+// Los segmentos que faltan cuentan como 0 y los no numéricos se comparan como texto.
+func Compare(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := segment(as, i), segment(bs, i)
+		xn, errX := strconv.Atoi(x)
+		yn, errY := strconv.Atoi(y)
+		switch {
+		case errX == nil && errY == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// Sort ordena versiones de menor a mayor
+func Sort(versions []string) {
+	sort.SliceStable(versions, func(i, j int) bool { return Compare(versions[i], versions[j]) < 0 })
+}
+
+// Previous devuelve la versión inmediatamente anterior a v dentro de versions ("" si no hay)
+func Previous(versions []string, v string) string {
+	prev := ""
+	for _, candidate := range versions {
+		if Compare(candidate, v) < 0 && (prev == "" || Compare(candidate, prev) > 0) {
+			prev = candidate
+		}
+	}
+	return prev
+}
+
+func segment(parts []string, i int) string {
+	if i < len(parts) && parts[i] != "" {
+		return parts[i]
+	}
+	return "0"
+}
@@ -0,0 +1,59 @@
+package version
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"14.14", "14.14", 0},
+		{"14.9", "14.10", -1}, // numérico, no lexicográfico
+		{"14.10", "14.9", 1},
+		{"9.15", "14.1", -1},
+		{"14.14", "14.14.602.1234", -1},
+		{"14.14.0", "14.14", 0}, // los segmentos que faltan cuentan como 0
+		{"15.1", "14.24.1", 1},
+		{"10", "9", 1},
+		{"", "0", 0},
+		{"1.a", "1.b", -1}, // no numéricos: como texto
+		{"1.b", "1.a", 1},
+		{"release-09.01", "release-09.02", -1},
+		{"release-09.10", "release-09.9", 1},
+	}
+	for _, tt := range tests {
+		if got := Compare(tt.a, tt.b); got != tt.want {
+			t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	versions := []string{"14.10", "9.15", "14.9", "14.1", "13.24"}
+	Sort(versions)
+	want := []string{"9.15", "13.24", "14.1", "14.9", "14.10"}
+	if !reflect.DeepEqual(versions, want) {
+		t.Fatalf("Sort = %v, want %v", versions, want)
+	}
+}
+
+func TestPrevious(t *testing.T) {
+	versions := []string{"14.10", "14.8", "14.9", "13.24"}
+	tests := []struct {
+		v, want string
+	}{
+		{"14.10", "14.9"},
+		{"14.9", "14.8"},
+		{"14.8", "13.24"},
+		{"13.24", ""},
+		{"15.1", "14.10"}, // v no tiene que estar en la lista
+	}
+	for _, tt := range tests {
+		if got := Previous(versions, tt.v); got != tt.want {
+			t.Errorf("Previous(%q) = %q, want %q", tt.v, got, tt.want)
+		}
+	}
+}